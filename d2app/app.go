@@ -20,6 +20,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
@@ -146,6 +147,9 @@ func (p *App) initialize() error {
 		{"quit", "exits the game", p.quitGame},
 		{"screen-gui", "enters the gui playground screen", p.enterGuiPlayground},
 		{"js", "eval JS scripts", p.evalJS},
+		{"reloadstrings", "re-reads the string tables from their files", p.reloadStrings},
+		{"volume", "set the volume (0-1) of a sound category: master, music, effects or ui", p.setVolume},
+		{"bindkey", "bind an input action (e.g. ToggleInventory) to a key code", p.bindKey},
 	}
 
 	for idx := range terminalActions {
@@ -164,7 +168,17 @@ func (p *App) initialize() error {
 		return err
 	}
 
-	p.audio.SetVolumes(config.BgmVolume, config.SfxVolume)
+	for category := d2enum.SoundCategory(0); category < d2enum.SoundCategoryCount; category++ {
+		p.audio.SetCategoryVolume(category, config.Volume(category))
+	}
+
+	p.audio.SetMaxVoices(config.MaxSoundVoices)
+
+	if err := d2input.Bindings().Load(config.KeyBindings); err != nil {
+		log.Printf("failed to load key bindings: %v", err)
+	}
+
+	d2input.SetGamepadDeadzone(config.GamepadDeadzone)
 
 	if err := p.loadDataDict(); err != nil {
 		return err
@@ -182,22 +196,61 @@ func (p *App) initialize() error {
 }
 
 func (p *App) loadStrings() error {
-	tablePaths := []string{
+	tables, err := p.readStringTables()
+	if err != nil {
+		return err
+	}
+
+	for _, data := range tables {
+		d2common.LoadTextDictionary(data)
+	}
+
+	return nil
+}
+
+// stringTablePaths returns the paths of the string tables the game text is loaded from
+func stringTablePaths() []string {
+	return []string{
 		d2resource.PatchStringTable,
 		d2resource.ExpansionStringTable,
 		d2resource.StringTable,
 	}
+}
+
+func (p *App) readStringTables() ([][]byte, error) {
+	tablePaths := stringTablePaths()
 
-	for _, tablePath := range tablePaths {
+	tables := make([][]byte, len(tablePaths))
+
+	for i, tablePath := range tablePaths {
 		data, err := d2asset.LoadFile(tablePath)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		d2common.LoadTextDictionary(data)
+		tables[i] = data
 	}
 
-	return nil
+	return tables, nil
+}
+
+func (p *App) reloadStrings() {
+	// the cached files and the archives opened at startup would be read again, hiding any changes made to them
+	for _, tablePath := range stringTablePaths() {
+		if err := d2asset.ReloadFile(tablePath); err != nil {
+			p.terminal.OutputErrorf("could not reload string tables: %v", err)
+			return
+		}
+	}
+
+	tables, err := p.readStringTables()
+	if err != nil {
+		p.terminal.OutputErrorf("could not reload string tables: %v", err)
+		return
+	}
+
+	d2common.ReloadTextDictionaries(tables...)
+	p.terminal.OutputInfof("string tables reloaded")
 }
 
 func (p *App) loadDataDict() error {
@@ -215,11 +268,13 @@ func (p *App) loadDataDict() error {
 		{d2resource.Misc, d2datadict.LoadMiscItems},
 		{d2resource.UniqueItems, d2datadict.LoadUniqueItems},
 		{d2resource.Missiles, d2datadict.LoadMissiles},
+		{d2resource.Skills, d2datadict.LoadSkills},
 		{d2resource.SoundSettings, d2datadict.LoadSounds},
 		{d2resource.AnimationData, d2data.LoadAnimationData},
 		{d2resource.MonStats, d2datadict.LoadMonStats},
 		{d2resource.MonStats2, d2datadict.LoadMonStats2},
 		{d2resource.MonPreset, d2datadict.LoadMonPresets},
+		{d2resource.ItemTypes, d2datadict.LoadItemTypes},
 		{d2resource.MagicPrefix, d2datadict.LoadMagicPrefix},
 		{d2resource.MagicSuffix, d2datadict.LoadMagicSuffix},
 		{d2resource.ItemStatCost, d2datadict.LoadItemStatCosts},
@@ -235,6 +290,7 @@ func (p *App) loadDataDict() error {
 		{d2resource.CubeRecipes, d2datadict.LoadCubeRecipes},
 		{d2resource.SuperUniques, d2datadict.LoadSuperUniques},
 		{d2resource.Inventory, d2datadict.LoadInventory},
+		{d2resource.TreasureClassEx, d2datadict.LoadTreasureClasses},
 	}
 
 	d2datadict.InitObjectRecords()
@@ -418,6 +474,10 @@ func (p *App) advance(elapsed, current float64) error {
 		return err
 	}
 
+	if err := p.audio.Advance(elapsed); err != nil {
+		return err
+	}
+
 	if err := d2gui.Advance(elapsed); err != nil {
 		return err
 	}
@@ -525,6 +585,55 @@ func (p *App) setTimeScale(timeScale float64) {
 	}
 }
 
+func (p *App) setVolume(categoryName string, level float64) {
+	categories := map[string]d2enum.SoundCategory{
+		"master":  d2enum.SoundCategoryMaster,
+		"music":   d2enum.SoundCategoryMusic,
+		"effects": d2enum.SoundCategoryEffects,
+		"ui":      d2enum.SoundCategoryUI,
+	}
+
+	category, ok := categories[strings.ToLower(categoryName)]
+	if !ok || level < 0 || level > 1 {
+		p.terminal.OutputErrorf("invalid volume, expected a category (master, music, effects or ui) and a level (0-1)")
+		return
+	}
+
+	p.audio.SetCategoryVolume(category, level)
+	d2config.Config.SetVolume(category, level)
+
+	if err := d2config.Config.Save(); err != nil {
+		p.terminal.OutputErrorf("failed to save volume: %v", err)
+		return
+	}
+
+	p.terminal.OutputInfof("%s volume is now: %v", categoryName, level)
+}
+
+func (p *App) bindKey(actionName string, key int) {
+	action, ok := d2enum.InputActionFromString(actionName)
+	if !ok {
+		p.terminal.OutputErrorf("unknown input action: %s", actionName)
+		return
+	}
+
+	bindings := d2input.Bindings()
+
+	if err := bindings.Rebind(action, d2enum.Key(key)); err != nil {
+		p.terminal.OutputErrorf("failed to bind %s: %v", actionName, err)
+		return
+	}
+
+	d2config.Config.KeyBindings = bindings.Save()
+
+	if err := d2config.Config.Save(); err != nil {
+		p.terminal.OutputErrorf("failed to save key bindings: %v", err)
+		return
+	}
+
+	p.terminal.OutputInfof("%s is now bound to key %d", actionName, key)
+}
+
 func (p *App) quitGame() {
 	os.Exit(0)
 }
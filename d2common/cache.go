@@ -132,6 +132,32 @@ func (c *Cache) Retrieve(key string) (interface{}, bool) {
 	return node.value, true
 }
 
+// Remove removes an object from the cache, if it is cached
+func (c *Cache) Remove(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	node, found := c.lookup[key]
+	if !found {
+		return
+	}
+
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		c.head = node.next
+	}
+
+	if node.next != nil {
+		node.next.prev = node.prev
+	} else {
+		c.tail = node.prev
+	}
+
+	delete(c.lookup, key)
+	c.weight -= node.weight
+}
+
 // Clear removes all cache entries
 func (c *Cache) Clear() {
 	c.mutex.Lock()
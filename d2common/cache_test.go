@@ -0,0 +1,36 @@
+package d2common
+
+import (
+	"testing"
+)
+
+func TestCacheRemove(t *testing.T) {
+	cache := CreateCache(10)
+
+	for _, key := range []string{"first", "middle", "last"} {
+		if err := cache.Insert(key, key, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, key := range []string{"middle", "first", "last", "missing"} {
+		cache.Remove(key)
+
+		if _, found := cache.Retrieve(key); found {
+			t.Errorf("%s: wanted removed from the cache", key)
+		}
+	}
+
+	if weight := cache.GetWeight(); weight != 0 {
+		t.Errorf("weight after removing every entry: wanted %d: got %d", 0, weight)
+	}
+
+	// the cache still works once emptied
+	if err := cache.Insert("first", "again", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, found := cache.Retrieve("first"); !found || value != "again" {
+		t.Errorf("entry inserted after removal: wanted %v: got %v", "again", value)
+	}
+}
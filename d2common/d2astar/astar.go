@@ -2,9 +2,13 @@ package d2astar
 
 import (
 	"container/heap"
+	"context"
 	"sync"
 )
 
+// contextCheckInterval is the number of iterations between checks for a cancelled search.
+const contextCheckInterval = 64
+
 var nodePool *sync.Pool
 var nodeMapPool *sync.Pool
 var priorityQueuePool *sync.Pool
@@ -80,9 +84,37 @@ func (nm nodeMap) get(p Pather) *node {
 	return n
 }
 
+// Options configures a path search.
+type Options struct {
+	// MaxCost is the maximum cost of a path, nodes beyond it are not searched.
+	MaxCost float64
+	// MaxIterations is the maximum number of nodes expanded before the search
+	// gives up and returns the closest partial path. 0 means no limit.
+	MaxIterations int
+	// CostScale returns the multiplier applied to the cost of moving onto a
+	// node, for example 2 for mud. When nil every node has a multiplier of 1.
+	CostScale func(to Pather) float64
+}
+
 // Path calculates a short path and the distance between the two Pather nodes.
 // If no path is found, found will be false and path will be the closest node to the target with a valid path.
 func Path(from, to Pather, maxCost float64) (path []Pather, distance float64, found bool) {
+	path, distance, found, _ = PathWithOptions(from, to, Options{MaxCost: maxCost})
+	return path, distance, found
+}
+
+// PathWithOptions calculates a short path and the distance between the two Pather nodes, as configured by opts.
+// If no path is found, found will be false and path will be the closest node to the target with a valid path.
+// If the search was stopped by MaxIterations, truncated will be true.
+func PathWithOptions(from, to Pather, opts Options) (path []Pather, distance float64, found, truncated bool) {
+	path, distance, found, truncated, _ = PathContext(context.Background(), from, to, opts)
+	return path, distance, found, truncated
+}
+
+// PathContext calculates a path like PathWithOptions, but stops searching and returns the context's error if the
+// context is cancelled.
+func PathContext(ctx context.Context, from, to Pather, opts Options) (path []Pather, distance float64,
+	found, truncated bool, err error) {
 	nm := nodeMapPool.Get().(nodeMap)
 	nq := priorityQueuePool.Get().(priorityQueue)
 
@@ -104,12 +136,26 @@ func Path(from, to Pather, maxCost float64) (path []Pather, distance float64, fo
 	fromNode := nm.get(from)
 	fromNode.open = true
 	heap.Push(&nq, fromNode)
+	iterations := 0
+
 	for {
 		if nq.Len() == 0 {
 			// There's no path, fallback to closest.
 			break
 		}
 
+		if opts.MaxIterations > 0 && iterations >= opts.MaxIterations {
+			// Searched for too long, fallback to closest.
+			truncated = true
+			break
+		}
+
+		if iterations%contextCheckInterval == 0 && ctx.Err() != nil {
+			return nil, 0, false, false, ctx.Err()
+		}
+
+		iterations++
+
 		current := heap.Pop(&nq).(*node)
 
 		current.open = false
@@ -123,12 +169,12 @@ func Path(from, to Pather, maxCost float64) (path []Pather, distance float64, fo
 				p = append(p, curr.pather)
 				curr = curr.parent
 			}
-			return p, current.cost, true
+			return p, current.cost, true, false, nil
 		}
 
 		for _, neighbor := range current.pather.PathNeighbors() {
-			cost := current.cost + current.pather.PathNeighborCost(neighbor)
-			if cost > maxCost {
+			cost := current.cost + opts.neighborCost(current.pather, neighbor)
+			if cost > opts.MaxCost {
 				// Out of range, tweak maxCost if this is cutting off too soon.
 				continue
 			}
@@ -172,5 +218,49 @@ func Path(from, to Pather, maxCost float64) (path []Pather, distance float64, fo
 		p = append(p, curr.pather)
 		curr = curr.parent
 	}
-	return p, closestNode.cost, false
+	return p, closestNode.cost, false, truncated, nil
+}
+
+// PathResult is the result of an asynchronous path search.
+type PathResult struct {
+	Path      []Pather
+	Distance  float64
+	Found     bool
+	Truncated bool
+	Err       error
+}
+
+// PathAsync calculates a path like PathWithOptions on a separate goroutine. The result is delivered on the returned
+// channel, unless the context is cancelled first, in which case nothing is delivered.
+func PathAsync(ctx context.Context, from, to Pather, opts Options) <-chan PathResult {
+	results := make(chan PathResult, 1)
+
+	go func() {
+		path, distance, found, truncated, err := PathContext(ctx, from, to, opts)
+		if ctx.Err() != nil {
+			// The request is stale, drop it.
+			return
+		}
+
+		results <- PathResult{
+			Path:      path,
+			Distance:  distance,
+			Found:     found,
+			Truncated: truncated,
+			Err:       err,
+		}
+	}()
+
+	return results
+}
+
+// neighborCost returns the cost of moving between two neighboring nodes, scaled by CostScale.
+func (opts *Options) neighborCost(from, to Pather) float64 {
+	cost := from.PathNeighborCost(to)
+
+	if opts.CostScale != nil {
+		cost *= opts.CostScale(to)
+	}
+
+	return cost
 }
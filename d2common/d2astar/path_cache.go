@@ -0,0 +1,129 @@
+package d2astar
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultPathCacheSize is the default number of paths held by a PathCache.
+const DefaultPathCacheSize = 128
+
+// pathCacheKey identifies a cached path.
+type pathCacheKey struct {
+	from, to Pather
+	version  uint64
+}
+
+// pathCacheEntry is a cached path search result.
+type pathCacheEntry struct {
+	key      pathCacheKey
+	path     []Pather
+	distance float64
+	found    bool
+}
+
+// PathCache is a least recently used cache of paths between two nodes.
+// Entries are keyed on a version number as well as the nodes, so the cache
+// can be invalidated by changing the version whenever the graph changes.
+type PathCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[pathCacheKey]*list.Element
+	order   *list.List
+}
+
+// NewPathCache creates a path cache holding at most size paths.
+func NewPathCache(size int) *PathCache {
+	return &PathCache{
+		size:    size,
+		entries: make(map[pathCacheKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// SetSize sets the maximum number of paths held by the cache, evicting the
+// least recently used paths if required.
+func (c *PathCache) SetSize(size int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.size = size
+	c.evict()
+}
+
+// Len returns the number of paths held by the cache.
+func (c *PathCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+// Path returns the path between the two nodes for the given graph version,
+// searching for it with PathWithOptions if it is not cached. Truncated
+// searches are not cached. The returned path is a copy which the caller may
+// modify.
+func (c *PathCache) Path(from, to Pather, version uint64, opts Options) (path []Pather, distance float64,
+	found, truncated bool) {
+	key := pathCacheKey{from: from, to: to, version: version}
+
+	if entry, ok := c.get(key); ok {
+		return copyPath(entry.path), entry.distance, entry.found, false
+	}
+
+	path, distance, found, truncated = PathWithOptions(from, to, opts)
+	if !truncated {
+		c.put(&pathCacheEntry{key: key, path: copyPath(path), distance: distance, found: found})
+	}
+
+	return path, distance, found, truncated
+}
+
+func (c *PathCache) get(key pathCacheKey) (*pathCacheEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return element.Value.(*pathCacheEntry), true
+}
+
+func (c *PathCache) put(entry *pathCacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[entry.key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[entry.key] = c.order.PushFront(entry)
+	c.evict()
+}
+
+// evict removes the least recently used paths until the cache fits its size.
+func (c *PathCache) evict() {
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*pathCacheEntry).key)
+	}
+}
+
+func copyPath(path []Pather) []Pather {
+	if path == nil {
+		return nil
+	}
+
+	result := make([]Pather, len(path))
+	copy(result, path)
+
+	return result
+}
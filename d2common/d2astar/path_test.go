@@ -5,8 +5,10 @@ package d2astar
 // what we're expecting.
 
 import (
+	"context"
 	"math"
 	"testing"
+	"time"
 )
 
 // testPath takes a string encoded world, decodes it, calculates a path and
@@ -134,3 +136,160 @@ F............................~.................................................
 		Path(world.From(), world.To(), math.MaxFloat64)
 	}
 }
+
+// TestCostScale checks that a cheap detour is preferred over a straight line
+// which has been made expensive by the cost scale.
+func TestCostScale(t *testing.T) {
+	world := ParseWorld(`
+............
+.F........T.
+............
+`)
+
+	opts := Options{
+		MaxCost: math.MaxFloat64,
+		CostScale: func(to Pather) float64 {
+			if tile := to.(*Tile); tile.Y == 1 && tile.X > 1 && tile.X < 10 {
+				return 5
+			}
+
+			return 1
+		},
+	}
+
+	p, dist, found, _ := PathWithOptions(world.From(), world.To(), opts)
+	if !found {
+		t.Fatal("Could not find a path")
+	}
+
+	t.Logf("Resulting path\n%s", world.RenderPath(p))
+
+	if dist != 11 {
+		t.Fatalf("Expected dist to be %v but got %v", 11, dist)
+	}
+
+	_, uniformDist, _, _ := PathWithOptions(world.From(), world.To(), Options{MaxCost: math.MaxFloat64})
+	if uniformDist != 9 {
+		t.Fatalf("Expected uniform dist to be %v but got %v", 9, uniformDist)
+	}
+}
+
+// TestMaxIterations checks that the search for a walled off goal stops after
+// the maximum number of iterations and reports that it was truncated.
+func TestMaxIterations(t *testing.T) {
+	world := ParseWorld(`
+..........................
+..........................
+.F...................XXX..
+.....................XTX..
+.....................XXX..
+..........................
+..........................
+`)
+
+	const maxIterations = 20
+
+	neighborCosts := 0
+
+	opts := Options{
+		MaxCost:       math.MaxFloat64,
+		MaxIterations: maxIterations,
+		CostScale: func(to Pather) float64 {
+			neighborCosts++
+			return 1
+		},
+	}
+
+	p, _, found, truncated := PathWithOptions(world.From(), world.To(), opts)
+	if found {
+		t.Fatal("Found a path to a walled off goal")
+	}
+
+	if !truncated {
+		t.Fatal("Expected the search to be truncated")
+	}
+
+	if len(p) < 2 {
+		t.Fatal("Expected a partial path toward the goal")
+	}
+
+	// every expanded node has at most 4 neighbors
+	if neighborCosts > maxIterations*4 {
+		t.Fatalf("Expected at most %v neighbor evaluations but got %v", maxIterations*4, neighborCosts)
+	}
+}
+
+// TestPathCache checks that a second identical request is answered from the
+// cache without searching, and that changing the version searches again.
+func TestPathCache(t *testing.T) {
+	world := ParseWorld(`
+............
+.F........T.
+............
+`)
+
+	neighborCosts := 0
+
+	opts := Options{
+		MaxCost: math.MaxFloat64,
+		CostScale: func(to Pather) float64 {
+			neighborCosts++
+			return 1
+		},
+	}
+
+	cache := NewPathCache(DefaultPathCacheSize)
+
+	first, _, _, _ := cache.Path(world.From(), world.To(), 0, opts)
+	searched := neighborCosts
+
+	second, dist, found, _ := cache.Path(world.From(), world.To(), 0, opts)
+	if neighborCosts != searched {
+		t.Fatalf("Expected a cached path but the search expanded %v more nodes", neighborCosts-searched)
+	}
+
+	if !found || dist != 9 || len(second) != len(first) {
+		t.Fatalf("Expected the cached path to match the searched path")
+	}
+
+	second[0] = nil
+	if third, _, _, _ := cache.Path(world.From(), world.To(), 0, opts); third[0] == nil {
+		t.Fatal("Expected the cache to return a copy of the path")
+	}
+
+	cache.Path(world.From(), world.To(), 1, opts)
+
+	if neighborCosts == searched {
+		t.Fatal("Expected a new version to search again")
+	}
+}
+
+// TestPathAsync checks that an asynchronous search delivers its result, and
+// that a cancelled search never delivers.
+func TestPathAsync(t *testing.T) {
+	world := ParseWorld(`
+............
+.F........T.
+............
+`)
+
+	opts := Options{MaxCost: math.MaxFloat64}
+
+	select {
+	case result := <-PathAsync(context.Background(), world.From(), world.To(), opts):
+		if !result.Found || result.Distance != 9 {
+			t.Fatalf("Expected dist to be %v but got %v", 9, result.Distance)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the search to deliver a result")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	select {
+	case <-PathAsync(ctx, world.From(), world.To(), opts):
+		t.Fatal("Expected a cancelled search not to deliver a result")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
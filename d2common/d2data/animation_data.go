@@ -19,9 +19,38 @@ type AnimationDataRecord struct {
 	Flags []byte
 }
 
+const (
+	// DefaultAnimationSpeed is the speed of animations without animation data, advancing a frame every 25FPS tick
+	DefaultAnimationSpeed = 256
+
+	animationTicksPerSecond = 25.0
+	animationSpeedDivisor   = 256.0
+)
+
+// AnimationFrameDuration returns the number of seconds each frame is shown for at an animation speed
+func AnimationFrameDuration(speed int) float64 {
+	return 1.0 / ((float64(speed) * animationTicksPerSecond) / animationSpeedDivisor)
+}
+
+// FrameDuration returns the number of seconds each frame of the animation is shown for
+func (r *AnimationDataRecord) FrameDuration() float64 {
+	return AnimationFrameDuration(r.AnimationSpeed)
+}
+
 // AnimationData represents all of the animation data records, mapped by the COF index
 var AnimationData map[string][]*AnimationDataRecord //nolint:gochecknoglobals // Currently global by design
 
+// GetAnimationData returns the animation data record of the animation token in a mode and weapon class, for example
+// GetAnimationData("PL", "NU", "HTH"). It returns false if there's no record for the animation.
+func GetAnimationData(token, mode, weaponClass string) (*AnimationDataRecord, bool) {
+	records := AnimationData[strings.ToLower(token+mode+weaponClass)]
+	if len(records) == 0 {
+		return nil, false
+	}
+
+	return records[0], true
+}
+
 // LoadAnimationData loads the animation data table into the global AnimationData dictionary
 func LoadAnimationData(rawData []byte) {
 	AnimationData = make(map[string][]*AnimationDataRecord)
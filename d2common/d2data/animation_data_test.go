@@ -0,0 +1,59 @@
+package d2data
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+func testAnimationData() []byte {
+	records := []struct {
+		cofName                   string
+		framesPerDirection, speed uint32
+	}{
+		{"PLNUHTH", 8, 256},
+		{"PLRNHTH", 8, 128},
+	}
+
+	sw := d2common.CreateStreamWriter()
+	sw.PushUint32(uint32(len(records)))
+
+	for _, record := range records {
+		name := make([]byte, 8)
+		copy(name, record.cofName)
+
+		for _, b := range name {
+			sw.PushByte(b)
+		}
+
+		sw.PushUint32(record.framesPerDirection)
+		sw.PushUint32(record.speed)
+
+		for i := 0; i < 144; i++ {
+			sw.PushByte(0)
+		}
+	}
+
+	return sw.GetBytes()
+}
+
+func TestGetAnimationData(t *testing.T) {
+	LoadAnimationData(testAnimationData())
+
+	record, found := GetAnimationData("PL", "RN", "HTH")
+	if !found {
+		t.Fatal("animation data not found")
+	}
+
+	if record.FramesPerDirection != 8 {
+		t.Errorf("frames per direction: wanted %d: got %d", 8, record.FramesPerDirection)
+	}
+
+	if duration := record.FrameDuration(); duration != 0.08 {
+		t.Errorf("frame duration: wanted %v: got %v", 0.08, duration)
+	}
+
+	if _, found := GetAnimationData("ZZ", "NU", "HTH"); found {
+		t.Error("found animation data of an unknown token")
+	}
+}
@@ -35,6 +35,8 @@ type CharStatsRecord struct {
 	ManaPerEne    int // mana per point of energy
 	StaminaPerVit int // stamina per point of vitality
 
+	LifeAdd int // life added to the initial vitality at character level 1
+
 	StatPerLevel int // amount of stat points per level
 
 	BlockFactor int // added to base shield block% in armor.txt (display & calc)
@@ -58,15 +60,12 @@ type CharStatsRecord struct {
 }
 
 // CharStats holds all of the CharStatsRecords
-//nolint:gochecknoglobals // Currently global by design, only written once
-var CharStats map[d2enum.Hero]*CharStatsRecord
+var CharStats map[d2enum.Hero]*CharStatsRecord   //nolint:gochecknoglobals // Currently global by design, only written once
 var charStringMap map[string]d2enum.Hero         //nolint:gochecknoglobals // Currently global by design
 var weaponTokenMap map[string]d2enum.WeaponClass //nolint:gochecknoglobals // Currently global by design
 
 // LoadCharStats loads charstats.txt file contents into map[d2enum.Hero]*CharStatsRecord
-//nolint:funlen // Makes no sense to split
-// LoadCharStats loads charstats.txt file contents into map[d2enum.Hero]*CharStatsRecord
-func LoadCharStats(file []byte) {
+func LoadCharStats(file []byte) { //nolint:funlen // Makes no sense to split
 	CharStats = make(map[d2enum.Hero]*CharStatsRecord)
 
 	charStringMap = map[string]d2enum.Hero{
@@ -123,6 +122,8 @@ func LoadCharStats(file []byte) {
 			ManaPerEne:    d.Number("ManaPerMagic"),
 			StaminaPerVit: d.Number("StaminaPerVitality"),
 
+			LifeAdd: d.Number("hpadd"),
+
 			StatPerLevel: d.Number("StatPerLevel"),
 			BlockFactor:  d.Number("BlockFactor"),
 
@@ -124,6 +124,7 @@ func createItemAffixRecords(d *d2common.DataDictionary, superType d2enum.ItemAff
 
 		group := ItemAffixGroups[affix.GroupID]
 		group.addMember(affix)
+		affix.Group = group
 
 		records = append(records, affix)
 	}
@@ -0,0 +1,71 @@
+package d2datadict
+
+import (
+	"log"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// maxItemTypeDepth limits how far up the item type hierarchy is searched
+const maxItemTypeDepth = 16
+
+// ItemTypeRecord is a representation of a row from itemtypes.txt, which
+// arranges the item types into groups, such as gloves being armor
+type ItemTypeRecord struct {
+	Name   string // called `ItemType` in itemtypes.txt
+	Code   string
+	Equiv1 string // the code of a parent type
+	Equiv2 string // the code of a second parent type
+}
+
+// ItemTypes stores all of the ItemTypeRecords by code
+var ItemTypes map[string]*ItemTypeRecord //nolint:gochecknoglobals // Currently global by design, only written once
+
+// LoadItemTypes loads item type records from itemtypes.txt
+func LoadItemTypes(file []byte) {
+	ItemTypes = make(map[string]*ItemTypeRecord)
+
+	d := d2common.LoadDataDictionary(file)
+	for d.Next() {
+		record := &ItemTypeRecord{
+			Name:   d.String("ItemType"),
+			Code:   d.String("Code"),
+			Equiv1: d.String("Equiv1"),
+			Equiv2: d.String("Equiv2"),
+		}
+
+		if record.Code == "" {
+			continue
+		}
+
+		ItemTypes[record.Code] = record
+	}
+
+	if d.Err != nil {
+		panic(d.Err)
+	}
+
+	log.Printf("Loaded %d ItemType records", len(ItemTypes))
+}
+
+// ItemTypeIsA returns true if the item type is the given group, or belongs to it
+func ItemTypeIsA(code, group string) bool {
+	return itemTypeIsA(code, group, 0)
+}
+
+func itemTypeIsA(code, group string, depth int) bool {
+	if code == "" || depth > maxItemTypeDepth {
+		return false
+	}
+
+	if code == group {
+		return true
+	}
+
+	record, found := ItemTypes[code]
+	if !found {
+		return false
+	}
+
+	return itemTypeIsA(record.Equiv1, group, depth+1) || itemTypeIsA(record.Equiv2, group, depth+1)
+}
@@ -0,0 +1,163 @@
+package d2datadict
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// one in magicAffixSlotChance magic items has only a prefix, one in
+// magicAffixSlotChance only a suffix, and the rest have both
+const magicAffixSlotChance = 4
+
+var errNoMagicAffixes = errors.New("no magic affixes can spawn on the item")
+
+// affixGroupKey identifies an affix group, prefix and suffix group ids are separate
+type affixGroupKey struct {
+	isPrefix bool
+	groupID  int
+}
+
+// MagicItemModifier is a modifier of a magic affix, with its value rolled
+type MagicItemModifier struct {
+	Code      string
+	Parameter int
+	Value     int
+}
+
+// MagicItem is a base item with a magic prefix and/or suffix
+type MagicItem struct {
+	Base      *ItemCommonRecord
+	Prefix    *ItemAffixCommonRecord
+	Suffix    *ItemAffixCommonRecord
+	Name      string
+	Modifiers []MagicItemModifier
+}
+
+// GenerateMagicItem rolls the magic affixes of an item with the given item
+// level. Only affixes which can spawn at the item level, on the base item's
+// type, are picked, weighted by their frequency.
+func GenerateMagicItem(baseItem *ItemCommonRecord, ilvl int, rng *rand.Rand) (*MagicItem, error) {
+	item := &MagicItem{Base: baseItem}
+	usedGroups := make(map[affixGroupKey]bool)
+
+	slots := rng.Intn(magicAffixSlotChance)
+
+	if slots != 1 {
+		item.Prefix = pickMagicAffix(MagicPrefix, baseItem, ilvl, usedGroups, rng)
+	}
+
+	if slots != 0 {
+		item.Suffix = pickMagicAffix(MagicSuffix, baseItem, ilvl, usedGroups, rng)
+	}
+
+	if item.Prefix == nil && item.Suffix == nil {
+		return nil, errNoMagicAffixes
+	}
+
+	names := make([]string, 0)
+
+	for _, affix := range []*ItemAffixCommonRecord{item.Prefix, item.Suffix} {
+		if affix != nil {
+			item.Modifiers = append(item.Modifiers, rollAffixModifiers(affix, rng)...)
+		}
+	}
+
+	if item.Prefix != nil {
+		names = append(names, d2common.TranslateString(item.Prefix.Name))
+	}
+
+	names = append(names, d2common.TranslateString(baseItem.Name))
+
+	if item.Suffix != nil {
+		names = append(names, d2common.TranslateString(item.Suffix.Name))
+	}
+
+	item.Name = strings.Join(names, " ")
+
+	return item, nil
+}
+
+// CanSpawnOn returns true if the affix can spawn on the item, at the given item level
+func (a *ItemAffixCommonRecord) CanSpawnOn(item *ItemCommonRecord, ilvl int) bool {
+	if !a.Spawnable || a.Frequency <= 0 || ilvl < a.Level || (a.MaxLevel > 0 && ilvl > a.MaxLevel) {
+		return false
+	}
+
+	isItemType := func(group string) bool {
+		return group != "" && (ItemTypeIsA(item.Type, group) || ItemTypeIsA(item.Type2, group))
+	}
+
+	for _, group := range a.ItemExclude {
+		if isItemType(group) {
+			return false
+		}
+	}
+
+	for _, group := range a.ItemInclude {
+		if isItemType(group) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pickMagicAffix picks an affix which can spawn on the item, weighted by
+// frequency, from a group which hasn't been used yet
+func pickMagicAffix(affixes []*ItemAffixCommonRecord, item *ItemCommonRecord, ilvl int, usedGroups map[affixGroupKey]bool,
+	rng *rand.Rand) *ItemAffixCommonRecord {
+	candidates := make([]*ItemAffixCommonRecord, 0)
+	total := 0
+
+	for _, affix := range affixes {
+		if usedGroups[affixGroupKey{affix.IsPrefix, affix.GroupID}] || !affix.CanSpawnOn(item, ilvl) {
+			continue
+		}
+
+		candidates = append(candidates, affix)
+		total += affix.Frequency
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	roll := rng.Intn(total)
+
+	for _, affix := range candidates {
+		if roll < affix.Frequency {
+			usedGroups[affixGroupKey{affix.IsPrefix, affix.GroupID}] = true
+			return affix
+		}
+
+		roll -= affix.Frequency
+	}
+
+	return nil
+}
+
+func rollAffixModifiers(affix *ItemAffixCommonRecord, rng *rand.Rand) []MagicItemModifier {
+	modifiers := make([]MagicItemModifier, 0, len(affix.Modifiers))
+
+	for _, modifier := range affix.Modifiers {
+		if modifier.Code == "" {
+			continue
+		}
+
+		value := modifier.Min
+		if modifier.Max > modifier.Min {
+			value += rng.Intn(modifier.Max - modifier.Min + 1)
+		}
+
+		modifiers = append(modifiers, MagicItemModifier{
+			Code:      modifier.Code,
+			Parameter: modifier.Parameter,
+			Value:     value,
+		})
+	}
+
+	return modifiers
+}
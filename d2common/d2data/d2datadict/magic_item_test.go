@@ -0,0 +1,116 @@
+package d2datadict
+
+import (
+	"math/rand"
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func loadMagicItemFixtures() {
+	LoadItemTypes(joinTxtRows([][]string{
+		{"ItemType", "Code", "Equiv1", "Equiv2"},
+		{"Armor", "armo", "", ""},
+		{"Gloves", "glov", "armo", ""},
+		{"Weapon", "weap", "", ""},
+	}))
+
+	affixHeader := []string{"Name", "spawnable", "level", "maxlevel", "frequency", "group",
+		"mod1code", "mod1min", "mod1max", "itype1", "itype2", "etype1"}
+
+	LoadMagicPrefix(joinTxtRows([][]string{
+		affixHeader,
+		{"Sharp", "1", "1", "", "3", "1", "dmg%", "10", "20", "weap", "", ""},
+		{"Red", "1", "1", "", "2", "2", "dmg-fire", "1", "3", "armo", "weap", ""},
+		{"Blue", "1", "1", "", "2", "2", "dmg-cold", "1", "3", "armo", "", ""},
+		{"Glowing", "1", "30", "", "5", "3", "light", "1", "1", "armo", "", ""},
+		{"Plated", "0", "1", "", "5", "4", "ac", "1", "1", "armo", "", ""},
+	}))
+
+	LoadMagicSuffix(joinTxtRows([][]string{
+		affixHeader,
+		{"of the Fox", "1", "1", "", "3", "1", "hp", "1", "5", "armo", "", "glov"},
+		{"of the Jackal", "1", "1", "", "3", "1", "hp", "1", "5", "armo", "", ""},
+		{"of Dexterity", "1", "1", "", "3", "2", "dex", "1", "2", "glov", "", ""},
+	}))
+}
+
+func TestGenerateMagicItem(t *testing.T) {
+	assert := testify.New(t)
+
+	loadMagicItemFixtures()
+
+	gloves := &ItemCommonRecord{Name: "Gauntlets", Code: "hgl", Type: "glov"}
+
+	const seed = 7
+
+	first, err := GenerateMagicItem(gloves, 5, rand.New(rand.NewSource(seed)))
+	if !assert.NoError(err) {
+		return
+	}
+
+	second, err := GenerateMagicItem(gloves, 5, rand.New(rand.NewSource(seed)))
+	if !assert.NoError(err) {
+		return
+	}
+
+	assert.Equal(first, second)
+	assert.True(first.Prefix != nil || first.Suffix != nil)
+
+	validPrefixes := []string{"Red", "Blue"}
+	validSuffixes := []string{"of the Jackal", "of Dexterity"}
+	expectedName := "Gauntlets"
+
+	if first.Prefix != nil {
+		assert.Contains(validPrefixes, first.Prefix.Name)
+		expectedName = first.Prefix.Name + " " + expectedName
+	}
+
+	if first.Suffix != nil {
+		assert.Contains(validSuffixes, first.Suffix.Name)
+		expectedName += " " + first.Suffix.Name
+	}
+
+	assert.Equal(expectedName, first.Name)
+
+	for _, modifier := range first.Modifiers {
+		assert.True(modifier.Value >= 1 && modifier.Value <= 3, modifier.Code)
+	}
+}
+
+func TestMagicAffixCanSpawnOn(t *testing.T) {
+	assert := testify.New(t)
+
+	loadMagicItemFixtures()
+
+	gloves := &ItemCommonRecord{Name: "Gauntlets", Type: "glov"}
+	affixes := make(map[string]*ItemAffixCommonRecord)
+
+	for _, affix := range append(MagicPrefix, MagicSuffix...) {
+		affixes[affix.Name] = affix
+	}
+
+	assert.True(affixes["Red"].CanSpawnOn(gloves, 1))
+	assert.False(affixes["Sharp"].CanSpawnOn(gloves, 1), "wrong item type")
+	assert.False(affixes["Glowing"].CanSpawnOn(gloves, 29), "item level too low")
+	assert.True(affixes["Glowing"].CanSpawnOn(gloves, 30))
+	assert.False(affixes["Plated"].CanSpawnOn(gloves, 1), "not spawnable")
+	assert.False(affixes["of the Fox"].CanSpawnOn(gloves, 1), "excluded item type")
+}
+
+func TestPickMagicAffixGroups(t *testing.T) {
+	assert := testify.New(t)
+
+	loadMagicItemFixtures()
+
+	gloves := &ItemCommonRecord{Name: "Gauntlets", Type: "glov"}
+	usedGroups := make(map[affixGroupKey]bool)
+	rng := rand.New(rand.NewSource(1))
+
+	first := pickMagicAffix(MagicPrefix, gloves, 1, usedGroups, rng)
+	second := pickMagicAffix(MagicPrefix, gloves, 1, usedGroups, rng)
+
+	// Red and Blue are the only prefixes which can spawn, and share a group
+	assert.NotNil(first)
+	assert.Nil(second)
+}
@@ -686,6 +686,7 @@ var MonStats map[string]*MonStatsRecord //nolint:gochecknoglobals // Currently g
 // LoadMonStats loads monstats
 func LoadMonStats(file []byte) { // nolint:funlen // Makes no sense to split
 	MonStats = make(map[string]*MonStatsRecord)
+	records := make([]*MonStatsRecord, 0)
 
 	d := d2common.LoadDataDictionary(file)
 	for d.Next() {
@@ -944,11 +945,14 @@ func LoadMonStats(file []byte) { // nolint:funlen // Makes no sense to split
 			SpecialClientEnd:               d.Number("SplClientEnd") > 0,
 		}
 		MonStats[record.Key] = record
+		records = append(records, record)
 	}
 
 	if d.Err != nil {
 		panic(d.Err)
 	}
 
+	indexMonStatsByNameKey(records)
+
 	log.Printf("Loaded %d MonStats records", len(MonStats))
 }
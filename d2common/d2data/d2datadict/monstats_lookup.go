@@ -0,0 +1,60 @@
+package d2datadict
+
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+//nolint:gochecknoglobals // Currently global by design, only written once
+var monStatsByNameKey map[string]*MonStatsRecord
+
+// GetMonStatsByID returns the MonStatsRecord with the given id, which is
+// called `Id` in monstats.txt, or nil if there is none
+func GetMonStatsByID(id string) *MonStatsRecord {
+	return MonStats[id]
+}
+
+// GetMonStatsByNameKey returns the first MonStatsRecord with the given name
+// string table key, or nil if there is none
+func GetMonStatsByNameKey(nameKey string) *MonStatsRecord {
+	return monStatsByNameKey[nameKey]
+}
+
+func indexMonStatsByNameKey(records []*MonStatsRecord) {
+	monStatsByNameKey = make(map[string]*MonStatsRecord, len(records))
+
+	for _, record := range records {
+		if _, exists := monStatsByNameKey[record.NameStringTableKey]; !exists {
+			monStatsByNameKey[record.NameStringTableKey] = record
+		}
+	}
+}
+
+// Level returns the monster's level on the given difficulty
+func (r *MonStatsRecord) Level(difficulty d2enum.DifficultyType) int {
+	return pickDifficulty(difficulty, r.LevelNormal, r.LevelNightmare, r.LevelHell)
+}
+
+// HitPoints returns the range of the monster's base hit points on the given difficulty
+func (r *MonStatsRecord) HitPoints(difficulty d2enum.DifficultyType) (minHP, maxHP int) {
+	return pickDifficulty(difficulty, r.MinHPNormal, r.MinHPNightmare, r.MinHPHell),
+		pickDifficulty(difficulty, r.MaxHPNormal, r.MaxHPNightmare, r.MaxHPHell)
+}
+
+// ArmorClass returns the monster's defense on the given difficulty
+func (r *MonStatsRecord) ArmorClass(difficulty d2enum.DifficultyType) int {
+	return pickDifficulty(difficulty, r.ArmorClassNormal, r.ArmorClassNightmare, r.ArmorClassHell)
+}
+
+// Experience returns the experience given for killing the monster on the given difficulty
+func (r *MonStatsRecord) Experience(difficulty d2enum.DifficultyType) int {
+	return pickDifficulty(difficulty, r.ExperienceNormal, r.ExperienceNightmare, r.ExperienceHell)
+}
+
+func pickDifficulty(difficulty d2enum.DifficultyType, normal, nightmare, hell int) int {
+	switch difficulty {
+	case d2enum.DifficultyNightmare:
+		return nightmare
+	case d2enum.DifficultyHell:
+		return hell
+	default:
+		return normal
+	}
+}
@@ -0,0 +1,54 @@
+package d2datadict
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func monStatsFixture() []byte {
+	rows := [][]string{
+		{"Id", "hcIdx", "NameStr", "Code", "Velocity", "Run", "Level", "Level(N)", "Level(H)",
+			"minHP", "maxHP", "MinHP(N)", "MaxHP(N)", "MinHP(H)", "MaxHP(H)", "AC", "AC(N)", "AC(H)", "Exp", "Exp(N)", "Exp(H)"},
+		{"zombie1", "4", "Zombie", "ZM", "3", "0", "1", "37", "68",
+			"7", "13", "158", "228", "315", "441", "5", "145", "235", "23", "548", "1728"},
+		{"zombie2", "5", "HungryDead", "ZM", "3", "0", "6", "39", "71",
+			"14", "18", "167", "249", "325", "486", "8", "158", "255", "61", "616", "1863"},
+		{"Expansion"},
+	}
+
+	return joinTxtRows(rows)
+}
+
+func TestLoadMonStats(t *testing.T) {
+	assert := testify.New(t)
+
+	LoadMonStats(monStatsFixture())
+
+	assert.Len(MonStats, 2)
+
+	zombie := GetMonStatsByID("zombie1")
+	if !assert.NotNil(zombie) {
+		return
+	}
+
+	assert.Equal(3, zombie.SpeedBase)
+	assert.Equal("ZM", zombie.AnimationDirectoryToken)
+
+	minHP, maxHP := zombie.HitPoints(d2enum.DifficultyNormal)
+	assert.Equal(7, minHP)
+	assert.Equal(13, maxHP)
+
+	minHP, maxHP = zombie.HitPoints(d2enum.DifficultyHell)
+	assert.Equal(315, minHP)
+	assert.Equal(441, maxHP)
+
+	assert.Equal(37, zombie.Level(d2enum.DifficultyNightmare))
+	assert.Equal(145, zombie.ArmorClass(d2enum.DifficultyNightmare))
+	assert.Equal(1728, zombie.Experience(d2enum.DifficultyHell))
+
+	assert.Equal(GetMonStatsByID("zombie2"), GetMonStatsByNameKey("HungryDead"))
+	assert.Nil(GetMonStatsByNameKey("Fallen"))
+}
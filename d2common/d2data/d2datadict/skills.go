@@ -0,0 +1,148 @@
+package d2datadict
+
+import (
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+const (
+	skillLevelRanges = 5
+	skillParams      = 8
+
+	// the mana columns are in 1/256ths of a point, scaled by manashift
+	skillManaPrecision = 256
+)
+
+// SkillDamage is the damage of a skill, and how it grows with the skill level
+type SkillDamage struct {
+	Min int
+	Max int
+	// MinPerLevel and MaxPerLevel are the damage added per skill level, for
+	// levels 2-8, 9-16, 17-22, 23-28 and 29+
+	MinPerLevel [skillLevelRanges]int
+	MaxPerLevel [skillLevelRanges]int
+}
+
+// SkillRecord is a representation of a row from skills.txt
+type SkillRecord struct {
+	Skill     string // the skill's name, used as a key by other txt files
+	ID        int
+	CharClass string // the class code of the character which can learn the skill, empty for monster skills
+	SkillDesc string // key into skilldesc.txt, which has the skill's display strings
+
+	RequiredLevel int
+	MaxLevel      int
+
+	// Animation tokens used when the skill is cast
+	Anim      string // player animation mode
+	SeqTrans  string // player animation mode used for sequences
+	MonAnim   string // monster animation mode
+	SeqNum    int    // sequence to play, for skills such as Zeal
+	SeqInput  int
+	Range     string // melee, ranged, both or none
+	UseManaOn int    // the frame the mana is taken on
+
+	Mana      int // base mana cost
+	LevelMana int // mana cost added per skill level
+	MinMana   int // minimum mana cost
+	ManaShift int // mana precision, the cost is scaled by 2^ManaShift/256
+
+	ToHit      int
+	LevelToHit int
+
+	Damage          SkillDamage
+	ElementType     string
+	ElementalDamage SkillDamage
+	ElementalLength int
+	ElementalLevLen [3]int
+
+	Params [skillParams]int
+}
+
+// ManaCost returns the mana cost of casting the skill at the given skill level
+func (r *SkillRecord) ManaCost(level int) float64 {
+	cost := float64(r.Mana+r.LevelMana*(level-1)) * math.Pow(2, float64(r.ManaShift)) / skillManaPrecision
+
+	return math.Max(cost, float64(r.MinMana))
+}
+
+// Skills stores all of the SkillRecords
+var Skills map[int]*SkillRecord //nolint:gochecknoglobals // Currently global by design, only written once
+
+// GetSkillByID returns the skill record with the given id, or nil if there is none
+func GetSkillByID(id int) *SkillRecord {
+	return Skills[id]
+}
+
+// LoadSkills loads skill records from skills.txt into a map keyed by id
+func LoadSkills(file []byte) {
+	Skills = make(map[int]*SkillRecord)
+
+	d := d2common.LoadDataDictionary(file)
+	for d.Next() {
+		// skip blank separator rows
+		if d.String("skill") == "" {
+			continue
+		}
+
+		record := &SkillRecord{
+			Skill:           d.String("skill"),
+			ID:              d.Number("Id"),
+			CharClass:       d.String("charclass"),
+			SkillDesc:       d.String("skilldesc"),
+			RequiredLevel:   d.Number("reqlevel"),
+			MaxLevel:        d.Number("maxlvl"),
+			Anim:            d.String("anim"),
+			SeqTrans:        d.String("seqtrans"),
+			MonAnim:         d.String("monanim"),
+			SeqNum:          d.Number("seqnum"),
+			SeqInput:        d.Number("seqinput"),
+			Range:           d.String("range"),
+			UseManaOn:       d.Number("UseManaOnDo"),
+			Mana:            d.Number("mana"),
+			LevelMana:       d.Number("lvlmana"),
+			MinMana:         d.Number("minmana"),
+			ManaShift:       d.Number("manashift"),
+			ToHit:           d.Number("ToHit"),
+			LevelToHit:      d.Number("LevToHit"),
+			Damage:          loadSkillDamage(d, "MinDam", "MaxDam", "MinLevDam", "MaxLevDam"),
+			ElementType:     d.String("EType"),
+			ElementalDamage: loadSkillDamage(d, "EMin", "EMax", "EMinLev", "EMaxLev"),
+			ElementalLength: d.Number("ELen"),
+			ElementalLevLen: [3]int{
+				d.Number("ELevLen1"),
+				d.Number("ELevLen2"),
+				d.Number("ELevLen3"),
+			},
+		}
+
+		for i := range record.Params {
+			record.Params[i] = d.Number("Param" + strconv.Itoa(i+1))
+		}
+
+		Skills[record.ID] = record
+	}
+
+	if d.Err != nil {
+		panic(d.Err)
+	}
+
+	log.Printf("Loaded %d Skill records", len(Skills))
+}
+
+func loadSkillDamage(d *d2common.DataDictionary, minField, maxField, minLevelField, maxLevelField string) SkillDamage {
+	damage := SkillDamage{
+		Min: d.Number(minField),
+		Max: d.Number(maxField),
+	}
+
+	for i := 0; i < skillLevelRanges; i++ {
+		damage.MinPerLevel[i] = d.Number(minLevelField + strconv.Itoa(i+1))
+		damage.MaxPerLevel[i] = d.Number(maxLevelField + strconv.Itoa(i+1))
+	}
+
+	return damage
+}
@@ -0,0 +1,45 @@
+package d2datadict
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func skillsFixture() []byte {
+	rows := [][]string{
+		{"skill", "Id", "charclass", "skilldesc", "reqlevel", "maxlvl", "anim", "mana", "lvlmana", "minmana", "manashift",
+			"MinDam", "MaxDam", "EType", "EMin", "EMax", "EMinLev1", "EMaxLev1"},
+		{"Attack", "0", "", "attack", "1", "1", "A1", "0", "0", "0", "0", "", "", "", "", "", "", ""},
+		{"Expansion"},
+		{""},
+		{"Fire Bolt", "36", "sor", "fire bolt", "1", "20", "SC", "5", "0", "0", "7", "", "", "fire", "3", "6", "1", "2"},
+	}
+
+	return joinTxtRows(rows)
+}
+
+func TestLoadSkills(t *testing.T) {
+	assert := testify.New(t)
+
+	LoadSkills(skillsFixture())
+
+	assert.Len(Skills, 2)
+
+	fireBolt := GetSkillByID(36)
+	if !assert.NotNil(fireBolt) {
+		return
+	}
+
+	assert.Equal("Fire Bolt", fireBolt.Skill)
+	assert.Equal("fire bolt", fireBolt.SkillDesc)
+	assert.Equal("sor", fireBolt.CharClass)
+	assert.Equal("SC", fireBolt.Anim)
+	assert.Equal(20, fireBolt.MaxLevel)
+	assert.Equal("fire", fireBolt.ElementType)
+	assert.Equal(3, fireBolt.ElementalDamage.Min)
+	assert.Equal(2, fireBolt.ElementalDamage.MaxPerLevel[0])
+	assert.Equal(2.5, fireBolt.ManaCost(1))
+
+	assert.Nil(GetSkillByID(1))
+}
@@ -0,0 +1,190 @@
+package d2datadict
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"strconv"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+const (
+	treasureClassItems = 10
+
+	// maxTreasureClassDepth limits how deeply treasure classes can reference
+	// each other, the deepest chains in the game data are much shallower
+	maxTreasureClassDepth = 32
+)
+
+var (
+	errTreasureClassNotFound  = errors.New("treasure class not found")
+	errTreasureClassRecursion = errors.New("treasure class references itself")
+	errTreasureClassTooDeep   = errors.New("treasure class references are nested too deeply")
+)
+
+// TreasureClassItem is an item code or a treasure class reference, with
+// its weight in the drop table
+type TreasureClassItem struct {
+	Name        string
+	Probability int
+}
+
+// TreasureClassRecord is a representation of a row from treasureclassex.txt,
+// a table of weighted item drops
+type TreasureClassRecord struct {
+	Name  string // called `Treasure Class` in treasureclassex.txt
+	Group int
+	Level int
+
+	// Picks is the number of items picked from the table. A negative value
+	// drops each item in order as many times as its probability, until the
+	// absolute value of Picks items have dropped.
+	Picks int
+
+	// Quality ratios, used to upgrade the dropped items
+	Unique int
+	Set    int
+	Rare   int
+	Magic  int
+
+	NoDrop int // the weight of dropping nothing
+	Items  []TreasureClassItem
+}
+
+// TreasureClasses stores all of the TreasureClassRecords by name
+var TreasureClasses map[string]*TreasureClassRecord //nolint:gochecknoglobals // Currently global by design, only written once
+
+// LoadTreasureClasses loads treasure class records from treasureclassex.txt
+func LoadTreasureClasses(file []byte) {
+	TreasureClasses = make(map[string]*TreasureClassRecord)
+
+	d := d2common.LoadDataDictionary(file)
+	for d.Next() {
+		record := &TreasureClassRecord{
+			Name:   d.String("Treasure Class"),
+			Group:  d.Number("group"),
+			Level:  d.Number("level"),
+			Picks:  d.Number("Picks"),
+			Unique: d.Number("Unique"),
+			Set:    d.Number("Set"),
+			Rare:   d.Number("Rare"),
+			Magic:  d.Number("Magic"),
+			NoDrop: d.Number("NoDrop"),
+		}
+
+		if record.Name == "" {
+			continue
+		}
+
+		for i := 1; i <= treasureClassItems; i++ {
+			name := d.String("Item" + strconv.Itoa(i))
+			if name == "" {
+				continue
+			}
+
+			record.Items = append(record.Items, TreasureClassItem{
+				Name:        name,
+				Probability: d.Number("Prob" + strconv.Itoa(i)),
+			})
+		}
+
+		TreasureClasses[record.Name] = record
+	}
+
+	if d.Err != nil {
+		panic(d.Err)
+	}
+
+	log.Printf("Loaded %d TreasureClass records", len(TreasureClasses))
+}
+
+// RollTreasureClass rolls the drops of the named treasure class with the
+// given random source. Rolls which pick another treasure class roll that
+// class in turn, so the result only holds item codes. It is empty if
+// nothing dropped.
+func RollTreasureClass(tcName string, rng *rand.Rand) ([]string, error) {
+	record, found := TreasureClasses[tcName]
+	if !found {
+		return nil, errTreasureClassNotFound
+	}
+
+	return record.roll(rng, nil)
+}
+
+func (r *TreasureClassRecord) roll(rng *rand.Rand, parents []string) ([]string, error) {
+	if len(parents) >= maxTreasureClassDepth {
+		return nil, errTreasureClassTooDeep
+	}
+
+	for _, parent := range parents {
+		if parent == r.Name {
+			return nil, errTreasureClassRecursion
+		}
+	}
+
+	parents = append(parents, r.Name)
+	drops := make([]string, 0)
+
+	for _, pick := range r.picks(rng) {
+		nested, isClass := TreasureClasses[pick]
+		if !isClass {
+			drops = append(drops, pick)
+			continue
+		}
+
+		nestedDrops, err := nested.roll(rng, parents)
+		if err != nil {
+			return nil, err
+		}
+
+		drops = append(drops, nestedDrops...)
+	}
+
+	return drops, nil
+}
+
+// picks returns the names picked from the table, skipping the no drops
+func (r *TreasureClassRecord) picks(rng *rand.Rand) []string {
+	picks := make([]string, 0)
+
+	if r.Picks < 0 {
+		remaining := -r.Picks
+
+		for _, item := range r.Items {
+			for i := 0; i < item.Probability && remaining > 0; i++ {
+				picks = append(picks, item.Name)
+				remaining--
+			}
+		}
+
+		return picks
+	}
+
+	total := r.NoDrop
+	for _, item := range r.Items {
+		total += item.Probability
+	}
+
+	if total <= 0 {
+		return picks
+	}
+
+	for i := 0; i < r.Picks; i++ {
+		roll := rng.Intn(total) - r.NoDrop
+		if roll < 0 {
+			continue
+		}
+
+		for _, item := range r.Items {
+			if roll < item.Probability {
+				picks = append(picks, item.Name)
+				break
+			}
+
+			roll -= item.Probability
+		}
+	}
+
+	return picks
+}
@@ -0,0 +1,79 @@
+package d2datadict
+
+import (
+	"math/rand"
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func treasureClassFixture() []byte {
+	rows := [][]string{
+		{"Treasure Class", "group", "level", "Picks", "Unique", "Set", "Rare", "Magic", "NoDrop",
+			"Item1", "Prob1", "Item2", "Prob2", "Item3", "Prob3"},
+		{"Act 1 Junk", "", "", "1", "", "", "", "", "", "hp1", "2", "mp1", "2", "gld", "1"},
+		{"Act 1 Equip A", "", "", "1", "", "", "", "", "", "cap", "1", "lbt", "1", "", ""},
+		{"Cow", "", "", "3", "", "", "", "", "5", "Act 1 Junk", "10", "Act 1 Equip A", "5", "", ""},
+		{"Chest", "", "", "-3", "", "", "", "", "", "gld", "2", "Act 1 Junk", "2", "", ""},
+		{"Loop A", "", "", "1", "", "", "", "", "", "Loop B", "1", "", "", "", ""},
+		{"Loop B", "", "", "1", "", "", "", "", "", "Loop A", "1", "", "", "", ""},
+	}
+
+	return joinTxtRows(rows)
+}
+
+func TestRollTreasureClassDeterministic(t *testing.T) {
+	assert := testify.New(t)
+
+	LoadTreasureClasses(treasureClassFixture())
+
+	if !assert.Len(TreasureClasses, 6) {
+		return
+	}
+
+	assert.Equal(5, TreasureClasses["Cow"].NoDrop)
+	assert.Len(TreasureClasses["Act 1 Equip A"].Items, 2)
+
+	const seed = 42
+
+	first, err := RollTreasureClass("Cow", rand.New(rand.NewSource(seed)))
+	assert.NoError(err)
+
+	second, err := RollTreasureClass("Cow", rand.New(rand.NewSource(seed)))
+	assert.NoError(err)
+
+	assert.Equal(first, second)
+	assert.True(len(first) <= 3)
+
+	valid := []string{"hp1", "mp1", "gld", "cap", "lbt"}
+	for _, drop := range first {
+		assert.Contains(valid, drop)
+	}
+}
+
+func TestRollTreasureClassNegativePicks(t *testing.T) {
+	assert := testify.New(t)
+
+	LoadTreasureClasses(treasureClassFixture())
+
+	drops, err := RollTreasureClass("Chest", rand.New(rand.NewSource(1)))
+	assert.NoError(err)
+
+	if assert.Len(drops, 3) {
+		assert.Equal("gld", drops[0])
+		assert.Equal("gld", drops[1])
+		assert.Contains([]string{"hp1", "mp1", "gld"}, drops[2])
+	}
+}
+
+func TestRollTreasureClassErrors(t *testing.T) {
+	assert := testify.New(t)
+
+	LoadTreasureClasses(treasureClassFixture())
+
+	_, err := RollTreasureClass("Loop A", rand.New(rand.NewSource(1)))
+	assert.Equal(errTreasureClassRecursion, err)
+
+	_, err = RollTreasureClass("Missing", rand.New(rand.NewSource(1)))
+	assert.Equal(errTreasureClassNotFound, err)
+}
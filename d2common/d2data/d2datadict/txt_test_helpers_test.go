@@ -0,0 +1,13 @@
+package d2datadict
+
+import "strings"
+
+// joinTxtRows builds a tab separated txt file from the given rows
+func joinTxtRows(rows [][]string) []byte {
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		lines[i] = strings.Join(row, "\t")
+	}
+
+	return []byte(strings.Join(lines, "\r\n") + "\r\n")
+}
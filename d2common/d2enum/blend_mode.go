@@ -0,0 +1,16 @@
+package d2enum
+
+// BlendMode determines how drawn pixels are combined with the pixels already on a surface.
+type BlendMode int
+
+const (
+	// BlendModeNormal draws pixels over the surface, blended by their alpha
+	BlendModeNormal BlendMode = iota
+
+	// BlendModeAdditive adds the drawn colors to the surface, brightening it, for glows and auras
+	BlendModeAdditive
+
+	// BlendModeMultiply multiplies the surface colors by the drawn colors, darkening it, for shadows and tints. The
+	// ebiten renderer multiplies by the brightness of the drawn colors, without their hue.
+	BlendModeMultiply
+)
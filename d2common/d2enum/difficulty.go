@@ -0,0 +1,11 @@
+package d2enum
+
+// DifficultyType is a game difficulty
+type DifficultyType int
+
+// Difficulty types
+const (
+	DifficultyNormal DifficultyType = iota
+	DifficultyNightmare
+	DifficultyHell
+)
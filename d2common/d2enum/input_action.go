@@ -0,0 +1,68 @@
+package d2enum
+
+// InputAction is a game action which can be bound to a key.
+type InputAction int
+
+// Input actions
+const (
+	InputActionMoveUp InputAction = iota
+	InputActionMoveDown
+	InputActionMoveLeft
+	InputActionMoveRight
+	InputActionToggleInventory
+	InputActionToggleCharacter
+	InputActionToggleAutomap
+	InputActionToggleRun
+	InputActionSkill1
+	InputActionSkill2
+	InputActionSkill3
+	InputActionSkill4
+	InputActionSkill5
+	InputActionSkill6
+	InputActionSkill7
+	InputActionSkill8
+
+	// InputActionCount is the number of input actions
+	InputActionCount
+)
+
+//nolint:gochecknoglobals // Currently global by design, treated as a constant
+var inputActionNames = [InputActionCount]string{
+	InputActionMoveUp:          "MoveUp",
+	InputActionMoveDown:        "MoveDown",
+	InputActionMoveLeft:        "MoveLeft",
+	InputActionMoveRight:       "MoveRight",
+	InputActionToggleInventory: "ToggleInventory",
+	InputActionToggleCharacter: "ToggleCharacter",
+	InputActionToggleAutomap:   "ToggleAutomap",
+	InputActionToggleRun:       "ToggleRun",
+	InputActionSkill1:          "Skill1",
+	InputActionSkill2:          "Skill2",
+	InputActionSkill3:          "Skill3",
+	InputActionSkill4:          "Skill4",
+	InputActionSkill5:          "Skill5",
+	InputActionSkill6:          "Skill6",
+	InputActionSkill7:          "Skill7",
+	InputActionSkill8:          "Skill8",
+}
+
+// String returns the name of the action, as used in the configuration file.
+func (a InputAction) String() string {
+	if a < 0 || a >= InputActionCount {
+		return ""
+	}
+
+	return inputActionNames[a]
+}
+
+// InputActionFromString returns the action with the given name. The second return value is false if there is no
+// action with that name.
+func InputActionFromString(name string) (InputAction, bool) {
+	for action := InputAction(0); action < InputActionCount; action++ {
+		if inputActionNames[action] == name {
+			return action, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,47 @@
+package d2enum
+
+// GamepadButton represents a button on a gamepad with a standard (XInput style) layout
+type GamepadButton int
+
+const (
+	// GamepadButtonA is the bottom face button
+	GamepadButtonA GamepadButton = iota
+	// GamepadButtonB is the right face button
+	GamepadButtonB
+	// GamepadButtonX is the left face button
+	GamepadButtonX
+	// GamepadButtonY is the top face button
+	GamepadButtonY
+	// GamepadButtonLeftShoulder is the left shoulder button
+	GamepadButtonLeftShoulder
+	// GamepadButtonRightShoulder is the right shoulder button
+	GamepadButtonRightShoulder
+	// GamepadButtonBack is the back (or select) button
+	GamepadButtonBack
+	// GamepadButtonStart is the start button
+	GamepadButtonStart
+	// GamepadButtonLeftStick is pressing the left stick
+	GamepadButtonLeftStick
+	// GamepadButtonRightStick is pressing the right stick
+	GamepadButtonRightStick
+
+	// GamepadButtonMin is the lowest GamepadButton
+	GamepadButtonMin = GamepadButtonA
+	// GamepadButtonMax is the highest GamepadButton
+	GamepadButtonMax = GamepadButtonRightStick
+)
+
+// GamepadStick represents an analog stick on a gamepad
+type GamepadStick int
+
+const (
+	// GamepadStickLeft is the left analog stick
+	GamepadStickLeft GamepadStick = iota
+	// GamepadStickRight is the right analog stick
+	GamepadStickRight
+
+	// GamepadStickMin is the lowest GamepadStick
+	GamepadStickMin = GamepadStickLeft
+	// GamepadStickMax is the highest GamepadStick
+	GamepadStickMax = GamepadStickRight
+)
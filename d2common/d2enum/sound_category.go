@@ -0,0 +1,16 @@
+package d2enum
+
+// SoundCategory is a group of sounds which share a volume setting
+type SoundCategory int
+
+// Sound categories
+const (
+	// SoundCategoryMaster scales the volume of every other category
+	SoundCategoryMaster SoundCategory = iota
+	SoundCategoryMusic
+	SoundCategoryEffects
+	SoundCategoryUI
+
+	// SoundCategoryCount is the number of sound categories
+	SoundCategoryCount
+)
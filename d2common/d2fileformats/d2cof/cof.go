@@ -74,3 +74,17 @@ func Load(fileData []byte) (*COF, error) {
 
 	return result, nil
 }
+
+// LayerOrder returns the composite layers of a frame of the given direction in the order they are drawn, from back
+// to front. It returns nil if the direction or frame is out of range.
+func (c *COF) LayerOrder(direction, frame int) []d2enum.CompositeType {
+	if direction < 0 || direction >= len(c.Priority) {
+		return nil
+	}
+
+	if frame < 0 || frame >= len(c.Priority[direction]) {
+		return nil
+	}
+
+	return c.Priority[direction][frame]
+}
@@ -0,0 +1,58 @@
+package d2cof
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// testCOF returns a COF with a torso and shield layer and a single frame in
+// two directions, drawing the shield behind the torso in the first direction
+// and in front of it in the second.
+func testCOF() []byte {
+	data := []byte{2, 1, 2} // Layers, frames per direction, directions
+	data = append(data, make([]byte, 21)...)
+	data = append(data, 8) // Speed
+	data = append(data, make([]byte, 3)...)
+
+	for _, layerType := range []d2enum.CompositeType{d2enum.CompositeTypeTorso, d2enum.CompositeTypeShield} {
+		data = append(data, byte(layerType), 1, 1, 0, 0, 'h', 't', 'h', 0)
+	}
+
+	data = append(data, 0) // Animation frames
+
+	torso, shield := byte(d2enum.CompositeTypeTorso), byte(d2enum.CompositeTypeShield)
+	data = append(data, shield, torso, torso, shield)
+
+	return data
+}
+
+func TestLayerOrder(t *testing.T) {
+	cof, err := Load(testCOF())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wanted := [][]d2enum.CompositeType{
+		{d2enum.CompositeTypeShield, d2enum.CompositeTypeTorso},
+		{d2enum.CompositeTypeTorso, d2enum.CompositeTypeShield},
+	}
+
+	for direction := range wanted {
+		order := cof.LayerOrder(direction, 0)
+		if len(order) != len(wanted[direction]) {
+			t.Fatalf("direction %d layer order: wanted %v: got %v", direction, wanted[direction], order)
+		}
+
+		for i := range order {
+			if order[i] != wanted[direction][i] {
+				t.Errorf("direction %d layer order: wanted %v: got %v", direction, wanted[direction], order)
+				break
+			}
+		}
+	}
+
+	if order := cof.LayerOrder(2, 0); order != nil {
+		t.Errorf("out of range direction: wanted no layers: got %v", order)
+	}
+}
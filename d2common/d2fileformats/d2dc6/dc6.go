@@ -14,6 +14,8 @@ type DC6 struct {
 	FramesPerDirection uint32
 	FramePointers      []uint32    // size is Directions*FramesPerDirection
 	Frames             []*DC6Frame // size is Directions*FramesPerDirection
+
+	cache *frameCache
 }
 
 // Load uses restruct to read the binary dc6 data into structs then parses image data from the frame data.
@@ -26,6 +28,7 @@ func Load(data []byte) (*DC6, error) {
 	r := d2common.CreateStreamReader(data)
 
 	var dc DC6
+	dc.cache = newFrameCache(DefaultFrameCacheSize)
 	dc.Version = r.GetInt32()
 	dc.Flags = r.GetUInt32()
 	dc.Encoding = r.GetUInt32()
@@ -60,8 +63,45 @@ func Load(data []byte) (*DC6, error) {
 	return &dc, nil
 }
 
-// DecodeFrame decodes the given frame to an indexed color texture
+// SetFrameCacheSize sets the maximum number of decoded frames held in memory,
+// releasing the least recently used frames if required.
+func (d *DC6) SetFrameCacheSize(size int) {
+	d.frameCache().setSize(size)
+}
+
+// DecodeFrame decodes the given frame to an indexed color texture. Decoded
+// frames are cached, so the returned data must not be modified.
 func (d *DC6) DecodeFrame(frameIndex int) []byte {
+	framesPerDirection := int(d.FramesPerDirection)
+	if framesPerDirection == 0 {
+		framesPerDirection = 1
+	}
+
+	return d.DecodeDirectionFrame(frameIndex/framesPerDirection, frameIndex%framesPerDirection)
+}
+
+// DecodeDirectionFrame decodes the given frame of a direction to an indexed
+// color texture. Decoded frames are cached, so the returned data must not be
+// modified.
+func (d *DC6) DecodeDirectionFrame(direction, frame int) []byte {
+	key := frameCacheKey{direction: direction, frame: frame}
+
+	return d.frameCache().get(key, func() []byte {
+		return d.decodeFrame(direction*int(d.FramesPerDirection) + frame)
+	})
+}
+
+// frameCache returns the cache of decoded frames, creating it for a DC6 which
+// wasn't created by Load.
+func (d *DC6) frameCache() *frameCache {
+	if d.cache == nil {
+		d.cache = newFrameCache(DefaultFrameCacheSize)
+	}
+
+	return d.cache
+}
+
+func (d *DC6) decodeFrame(frameIndex int) []byte {
 	frame := d.Frames[frameIndex]
 
 	indexData := make([]byte, frame.Width*frame.Height)
@@ -0,0 +1,77 @@
+package d2dc6
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultFrameCacheSize is the default number of decoded frames held by a DC6.
+const DefaultFrameCacheSize = 32
+
+// frameCacheKey identifies a decoded frame.
+type frameCacheKey struct {
+	direction, frame int
+}
+
+// frameCacheEntry is a decoded frame.
+type frameCacheEntry struct {
+	key       frameCacheKey
+	indexData []byte
+}
+
+// frameCache is a least recently used cache of decoded frames, so the
+// frames of idle animations are released once others are decoded.
+type frameCache struct {
+	mutex   sync.Mutex
+	size    int
+	entries map[frameCacheKey]*list.Element
+	order   *list.List
+
+	// decodes counts the frames decoded on cache misses
+	decodes int
+}
+
+func newFrameCache(size int) *frameCache {
+	return &frameCache{
+		size:    size,
+		entries: make(map[frameCacheKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the decoded frame, calling decode and caching the result if it
+// is not cached.
+func (c *frameCache) get(key frameCacheKey, decode func() []byte) []byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*frameCacheEntry).indexData
+	}
+
+	indexData := decode()
+	c.decodes++
+
+	c.entries[key] = c.order.PushFront(&frameCacheEntry{key: key, indexData: indexData})
+	c.evict()
+
+	return indexData
+}
+
+func (c *frameCache) setSize(size int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.size = size
+	c.evict()
+}
+
+// evict removes the least recently used frames until the cache fits its size.
+func (c *frameCache) evict() {
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*frameCacheEntry).key)
+	}
+}
@@ -0,0 +1,70 @@
+package d2dc6
+
+import (
+	"testing"
+)
+
+// testDC6 returns a DC6 with the given number of 2x2 frames, each filled with
+// its frame index.
+func testDC6(directions, framesPerDirection int) *DC6 {
+	dc := &DC6{
+		Directions:         uint32(directions),
+		FramesPerDirection: uint32(framesPerDirection),
+	}
+
+	for i := 0; i < directions*framesPerDirection; i++ {
+		index := byte(i)
+		dc.Frames = append(dc.Frames, &DC6Frame{
+			Width:     2,
+			Height:    2,
+			FrameData: []byte{2, index, index, 0x80, 2, index, index, 0x80},
+		})
+	}
+
+	return dc
+}
+
+func TestDecodeFrameCached(t *testing.T) {
+	dc := testDC6(2, 3)
+
+	first := dc.DecodeFrame(4)
+	second := dc.DecodeFrame(4)
+
+	if dc.cache.decodes != 1 {
+		t.Errorf("decodes: wanted %d: got %d", 1, dc.cache.decodes)
+	}
+
+	if &first[0] != &second[0] {
+		t.Error("second decode didn't reuse the cached frame")
+	}
+
+	for i, index := range first {
+		if index != 4 {
+			t.Errorf("pixel %d: wanted %d: got %d", i, 4, index)
+		}
+	}
+
+	if direction := dc.DecodeDirectionFrame(1, 1); &direction[0] != &first[0] {
+		t.Error("decoding by direction and frame didn't reuse the cached frame")
+	}
+}
+
+func TestDecodeFrameEviction(t *testing.T) {
+	dc := testDC6(1, 3)
+	dc.SetFrameCacheSize(2)
+
+	dc.DecodeFrame(0)
+	dc.DecodeFrame(1)
+	dc.DecodeFrame(2)
+
+	if dc.cache.order.Len() != 2 {
+		t.Errorf("cached frames: wanted %d: got %d", 2, dc.cache.order.Len())
+	}
+
+	// Frame 0 was the least recently used, so decoding it again misses
+	dc.DecodeFrame(0)
+
+	if dc.cache.decodes != 4 {
+		t.Errorf("decodes: wanted %d: got %d", 4, dc.cache.decodes)
+	}
+}
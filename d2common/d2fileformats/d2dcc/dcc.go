@@ -17,6 +17,7 @@ type DCC struct {
 	FramesPerDirection int
 	directionOffsets   []int
 	fileData           []byte
+	directions         []*DCCDirection // decoded directions, nil until decoded by Direction
 }
 
 // Load loads a DCC file.
@@ -44,6 +45,7 @@ func Load(fileData []byte) (*DCC, error) {
 	bm.GetInt32() // TotalSizeCoded
 
 	result.directionOffsets = make([]int, result.NumberOfDirections)
+	result.directions = make([]*DCCDirection, result.NumberOfDirections)
 
 	for i := 0; i < result.NumberOfDirections; i++ {
 		result.directionOffsets[i] = int(bm.GetInt32())
@@ -57,3 +59,29 @@ func (dcc *DCC) DecodeDirection(direction int) *DCCDirection {
 	return CreateDCCDirection(d2common.CreateBitMuncher(dcc.fileData,
 		dcc.directionOffsets[direction]*directionOffsetMultiplier), dcc)
 }
+
+// Direction returns the given direction, decoding it the first time it is
+// requested. The other directions are not decoded.
+func (dcc *DCC) Direction(direction int) *DCCDirection {
+	if dcc.directions[direction] == nil {
+		dcc.directions[direction] = dcc.DecodeDirection(direction)
+	}
+
+	return dcc.directions[direction]
+}
+
+// IsDirectionDecoded returns true if the given direction has been decoded by
+// Direction and not freed since.
+func (dcc *DCC) IsDirectionDecoded(direction int) bool {
+	return dcc.directions[direction] != nil
+}
+
+// FreeDirectionsExcept frees the decoded frames of every direction other than
+// the given one, for files of which only one direction is shown.
+func (dcc *DCC) FreeDirectionsExcept(direction int) {
+	for i := range dcc.directions {
+		if i != direction {
+			dcc.directions[i] = nil
+		}
+	}
+}
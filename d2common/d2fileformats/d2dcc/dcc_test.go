@@ -0,0 +1,86 @@
+package d2dcc
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+const (
+	testDirectionCount = 4
+	testDirectionSize  = 64
+)
+
+// testDCC returns a DCC file with the given number of directions, each
+// holding a single empty frame.
+func testDCC(directions int) []byte {
+	headerSize := 15 + 4*directions
+
+	data := make([]byte, headerSize+directions*testDirectionSize)
+	data[0] = dccFileSignature
+	data[1] = 6
+	data[2] = byte(directions)
+	binary.LittleEndian.PutUint32(data[3:], 1)  // FramesPerDirection
+	binary.LittleEndian.PutUint32(data[7:], 1)  // Always 1
+	binary.LittleEndian.PutUint32(data[11:], 0) // TotalSizeCoded
+
+	for i := 0; i < directions; i++ {
+		binary.LittleEndian.PutUint32(data[15+4*i:], uint32(headerSize+i*testDirectionSize))
+	}
+
+	return data
+}
+
+func TestDecodeSingleDirection(t *testing.T) {
+	dcc, err := Load(testDCC(testDirectionCount))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dcc.directionOffsets) != testDirectionCount {
+		t.Fatalf("direction offsets: wanted %d: got %d", testDirectionCount, len(dcc.directionOffsets))
+	}
+
+	for i := 0; i < testDirectionCount; i++ {
+		if dcc.IsDirectionDecoded(i) {
+			t.Errorf("direction %d decoded on load", i)
+		}
+	}
+
+	direction := dcc.Direction(0)
+	if len(direction.Frames) != 1 {
+		t.Errorf("frames: wanted %d: got %d", 1, len(direction.Frames))
+	}
+
+	if dcc.Direction(0) != direction {
+		t.Error("direction decoded again")
+	}
+
+	if !dcc.IsDirectionDecoded(0) {
+		t.Error("direction 0 not decoded")
+	}
+
+	for i := 1; i < testDirectionCount; i++ {
+		if dcc.IsDirectionDecoded(i) {
+			t.Errorf("direction %d decoded with direction 0", i)
+		}
+	}
+}
+
+func TestFreeDirectionsExcept(t *testing.T) {
+	dcc, err := Load(testDCC(testDirectionCount))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dcc.Direction(0)
+	dcc.Direction(2)
+	dcc.FreeDirectionsExcept(2)
+
+	if dcc.IsDirectionDecoded(0) {
+		t.Error("direction 0 not freed")
+	}
+
+	if !dcc.IsDirectionDecoded(2) {
+		t.Error("kept direction 2 freed")
+	}
+}
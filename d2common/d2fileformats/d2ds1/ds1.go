@@ -206,21 +206,19 @@ func (ds1 *DS1) loadNPCs(br *d2common.StreamReader) {
 			if objIdx > -1 {
 				ds1.loadNpcPaths(br, objIdx, int(numPaths))
 			} else {
+				// Skip the path points of NPCs without an object, which are two or three int32s each
 				if ds1.Version >= 15 { //nolint:gomnd // Version number
-					br.SkipBytes(int(numPaths) * 3) //nolint:gomnd // Unknown data
+					br.SkipBytes(int(numPaths) * 3 * 4) //nolint:gomnd // Path point size
 				} else {
-					br.SkipBytes(int(numPaths) * 2) //nolint:gomnd // Unknown data
+					br.SkipBytes(int(numPaths) * 2 * 4) //nolint:gomnd // Path point size
 				}
 			}
 		}
 	}
 }
 
+// loadNpcPaths reads the path points of an NPC, appending them to the paths of its object
 func (ds1 *DS1) loadNpcPaths(br *d2common.StreamReader, objIdx, numPaths int) {
-	if ds1.Objects[objIdx].Paths == nil {
-		ds1.Objects[objIdx].Paths = make([]d2common.Path, numPaths)
-	}
-
 	for pathIdx := 0; pathIdx < numPaths; pathIdx++ {
 		newPath := d2common.Path{}
 		newPath.X = int(br.GetInt32())
@@ -230,7 +228,7 @@ func (ds1 *DS1) loadNpcPaths(br *d2common.StreamReader, objIdx, numPaths int) {
 			newPath.Action = int(br.GetInt32())
 		}
 
-		ds1.Objects[objIdx].Paths[pathIdx] = newPath
+		ds1.Objects[objIdx].Paths = append(ds1.Objects[objIdx].Paths, newPath)
 	}
 }
 
@@ -0,0 +1,78 @@
+package d2ds1
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// testDS1 returns a version 15 DS1 of a single tile, with two objects and
+// three NPC path records, the second of which has no object.
+func testDS1() []byte {
+	values := []int32{
+		15,   // Version
+		0, 0, // Width and height, minus one
+		0,    // Act, minus one
+		0,    // SubstitutionType
+		0,    // Number of files
+		1,    // NumberOfWalls
+		0, 0, // Wall and orientation layers
+		0,              // Floor layer
+		0,              // Shadow layer
+		2,              // Number of objects
+		1, 10, 5, 6, 0, // Type, ID, X, Y, Flags
+		2, 11, 7, 8, 0,
+		3,       // Number of NPCs
+		2, 5, 6, // Number of paths, X, Y
+		1, 2, 0, // Path X, Y, Action
+		3, 4, 1,
+		1, 99, 99,
+		0, 0, 0,
+		1, 7, 8,
+		9, 9, 2,
+	}
+
+	var buffer bytes.Buffer
+	_ = binary.Write(&buffer, binary.LittleEndian, values)
+
+	return buffer.Bytes()
+}
+
+func TestLoadObjects(t *testing.T) {
+	ds1, err := LoadDS1(testDS1())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ds1.Objects) != 2 {
+		t.Fatalf("objects: wanted %d: got %d", 2, len(ds1.Objects))
+	}
+
+	first := ds1.Objects[0]
+	if first.Type != 1 || first.Id != 10 || first.X != 5 || first.Y != 6 {
+		t.Errorf("first object: wanted type 1, id 10 at (5, 6): got type %d, id %d at (%d, %d)",
+			first.Type, first.Id, first.X, first.Y)
+	}
+
+	wantedPaths := [][]d2common.Path{
+		{{X: 1, Y: 2, Action: 0}, {X: 3, Y: 4, Action: 1}},
+		{{X: 9, Y: 9, Action: 2}},
+	}
+
+	for i, wanted := range wantedPaths {
+		paths := ds1.Objects[i].Paths
+		if len(paths) != len(wanted) {
+			t.Errorf("object %d paths: wanted %v: got %v", i, wanted, paths)
+			continue
+		}
+
+		for j := range wanted {
+			if paths[j] != wanted[j] {
+				t.Errorf("object %d paths: wanted %v: got %v", i, wanted, paths)
+				break
+			}
+		}
+	}
+}
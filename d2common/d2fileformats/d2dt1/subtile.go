@@ -51,6 +51,22 @@ func (s *SubTileFlags) DebugString() string {
 	return result
 }
 
+// Encode returns the flags as the byte they are stored as in a DT1
+func (s *SubTileFlags) Encode() byte {
+	var data byte
+
+	flags := []bool{s.BlockWalk, s.BlockLOS, s.BlockJump, s.BlockPlayerWalk, s.Unknown1, s.BlockLight, s.Unknown2,
+		s.Unknown3}
+
+	for bit, set := range flags {
+		if set {
+			data |= 1 << uint(bit)
+		}
+	}
+
+	return data
+}
+
 // NewSubTileFlags returns a list of new subtile flags
 //nolint:gomnd binary flags
 func NewSubTileFlags(data byte) SubTileFlags {
@@ -28,3 +28,17 @@ func (t *Tile) GetSubTileFlags(x, y int) *SubTileFlags {
 
 	return &t.SubTileFlags[subtileLookup[y][x]]
 }
+
+// GetSubTileFlagBytes returns the encoded flags of the sub-tiles, indexed by
+// y*5+x, for building collision grids. A sub-tile with no flags set is walkable.
+func (t *Tile) GetSubTileFlagBytes() [25]byte {
+	var result [25]byte
+
+	for y := range subtileLookup {
+		for x := range subtileLookup[y] {
+			result[y*len(subtileLookup[y])+x] = t.GetSubTileFlags(x, y).Encode()
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,30 @@
+package d2dt1
+
+import (
+	"testing"
+
+	testify "github.com/stretchr/testify/assert"
+)
+
+func TestSubTileFlagsEncode(t *testing.T) {
+	assert := testify.New(t)
+
+	for i := 0; i < 256; i++ {
+		flags := NewSubTileFlags(byte(i))
+		assert.Equal(byte(i), flags.Encode())
+	}
+}
+
+func TestGetSubTileFlagBytes(t *testing.T) {
+	assert := testify.New(t)
+
+	tile := Tile{}
+	tile.GetSubTileFlags(1, 2).BlockWalk = true
+	tile.GetSubTileFlags(3, 4).BlockLOS = true
+
+	flags := tile.GetSubTileFlagBytes()
+
+	assert.Equal(byte(1), flags[2*5+1])
+	assert.Equal(byte(2), flags[4*5+3])
+	assert.Equal(byte(0), flags[0])
+}
@@ -0,0 +1,130 @@
+package d2mpq
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// ErrFileNotFound is returned when none of the archives mounted in a file system contains the requested file
+var ErrFileNotFound = errors.New("file not found in any mounted archive")
+
+type mountedArchive struct {
+	archive  d2interface.Archive
+	priority int
+	order    int
+}
+
+// FileSystem layers several mounted archives, so mods can override game files without editing the original MPQs.
+// A file is resolved from the highest priority archive which contains it. Among archives of the same priority, the
+// one mounted last takes precedence.
+type FileSystem struct {
+	mounts    []mountedArchive
+	mountings int
+	mutex     sync.RWMutex
+}
+
+// NewFileSystem creates a file system without any mounted archives
+func NewFileSystem() *FileSystem {
+	return &FileSystem{}
+}
+
+// Mount adds an archive to the file system with the given priority
+func (fs *FileSystem) Mount(archive d2interface.Archive, priority int) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	fs.mounts = append(fs.mounts, mountedArchive{archive: archive, priority: priority, order: fs.mountings})
+	fs.mountings++
+
+	sort.SliceStable(fs.mounts, func(i, j int) bool {
+		if fs.mounts[i].priority != fs.mounts[j].priority {
+			return fs.mounts[i].priority > fs.mounts[j].priority
+		}
+
+		return fs.mounts[i].order > fs.mounts[j].order
+	})
+}
+
+// MountFile loads the MPQ at the given path and adds it to the file system with the given priority
+func (fs *FileSystem) MountFile(archivePath string, priority int) (d2interface.Archive, error) {
+	archive, err := Load(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	fs.Mount(archive, priority)
+
+	return archive, nil
+}
+
+// Unmount removes an archive from the file system, returning false if it wasn't mounted. The archive isn't closed.
+func (fs *FileSystem) Unmount(archive d2interface.Archive) bool {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	for idx := range fs.mounts {
+		if fs.mounts[idx].archive == archive {
+			fs.mounts = append(fs.mounts[:idx], fs.mounts[idx+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Archives returns the mounted archives, from the highest priority to the lowest
+func (fs *FileSystem) Archives() []d2interface.Archive {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	archives := make([]d2interface.Archive, len(fs.mounts))
+
+	for idx := range fs.mounts {
+		archives[idx] = fs.mounts[idx].archive
+	}
+
+	return archives
+}
+
+// Resolve returns the archive the given file is read from
+func (fs *FileSystem) Resolve(filePath string) (d2interface.Archive, error) {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	for idx := range fs.mounts {
+		if fs.mounts[idx].archive.Contains(filePath) {
+			return fs.mounts[idx].archive, nil
+		}
+	}
+
+	return nil, ErrFileNotFound
+}
+
+// Exists returns true if any mounted archive contains the given file
+func (fs *FileSystem) Exists(filePath string) bool {
+	_, err := fs.Resolve(filePath)
+	return err == nil
+}
+
+// Open returns a stream of the given file, read from the highest priority archive which contains it
+func (fs *FileSystem) Open(filePath string) (d2interface.ArchiveDataStream, error) {
+	archive, err := fs.Resolve(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return archive.ReadFileStream(filePath)
+}
+
+// ReadFile returns the contents of the given file, read from the highest priority archive which contains it
+func (fs *FileSystem) ReadFile(filePath string) ([]byte, error) {
+	archive, err := fs.Resolve(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return archive.ReadFile(filePath)
+}
@@ -0,0 +1,94 @@
+package d2mpq
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestArchive(t *testing.T, archivePath string, files map[string]string) {
+	t.Helper()
+
+	writer := NewWriter()
+
+	for fileName, content := range files {
+		if err := writer.AddFile(fileName, []byte(content), true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Save(archivePath); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileSystemOverride(t *testing.T) {
+	dir, err := ioutil.TempDir("", "d2mpq")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	const (
+		sharedFile = `data\global\excel\weapons.txt`
+		baseFile   = `data\global\excel\armor.txt`
+	)
+
+	basePath := filepath.Join(dir, "d2data.mpq")
+	overridePath := filepath.Join(dir, "mod.mpq")
+
+	writeTestArchive(t, basePath, map[string]string{sharedFile: "base weapons", baseFile: "base armor"})
+	writeTestArchive(t, overridePath, map[string]string{sharedFile: "mod weapons"})
+
+	fs := NewFileSystem()
+
+	// mount the override first, priority decides and not the mounting order
+	override, err := fs.MountFile(overridePath, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer override.Close()
+
+	base, err := fs.MountFile(basePath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer base.Close()
+
+	for fileName, want := range map[string]string{sharedFile: "mod weapons", baseFile: "base armor"} {
+		stream, err := fs.Open(fileName)
+		if err != nil {
+			t.Errorf("opening %s: %v", fileName, err)
+			continue
+		}
+
+		got := make([]byte, len(want))
+		n, _ := stream.Read(got)
+		_ = stream.Close()
+
+		if string(got[:n]) != want {
+			t.Errorf("opening %s: wanted %q: got %q", fileName, want, got[:n])
+		}
+	}
+
+	if _, err := fs.Open(`data\global\excel\missing.txt`); err != ErrFileNotFound {
+		t.Errorf("opening a missing file: wanted %v: got %v", ErrFileNotFound, err)
+	}
+
+	if !fs.Unmount(override) {
+		t.Fatal("unmounting the override archive failed")
+	}
+
+	data, err := fs.ReadFile(sharedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "base weapons" {
+		t.Errorf("reading %s after unmounting the override: wanted %q: got %q", sharedFile, "base weapons", data)
+	}
+}
@@ -229,6 +229,19 @@ func decrypt(data []uint32, seed uint32) {
 	}
 }
 
+func encrypt(data []uint32, seed uint32) {
+	seed2 := uint32(0xeeeeeeee) //nolint:gomnd Decryption magic
+
+	for i := 0; i < len(data); i++ {
+		seed2 += cryptoLookup(0x400 + (seed & 0xff)) //nolint:gomnd Decryption magic
+		plain := data[i]
+		data[i] = plain ^ (seed + seed2)
+
+		seed = ((^seed << 21) + 0x11111111) | (seed >> 11)
+		seed2 = plain + seed2 + (seed2 << 5) + 3 //nolint:gomnd Decryption magic
+	}
+}
+
 func decryptBytes(data []byte, seed uint32) {
 	seed2 := uint32(0xEEEEEEEE) //nolint:gomnd Decryption magic
 	for i := 0; i < len(data)-3; i += 4 {
@@ -345,9 +358,14 @@ func (v *BlockTableEntry) calculateEncryptionSeed() {
 	v.EncryptionSeed = (v.EncryptionSeed + v.FilePosition) ^ v.UncompressedFileSize
 }
 
-// GetFileList returns the list of files in this MPQ
+// GetFileList returns the list of files in this MPQ, read from its (listfile). Archives without a (listfile) can't
+// be enumerated, for them the list is empty.
 func (v *MPQ) GetFileList() ([]string, error) {
-	data, err := v.ReadFile("(listfile)")
+	if !v.Contains(listFileName) {
+		return []string{}, nil
+	}
+
+	data, err := v.ReadFile(listFileName)
 
 	if err != nil {
 		return nil, err
@@ -356,10 +374,14 @@ func (v *MPQ) GetFileList() ([]string, error) {
 	raw := strings.TrimRight(string(data), "\x00")
 	s := bufio.NewScanner(strings.NewReader(raw))
 
-	var filePaths []string
+	filePaths := []string{}
 
 	for s.Scan() {
-		filePath := s.Text()
+		filePath := strings.TrimSpace(s.Text())
+		if filePath == "" {
+			continue
+		}
+
 		filePaths = append(filePaths, filePath)
 	}
 
@@ -0,0 +1,55 @@
+package d2mpq
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetFileList(t *testing.T) {
+	// testdata/listfile.mpq contains three files and a (listfile) naming them
+	archive, err := Load(filepath.Join("testdata", "listfile.mpq"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer archive.Close()
+
+	fileList, err := archive.GetFileList()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`data\global\excel\armor.txt`,
+		`data\global\ui\panel\invchar6.dc6`,
+		`data\local\lng\eng\string.tbl`,
+	}
+
+	if len(fileList) != len(want) {
+		t.Fatalf("file list: wanted %v: got %v", want, fileList)
+	}
+
+	for idx := range want {
+		if fileList[idx] != want[idx] {
+			t.Errorf("file list entry %d: wanted %s: got %s", idx, want[idx], fileList[idx])
+		}
+
+		if !archive.Contains(fileList[idx]) {
+			t.Errorf("archive doesn't contain listed file %s", fileList[idx])
+		}
+	}
+}
+
+func TestGetFileListWithoutListFile(t *testing.T) {
+	// an archive without a (listfile), nothing was ever loaded into its hash table
+	archive := &MPQ{}
+
+	fileList, err := archive.GetFileList()
+	if err != nil {
+		t.Errorf("file list: wanted no error: got %v", err)
+	}
+
+	if fileList == nil || len(fileList) != 0 {
+		t.Errorf("file list: wanted an empty list: got %v", fileList)
+	}
+}
@@ -0,0 +1,250 @@
+package d2mpq
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	listFileName = "(listfile)"
+
+	mpqHeaderSize        = 32
+	mpqBlockSizeShift    = 3 // sectors are 0x200 << 3 = 4096 bytes
+	mpqMinHashTableSize  = 16
+	mpqCompressionZlib   = 0x02
+	mpqHashTableEmpty    = 0xFFFFFFFF
+	mpqHashTablePosition = 0
+)
+
+// ErrDuplicateFile is returned when adding a file which has already been added to the MPQ writer
+var ErrDuplicateFile = errors.New("file already added to mpq")
+
+// ErrEmptyFileName is returned when adding a file without a name to the MPQ writer
+var ErrEmptyFileName = errors.New("mpq file name is empty")
+
+type writerFile struct {
+	fileName string
+	data     []byte
+	compress bool
+}
+
+// Writer creates MPQ archives, for packaging mod assets. Files are stored unencrypted, split into sectors which are
+// optionally compressed with zlib, and a (listfile) naming every file is added when the archive is written.
+type Writer struct {
+	files []writerFile
+	names map[string]bool
+}
+
+// NewWriter creates an empty MPQ writer
+func NewWriter() *Writer {
+	return &Writer{names: make(map[string]bool)}
+}
+
+// AddFile adds a file to the archive. File names are matched without regard to case, as they are by the reader, and
+// use backslashes as path separators like the game archives do.
+func (w *Writer) AddFile(fileName string, data []byte, compress bool) error {
+	if fileName == "" {
+		return ErrEmptyFileName
+	}
+
+	key := strings.ToUpper(fileName)
+	if w.names[key] {
+		return ErrDuplicateFile
+	}
+
+	w.names[key] = true
+	w.files = append(w.files, writerFile{fileName: fileName, data: data, compress: compress})
+
+	return nil
+}
+
+// Save writes the archive to the given file, replacing it if it exists
+func (w *Writer) Save(fileName string) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	if err := w.Write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// Write writes the archive: the header, followed by the file data, the hash table and the block table
+func (w *Writer) Write(out io.Writer) error {
+	files := w.files
+
+	if !w.names[strings.ToUpper(listFileName)] {
+		files = append(files[:len(files):len(files)], writerFile{
+			fileName: listFileName,
+			data:     w.listFile(),
+			compress: true,
+		})
+	}
+
+	body := new(bytes.Buffer)
+	blocks := make([]BlockTableEntry, len(files))
+
+	for idx := range files {
+		data, flags, err := encodeFileData(files[idx].data, files[idx].compress)
+		if err != nil {
+			return err
+		}
+
+		blocks[idx] = BlockTableEntry{
+			FilePosition:         uint32(mpqHeaderSize + body.Len()),
+			CompressedFileSize:   uint32(len(data)),
+			UncompressedFileSize: uint32(len(files[idx].data)),
+			Flags:                flags,
+		}
+
+		body.Write(data)
+	}
+
+	hashTable := buildHashTable(files)
+	hashTableOffset := uint32(mpqHeaderSize + body.Len())
+	blockTableOffset := hashTableOffset + uint32(len(hashTable)*4)
+
+	blockTable := make([]uint32, 0, len(blocks)*4)
+	for idx := range blocks {
+		blockTable = append(blockTable, blocks[idx].FilePosition, blocks[idx].CompressedFileSize,
+			blocks[idx].UncompressedFileSize, uint32(blocks[idx].Flags))
+	}
+
+	encrypt(hashTable, hashString("(hash table)", 3))
+	encrypt(blockTable, hashString("(block table)", 3))
+
+	header := Data{
+		Magic:             [4]byte{'M', 'P', 'Q', 0x1A},
+		HeaderSize:        mpqHeaderSize,
+		ArchiveSize:       blockTableOffset + uint32(len(blockTable)*4),
+		BlockSize:         mpqBlockSizeShift,
+		HashTableOffset:   hashTableOffset,
+		BlockTableOffset:  blockTableOffset,
+		HashTableEntries:  uint32(len(hashTable) / 4),
+		BlockTableEntries: uint32(len(blocks)),
+	}
+
+	for _, part := range []interface{}{header, body.Bytes(), hashTable, blockTable} {
+		if err := binary.Write(out, binary.LittleEndian, part); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listFile returns the contents of the (listfile), the name of every added file on its own line
+func (w *Writer) listFile() []byte {
+	buf := new(bytes.Buffer)
+
+	for idx := range w.files {
+		buf.WriteString(w.files[idx].fileName)
+		buf.WriteString("\r\n")
+	}
+
+	return buf.Bytes()
+}
+
+// encodeFileData splits the file data into sectors. When compressing, the sectors are preceded by a table of their
+// offsets, and each sector which gets smaller is zlib compressed, the others are stored as they are.
+func encodeFileData(data []byte, compress bool) ([]byte, FileFlag, error) {
+	if !compress || len(data) == 0 {
+		return data, FileExists, nil
+	}
+
+	sectorSize := 0x200 << mpqBlockSizeShift
+	sectorCount := (len(data) + sectorSize - 1) / sectorSize
+	offsets := make([]uint32, sectorCount+1)
+	sectors := new(bytes.Buffer)
+
+	offsets[0] = uint32(len(offsets) * 4)
+
+	for idx := 0; idx < sectorCount; idx++ {
+		start := idx * sectorSize
+		end := start + sectorSize
+
+		if end > len(data) {
+			end = len(data)
+		}
+
+		sector, err := compressSector(data[start:end])
+		if err != nil {
+			return nil, 0, err
+		}
+
+		sectors.Write(sector)
+		offsets[idx+1] = offsets[0] + uint32(sectors.Len())
+	}
+
+	encoded := new(bytes.Buffer)
+
+	if err := binary.Write(encoded, binary.LittleEndian, offsets); err != nil {
+		return nil, 0, err
+	}
+
+	encoded.Write(sectors.Bytes())
+
+	return encoded.Bytes(), FileExists | FileCompress, nil
+}
+
+// compressSector zlib compresses a sector, prefixed with the compression type. Sectors which don't get smaller are
+// returned as they are, the reader only decompresses sectors which are smaller than their uncompressed size.
+func compressSector(sector []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(mpqCompressionZlib)
+
+	zw := zlib.NewWriter(buf)
+
+	if _, err := zw.Write(sector); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	if buf.Len() >= len(sector) {
+		return sector, nil
+	}
+
+	return buf.Bytes(), nil
+}
+
+// buildHashTable returns the unencrypted hash table of the given files, four values per entry. Each file is placed
+// at the index given by hashing its name, or the next free entry after it.
+func buildHashTable(files []writerFile) []uint32 {
+	size := mpqMinHashTableSize
+	for size <= len(files) {
+		size <<= 1
+	}
+
+	table := make([]uint32, size*4)
+	for idx := range table {
+		table[idx] = mpqHashTableEmpty
+	}
+
+	for blockIndex := range files {
+		name := files[blockIndex].fileName
+		entry := int(hashString(name, mpqHashTablePosition)) & (size - 1)
+
+		for table[entry*4+3] != mpqHashTableEmpty {
+			entry = (entry + 1) & (size - 1)
+		}
+
+		table[entry*4] = hashString(name, 1)
+		table[entry*4+1] = hashString(name, 2)
+		table[entry*4+2] = 0 // neutral locale, default platform
+		table[entry*4+3] = uint32(blockIndex)
+	}
+
+	return table
+}
@@ -0,0 +1,68 @@
+package d2mpq
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "d2mpq")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	// larger than a sector and compressible, so it's split into several compressed sectors
+	compressed := bytes.Repeat([]byte("OpenDiablo2 "), 1000)
+	stored := []byte("stored without compression")
+
+	writer := NewWriter()
+
+	if err := writer.AddFile(`data\global\excel\compressed.txt`, compressed, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.AddFile(`data\global\stored.txt`, stored, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.AddFile(`DATA\GLOBAL\STORED.TXT`, stored, false); err != ErrDuplicateFile {
+		t.Errorf("adding a duplicate file: wanted %v: got %v", ErrDuplicateFile, err)
+	}
+
+	archivePath := filepath.Join(dir, "mod.mpq")
+
+	if err := writer.Save(archivePath); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := Load(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer archive.Close()
+
+	for fileName, want := range map[string][]byte{
+		`data\global\excel\compressed.txt`: compressed,
+		`data\global\stored.txt`:           stored,
+	} {
+		got, err := archive.ReadFile(fileName)
+		if err != nil {
+			t.Errorf("reading %s: %v", fileName, err)
+			continue
+		}
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("reading %s: wanted %d bytes: got %d bytes which differ", fileName, len(want), len(got))
+		}
+	}
+
+	if archive.Contains(`data\global\missing.txt`) {
+		t.Error("archive contains a file which was never added")
+	}
+}
@@ -0,0 +1,84 @@
+package d2pl2
+
+import (
+	"math"
+)
+
+// LightLevelCount is the number of light level variations, from fully lit to dark
+const LightLevelCount = 32
+
+// LightLevelForBrightness returns the light level variation for a brightness,
+// from 0 (dark) to 1 (fully lit). Level 0 is fully lit.
+func (p *PL2) LightLevelForBrightness(brightness float64) int {
+	return LightLevel(brightness)
+}
+
+// LightLevel returns the light level variation for a brightness, from 0
+// (dark) to 1 (fully lit). Level 0 is fully lit.
+func LightLevel(brightness float64) int {
+	brightness = math.Max(0, math.Min(brightness, 1))
+
+	return int(math.Round((1 - brightness) * (LightLevelCount - 1)))
+}
+
+// LightLevelBrightness returns the brightness of a light level variation,
+// from 0 (dark) to 1 (fully lit).
+func LightLevelBrightness(level int) float64 {
+	return 1 - (float64(level) / (LightLevelCount - 1))
+}
+
+// LightLevelIndex returns the palette index the given index is drawn as at a
+// light level.
+func (p *PL2) LightLevelIndex(level int, index uint8) uint8 {
+	return p.LightLevelVariations[level].Indices[index]
+}
+
+// LightLevelColor returns the base palette color the given index is drawn
+// with at a light level.
+func (p *PL2) LightLevelColor(level int, index uint8) PL2Color {
+	return p.BasePalette.Colors[p.LightLevelIndex(level, index)]
+}
+
+// LightLevelScale returns how much a light level variation scales each
+// channel of the base palette colors, on average. It lets images which are
+// already converted to colors be drawn at the light level.
+func (p *PL2) LightLevelScale(level int) (red, green, blue float64) {
+	var litRed, litGreen, litBlue, baseRed, baseGreen, baseBlue float64
+
+	for index := range p.BasePalette.Colors {
+		base := p.BasePalette.Colors[index]
+		lit := p.LightLevelColor(level, uint8(index))
+
+		litRed += float64(lit.R)
+		litGreen += float64(lit.G)
+		litBlue += float64(lit.B)
+		baseRed += float64(base.R)
+		baseGreen += float64(base.G)
+		baseBlue += float64(base.B)
+	}
+
+	return channelScale(litRed, baseRed), channelScale(litGreen, baseGreen), channelScale(litBlue, baseBlue)
+}
+
+// channelScale returns the ratio of the lit to the base channel total, a
+// channel missing from the palette is left unchanged.
+func channelScale(lit, base float64) float64 {
+	if base == 0 {
+		return 1
+	}
+
+	return lit / base
+}
+
+// InvColorIndex returns the palette index the given index is drawn as when
+// tinted with an item color variation.
+func (p *PL2) InvColorIndex(variation int, index uint8) uint8 {
+	return p.InvColorVariations[variation].Indices[index]
+}
+
+// AlphaBlendIndex returns the palette index of a source index drawn over a
+// destination index at an alpha level, where levels 0, 1 and 2 are 25%, 50%
+// and 75% opaque.
+func (p *PL2) AlphaBlendIndex(level int, source, destination uint8) uint8 {
+	return p.AlphaBlend[level][source].Indices[destination]
+}
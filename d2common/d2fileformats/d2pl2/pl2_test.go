@@ -0,0 +1,85 @@
+package d2pl2
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestLightLevelColor(t *testing.T) {
+	var pl2 PL2
+
+	pl2.BasePalette.Colors[10] = PL2Color{R: 1, G: 2, B: 3}
+	pl2.LightLevelVariations[5].Indices[7] = 10
+
+	var buffer bytes.Buffer
+	if err := binary.Write(&buffer, binary.LittleEndian, &pl2); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(buffer.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wanted := PL2Color{R: 1, G: 2, B: 3}
+	if got := loaded.LightLevelColor(5, 7); got != wanted {
+		t.Errorf("light level 5 color of index 7: wanted %v: got %v", wanted, got)
+	}
+
+	if got := loaded.LightLevelIndex(5, 8); got != 0 {
+		t.Errorf("light level 5 index of index 8: wanted %d: got %d", 0, got)
+	}
+}
+
+func TestLightLevelForBrightness(t *testing.T) {
+	var pl2 PL2
+
+	tests := []struct {
+		brightness float64
+		wanted     int
+	}{
+		{1, 0},
+		{0, LightLevelCount - 1},
+		{0.5, 16},
+		{2, 0},
+		{-1, LightLevelCount - 1},
+	}
+
+	for _, test := range tests {
+		if got := pl2.LightLevelForBrightness(test.brightness); got != test.wanted {
+			t.Errorf("brightness %v: wanted %d: got %d", test.brightness, test.wanted, got)
+		}
+	}
+}
+
+func TestLightLevelScale(t *testing.T) {
+	var pl2 PL2
+
+	pl2.BasePalette.Colors[1] = PL2Color{R: 200, G: 100, B: 0}
+	pl2.BasePalette.Colors[2] = PL2Color{R: 100, G: 50, B: 0}
+
+	for index := range pl2.LightLevelVariations[0].Indices {
+		pl2.LightLevelVariations[0].Indices[index] = uint8(index)
+		pl2.LightLevelVariations[10].Indices[index] = uint8(index)
+	}
+
+	// level 10 draws the bright color as the dark one
+	pl2.LightLevelVariations[10].Indices[1] = 2
+
+	tests := []struct {
+		level                        int
+		wantRed, wantGreen, wantBlue float64
+	}{
+		{0, 1, 1, 1},
+		{10, 200.0 / 300, 100.0 / 150, 1},
+	}
+
+	for _, test := range tests {
+		red, green, blue := pl2.LightLevelScale(test.level)
+		if red != test.wantRed || green != test.wantGreen || blue != test.wantBlue {
+			t.Errorf("light level %d scale: wanted (%.2f, %.2f, %.2f): got (%.2f, %.2f, %.2f)", test.level,
+				test.wantRed, test.wantGreen, test.wantBlue, red, green, blue)
+		}
+	}
+}
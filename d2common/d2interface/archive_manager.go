@@ -7,4 +7,5 @@ type ArchiveManager interface {
 	FileExistsInArchive(filePath string) (bool, error)
 	LoadArchive(archivePath string) (Archive, error)
 	CacheArchiveEntries() error
+	ReloadArchivesForFile(filePath string) error
 }
@@ -7,4 +7,5 @@ type ArchivedFileManager interface {
 	LoadFileStream(filePath string) (ArchiveDataStream, error)
 	LoadFile(filePath string) ([]byte, error)
 	FileExists(filePath string) (bool, error)
+	ReloadFile(filePath string) error
 }
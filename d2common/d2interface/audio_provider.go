@@ -1,9 +1,20 @@
 package d2interface
 
+import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
 // AudioProvider is something that can play music, load audio files managed
-// by the asset manager, and set the game engine's volume levels
+// by the asset manager, and set the volume level of each sound category
 type AudioProvider interface {
 	PlayBGM(song string)
-	LoadSoundEffect(sfx string) (SoundEffect, error)
-	SetVolumes(bgmVolume, sfxVolume float64)
+	CrossfadeTo(song string, duration float64)
+	Advance(elapsed float64) error
+	LoadSoundEffect(sfx string, category d2enum.SoundCategory) (SoundEffect, error)
+	SetCategoryVolume(category d2enum.SoundCategory, level float64)
+	GetCategoryVolume(category d2enum.SoundCategory) float64
+	SetListenerPosition(x, y float64)
+	PlayAt(sfx string, x, y float64)
+	PlayLoop(sfx string) (SoundLoop, error)
+	SetPitchVariation(sfx string, semitones float64)
+	SetMaxVoices(maxVoices int)
+	SetMaxInstances(sfx string, maxInstances int)
 }
@@ -7,6 +7,7 @@ type Cache interface {
 	GetBudget() int
 	Insert(key string, value interface{}, weight int) error
 	Retrieve(key string) (interface{}, bool)
+	Remove(key string)
 	Clear()
 }
 
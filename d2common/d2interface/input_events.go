@@ -34,3 +34,27 @@ type MouseEvent interface {
 type MouseMoveEvent interface {
 	HandlerEvent
 }
+
+// MouseWheelEvent represents a mouse wheel event
+type MouseWheelEvent interface {
+	HandlerEvent
+	// DeltaX is the horizontal wheel offset, positive values are to the right
+	DeltaX() float64
+	// DeltaY is the vertical wheel offset, positive values are up
+	DeltaY() float64
+}
+
+// InputActionEvent represents an event associated with a bound input action, triggered by a key or gamepad button
+type InputActionEvent interface {
+	HandlerEvent
+	Action() d2enum.InputAction
+}
+
+// GamepadStickEvent represents the movement of a gamepad stick outside of its deadzone
+type GamepadStickEvent interface {
+	HandlerEvent
+	Stick() d2enum.GamepadStick
+	// DirectionX and DirectionY form a vector of up to unit length, positive values are right and down
+	DirectionX() float64
+	DirectionY() float64
+}
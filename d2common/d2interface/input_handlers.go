@@ -42,3 +42,18 @@ type MouseButtonUpHandler interface {
 type MouseMoveHandler interface {
 	OnMouseMove(event MouseMoveEvent) bool
 }
+
+// MouseWheelHandler represents a handler for a mouse wheel event
+type MouseWheelHandler interface {
+	OnMouseWheel(event MouseWheelEvent) bool
+}
+
+// InputActionHandler represents a handler for a key or gamepad button bound to an input action being pressed
+type InputActionHandler interface {
+	OnInputAction(event InputActionEvent) bool
+}
+
+// GamepadStickHandler represents a handler for a gamepad stick being held outside of its deadzone
+type GamepadStickHandler interface {
+	OnGamepadStick(event GamepadStickEvent) bool
+}
@@ -22,4 +22,12 @@ type InputService interface {
 	IsMouseButtonJustReleased(button d2enum.MouseButton) bool
 	// KeyPressDuration returns how long the key is pressed in frames.
 	KeyPressDuration(key d2enum.Key) int
+	// Wheel returns the mouse wheel offsets since the last update.
+	Wheel() (xoff, yoff float64)
+	// GamepadStickPosition returns the position of a stick of the first connected gamepad, from -1 to 1 on each
+	// axis. Positive values are right and down. It returns 0, 0 if no gamepad is connected.
+	GamepadStickPosition(stick d2enum.GamepadStick) (x, y float64)
+	// IsGamepadButtonJustPressed checks if the provided button of the first connected gamepad is just transitioned
+	// from up to down.
+	IsGamepadButtonJustPressed(button d2enum.GamepadButton) bool
 }
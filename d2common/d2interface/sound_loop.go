@@ -0,0 +1,8 @@
+package d2interface
+
+// SoundLoop is a looping sound started by the AudioProvider, which is
+// faded out when stopped
+type SoundLoop interface {
+	Stop(fadeDuration float64) error
+	IsPlaying() bool
+}
@@ -22,6 +22,15 @@ type Surface interface {
 	PushFilter(filter d2enum.Filter)
 	PushTranslation(x, y int)
 	PushBrightness(brightness float64)
+	// PushScale stretches the following renders of surfaces by the given factors, from the current translation. Nested
+	// scales multiply. Translations pushed after a scale, rectangles, lines and text are not scaled.
+	PushScale(scaleX, scaleY float64)
+	// PushClip restricts drawing to bounds, relative to the current translation. Nested clips intersect.
+	PushClip(bounds image.Rectangle)
+	// SetBlendMode sets how the following draws are combined with the surface. Popping a state restores the blend
+	// mode it was pushed with.
+	SetBlendMode(mode d2enum.BlendMode)
+	GetBlendMode() d2enum.BlendMode
 	Render(surface Surface) error
 	// Renders a section of the surface enclosed by bounds
 	RenderSection(surface Surface, bound image.Rectangle) error
@@ -196,13 +196,16 @@ const (
 
 	// --- Inventory Data ---
 
-	Inventory     = "/data/global/excel/inventory.txt"
+	Inventory   = "/data/global/excel/inventory.txt"
 	Weapons     = "/data/global/excel/weapons.txt"
 	Armor       = "/data/global/excel/armor.txt"
 	Misc        = "/data/global/excel/misc.txt"
 	UniqueItems = "/data/global/excel/UniqueItems.txt"
 	Gems        = "/data/global/excel/gems.txt"
 
+	TreasureClassEx = "/data/global/excel/TreasureClassEx.txt"
+	ItemTypes       = "/data/global/excel/ItemTypes.txt"
+
 	// --- Affixes ---
 
 	MagicPrefix = "/data/global/excel/MagicPrefix.txt"
@@ -282,12 +285,13 @@ const (
 	// --- Enemy Data ---
 
 	MonStats     = "/data/global/excel/monstats.txt"
-	MonStats2 	 = "/data/global/excel/monstats2.txt"
+	MonStats2    = "/data/global/excel/monstats2.txt"
 	MonPreset    = "/data/global/excel/monpreset.txt"
 	SuperUniques = "/data/global/excel/SuperUniques.txt"
 
 	// --- Skill Data ---
 
+	Skills   = "/data/global/excel/Skills.txt"
 	Missiles = "/data/global/excel/Missiles.txt"
 
 	// --- Palettes ---
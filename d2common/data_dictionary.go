@@ -22,6 +22,8 @@ func LoadDataDictionary(buf []byte) *DataDictionary {
 	cr := csv.NewReader(bytes.NewReader(buf))
 	cr.Comma = '\t'
 	cr.ReuseRecord = true
+	// Delimiter rows such as "Expansion" may have fewer fields than the header
+	cr.FieldsPerRecord = -1
 
 	fieldNames, err := cr.Read()
 	if err != nil {
@@ -60,9 +62,15 @@ func (d *DataDictionary) Next() bool {
 	return true
 }
 
-// String gets a string from the given column
+// String gets a string from the given column, which is empty if the
+// column doesn't exist or the row is too short
 func (d *DataDictionary) String(field string) string {
-	return d.record[d.lookup[field]]
+	index, ok := d.lookup[field]
+	if !ok || index >= len(d.record) {
+		return ""
+	}
+
+	return d.record[index]
 }
 
 // Number gets a number for the given column
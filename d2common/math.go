@@ -92,6 +92,34 @@ func GetAngleBetween(p1X, p1Y, p2X, p2Y float64) int {
 	return iResult
 }
 
+// AngleToDirection converts an angle in degrees, as returned by GetAngleBetween, to one of the 64 entity facing
+// directions. The division is truncated towards zero, and results up to one turn out of range wrap around.
+func AngleToDirection(angle float64) int {
+	const (
+		directions          = 64
+		degreesPerDirection = 360.0 / directions
+		offset              = 45.0 - (degreesPerDirection / 2)
+	)
+
+	direction := int((angle - offset) / degreesPerDirection)
+
+	if direction >= directions {
+		direction -= directions
+	} else if direction < 0 {
+		direction += directions
+	}
+
+	return direction
+}
+
+// DirectionBetween returns the facing direction, one of 64, of an entity at the first point looking at the second
+// point.
+func DirectionBetween(p1X, p1Y, p2X, p2Y float64) int {
+	const maxAngle = 359
+
+	return AngleToDirection(float64(maxAngle - GetAngleBetween(p1X, p1Y, p2X, p2Y)))
+}
+
 // GetRadiansBetween returns the radians between two points. 0rad is facing to the right.
 func GetRadiansBetween(p1X, p1Y, p2X, p2Y float64) float64 {
 	deltaY := p2Y - p1Y
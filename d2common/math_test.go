@@ -0,0 +1,72 @@
+package d2common
+
+import (
+	"testing"
+)
+
+func TestGetAngleBetween(t *testing.T) {
+	tests := []struct {
+		name   string
+		toX    float64
+		toY    float64
+		result int
+	}{
+		{"right", 1, 0, 0},
+		{"up", 0, -1, 90},
+		{"left", -1, 0, 180},
+		{"down", 0, 1, 270},
+	}
+
+	for _, test := range tests {
+		if got := GetAngleBetween(0, 0, test.toX, test.toY); got != test.result {
+			t.Errorf("angle %s: wanted %d: got %d", test.name, test.result, got)
+		}
+	}
+}
+
+func TestAngleToDirection(t *testing.T) {
+	tests := []struct {
+		angle     float64
+		direction int
+	}{
+		{0, 57},
+		{30, 62},
+		{90, 8},
+		{180, 24},
+		{270, 40},
+		{359.99, 56},
+		{360, 56},
+		{-90, 41},
+		// direction 0 starts half a direction before 45 degrees, the division truncates towards zero
+		{42.1875, 0},
+		{42.18, 0},
+		{47.8125, 1},
+		{405, 0},
+	}
+
+	for _, test := range tests {
+		if got := AngleToDirection(test.angle); got != test.direction {
+			t.Errorf("direction of %.4f degrees: wanted %d: got %d", test.angle, test.direction, got)
+		}
+	}
+}
+
+func TestDirectionBetween(t *testing.T) {
+	tests := []struct {
+		name      string
+		toX       float64
+		toY       float64
+		direction int
+	}{
+		{"right", 5, 0, 56},
+		{"down", 0, 5, 8},
+		{"left", -5, 0, 24},
+		{"up", 0, -5, 40},
+	}
+
+	for _, test := range tests {
+		if got := DirectionBetween(10, 10, 10+test.toX, 10+test.toY); got != test.direction {
+			t.Errorf("direction %s: wanted %d: got %d", test.name, test.direction, got)
+		}
+	}
+}
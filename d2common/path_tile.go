@@ -1,6 +1,10 @@
 package d2common
 
-import "github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+)
 
 // PathTile represents a node in path finding
 type PathTile struct {
@@ -71,3 +75,135 @@ func (t *PathTile) PathEstimatedCost(to d2astar.Pather) float64 {
 
 	return r
 }
+
+// LinkPathTiles connects each walkable tile in a grid of the given width to its walkable neighbors. When
+// allowDiagonal is true, diagonal neighbors are only linked if both tiles orthogonally adjacent to the diagonal step
+// are walkable, so paths never cut across the corner of a blocked tile.
+func LinkPathTiles(tiles []PathTile, width int, allowDiagonal bool) {
+	if width <= 0 {
+		return
+	}
+
+	height := len(tiles) / width
+
+	walkable := func(x, y int) bool {
+		return x >= 0 && x < width && y >= 0 && y < height && tiles[x+(y*width)].Walkable
+	}
+
+	for idx := range tiles {
+		tile := &tiles[idx]
+		tile.Up, tile.Down, tile.Left, tile.Right = nil, nil, nil, nil
+		tile.UpLeft, tile.UpRight, tile.DownLeft, tile.DownRight = nil, nil, nil, nil
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if !walkable(x, y) {
+				continue
+			}
+
+			tile := &tiles[x+(y*width)]
+
+			if walkable(x+1, y) {
+				tile.Right = &tiles[(x+1)+(y*width)]
+				tile.Right.Left = tile
+			}
+
+			if walkable(x, y+1) {
+				tile.Down = &tiles[x+((y+1)*width)]
+				tile.Down.Up = tile
+			}
+
+			if !allowDiagonal || !walkable(x, y+1) {
+				continue
+			}
+
+			if walkable(x+1, y) && walkable(x+1, y+1) {
+				tile.DownRight = &tiles[(x+1)+((y+1)*width)]
+				tile.DownRight.UpLeft = tile
+			}
+
+			if walkable(x-1, y) && walkable(x-1, y+1) {
+				tile.DownLeft = &tiles[(x-1)+((y+1)*width)]
+				tile.DownLeft.UpRight = tile
+			}
+		}
+	}
+}
+
+// subTilePosition returns the position of this node in sub-tiles
+func (t *PathTile) subTilePosition() (x, y int) {
+	return int(math.Round(t.X * 5)), int(math.Round(t.Y * 5))
+}
+
+// SmoothPath removes intermediate nodes from a path wherever there is a clear line of sight between the node before
+// and the node after them. The line of sight function receives sub-tile coordinates. Paths containing nodes that
+// are not PathTiles are returned unchanged.
+func SmoothPath(path []d2astar.Pather, losFunc func(x0, y0, x1, y1 int) bool) []d2astar.Pather {
+	if len(path) < 3 || losFunc == nil {
+		return path
+	}
+
+	tiles := make([]*PathTile, len(path))
+
+	for idx := range path {
+		tile, ok := path[idx].(*PathTile)
+		if !ok {
+			return path
+		}
+
+		tiles[idx] = tile
+	}
+
+	result := make([]d2astar.Pather, 0, len(path))
+	result = append(result, path[0])
+	anchor := tiles[0]
+
+	for idx := 1; idx < len(tiles)-1; idx++ {
+		x0, y0 := anchor.subTilePosition()
+		x1, y1 := tiles[idx+1].subTilePosition()
+
+		if losFunc(x0, y0, x1, y1) {
+			continue
+		}
+
+		result = append(result, path[idx])
+		anchor = tiles[idx]
+	}
+
+	return append(result, path[len(path)-1])
+}
+
+// NearestWalkable searches outward from the given tile, ring by ring, for the closest tile which isWalkable accepts,
+// searching at most maxRadius tiles away along either axis. The tile itself is returned if it is walkable. Among
+// tiles at the same distance, the first one found wins. ok is false if there is no walkable tile within the radius.
+func NearestWalkable(x, y, maxRadius int, isWalkable func(x, y int) bool) (nearestX, nearestY int, ok bool) {
+	bestDistance := -1
+
+	for radius := 0; radius <= maxRadius; radius++ {
+		// tiles on this ring and further out are at least radius tiles away
+		if bestDistance >= 0 && radius*radius > bestDistance {
+			break
+		}
+
+		for offsetY := -radius; offsetY <= radius; offsetY++ {
+			for offsetX := -radius; offsetX <= radius; offsetX++ {
+				onRing := offsetX == -radius || offsetX == radius || offsetY == -radius || offsetY == radius
+				if !onRing {
+					continue
+				}
+
+				distance := offsetX*offsetX + offsetY*offsetY
+				if bestDistance >= 0 && distance >= bestDistance {
+					continue
+				}
+
+				if isWalkable(x+offsetX, y+offsetY) {
+					nearestX, nearestY, bestDistance = x+offsetX, y+offsetY, distance
+				}
+			}
+		}
+	}
+
+	return nearestX, nearestY, bestDistance >= 0
+}
@@ -0,0 +1,137 @@
+package d2common
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+)
+
+func TestSmoothPathStraightCorridor(t *testing.T) {
+	path := make([]d2astar.Pather, 0)
+
+	for x := 0; x < 10; x++ {
+		path = append(path, &PathTile{X: float64(x) / 5, Y: 1})
+	}
+
+	los := func(x0, y0, x1, y1 int) bool {
+		return y0 == y1
+	}
+
+	smoothed := SmoothPath(path, los)
+
+	if len(smoothed) != 2 {
+		t.Fatalf("smoothed path length: wanted %d: got %d", 2, len(smoothed))
+	}
+
+	if smoothed[0] != path[0] || smoothed[1] != path[len(path)-1] {
+		t.Error("smoothed path should keep the original end points")
+	}
+}
+
+func TestSmoothPathBlocked(t *testing.T) {
+	path := []d2astar.Pather{
+		&PathTile{X: 0, Y: 0},
+		&PathTile{X: 1, Y: 0},
+		&PathTile{X: 1, Y: 1},
+	}
+
+	los := func(x0, y0, x1, y1 int) bool {
+		return x0 == x1 || y0 == y1
+	}
+
+	if smoothed := SmoothPath(path, los); len(smoothed) != len(path) {
+		t.Errorf("smoothed path length: wanted %d: got %d", len(path), len(smoothed))
+	}
+}
+
+// parseWalkMesh creates linked path tiles from rows of '.' (walkable) and 'X' (blocked) characters.
+func parseWalkMesh(rows []string, allowDiagonal bool) (tiles []PathTile, width int) {
+	width = len(rows[0])
+	tiles = make([]PathTile, 0, width*len(rows))
+
+	for y, row := range rows {
+		for x, r := range row {
+			tiles = append(tiles, PathTile{Walkable: r != 'X', X: float64(x), Y: float64(y)})
+		}
+	}
+
+	LinkPathTiles(tiles, width, allowDiagonal)
+
+	return tiles, width
+}
+
+func TestLinkPathTilesOpenDiagonal(t *testing.T) {
+	tiles, width := parseWalkMesh([]string{
+		"....",
+		"....",
+		"....",
+		"....",
+	}, true)
+
+	path, _, found := d2astar.Path(&tiles[0], &tiles[3+(3*width)], 100)
+	if !found {
+		t.Fatal("could not find a path")
+	}
+
+	if len(path) != 4 {
+		t.Errorf("diagonal path length: wanted %d: got %d", 4, len(path))
+	}
+}
+
+func TestLinkPathTilesBlockedCorner(t *testing.T) {
+	tiles, width := parseWalkMesh([]string{
+		".X..",
+		"....",
+	}, true)
+
+	if tiles[0].DownRight != nil {
+		t.Error("diagonal step should not cut the corner of a blocked tile")
+	}
+
+	path, _, found := d2astar.Path(&tiles[0], &tiles[1+width], 100)
+	if !found {
+		t.Fatal("could not find a path")
+	}
+
+	if len(path) != 3 {
+		t.Errorf("path around corner length: wanted %d: got %d", 3, len(path))
+	}
+
+	tiles, _ = parseWalkMesh([]string{
+		"..",
+		"..",
+	}, false)
+
+	if tiles[0].DownRight != nil {
+		t.Error("diagonal steps should not be linked when diagonals are not allowed")
+	}
+}
+
+func TestNearestWalkable(t *testing.T) {
+	// a wall along x = 5, with open floor everywhere else
+	isWalkable := func(x, y int) bool {
+		return x != 5
+	}
+
+	x, y, ok := NearestWalkable(5, 3, 4, isWalkable)
+	if !ok || y != 3 || (x != 4 && x != 6) {
+		t.Errorf("nearest to a wall tile: wanted an adjacent tile: got (%d, %d, %v)", x, y, ok)
+	}
+
+	x, y, ok = NearestWalkable(2, 3, 4, isWalkable)
+	if !ok || x != 2 || y != 3 {
+		t.Errorf("nearest to a walkable tile: wanted (%d, %d): got (%d, %d, %v)", 2, 3, x, y, ok)
+	}
+
+	// only a single tile diagonally away is open
+	x, y, ok = NearestWalkable(0, 0, 4, func(x, y int) bool {
+		return x == 2 && y == -2
+	})
+	if !ok || x != 2 || y != -2 {
+		t.Errorf("nearest to an enclosed tile: wanted (%d, %d): got (%d, %d, %v)", 2, -2, x, y, ok)
+	}
+
+	if _, _, ok = NearestWalkable(0, 0, 1, func(x, y int) bool { return x == 2 }); ok {
+		t.Error("no walkable tile within the radius should not be found")
+	}
+}
@@ -18,6 +18,19 @@ func (v *Rectangle) Right() int {
 	return v.Left + v.Width
 }
 
+// Intersect returns the area covered by both rectangles, which is empty if they don't overlap
+func (v *Rectangle) Intersect(other Rectangle) Rectangle {
+	left, top := MaxInt(v.Left, other.Left), MaxInt(v.Top, other.Top)
+	right, bottom := MinInt(v.Right(), other.Right()), MinInt(v.Bottom(), other.Bottom())
+
+	return Rectangle{Left: left, Top: top, Width: MaxInt(0, right-left), Height: MaxInt(0, bottom-top)}
+}
+
+// Intersects returns true if the rectangles overlap
+func (v *Rectangle) Intersects(other Rectangle) bool {
+	return v.Left < other.Right() && other.Left < v.Right() && v.Top < other.Bottom() && other.Top < v.Bottom()
+}
+
 // IsInRect returns if the given position is in the rectangle or not
 func (v *Rectangle) IsInRect(x, y int) bool {
 	return x >= v.Left && x < v.Left+v.Width && y >= v.Top && y < v.Top+v.Height
@@ -3,6 +3,8 @@ package d2common
 import (
 	"log"
 	"strconv"
+	"sync"
+	"sync/atomic"
 )
 
 type textDictionaryHashEntry struct {
@@ -14,11 +16,23 @@ type textDictionaryHashEntry struct {
 	NameLength  uint16
 }
 
-var lookupTable map[string]string
+var (
+	// lookupTable holds the map[string]string of translations. The map is never modified once stored, so lookups
+	// never see a partially loaded table.
+	lookupTable atomic.Value
+
+	// lookupTableMutex serializes loading tables
+	lookupTableMutex sync.Mutex
+)
+
+func currentLookupTable() map[string]string {
+	table, _ := lookupTable.Load().(map[string]string)
+	return table
+}
 
 // TranslateString returns the translation of the given string
 func TranslateString(key string) string {
-	result, ok := lookupTable[key]
+	result, ok := currentLookupTable()[key]
 	if !ok {
 		// Fix to allow v.setDescLabels("#123") to be bypassed for a patch in issue #360. Reenable later.
 		// log.Panicf("Could not find a string for the key '%s'", key)
@@ -28,12 +42,40 @@ func TranslateString(key string) string {
 	return result
 }
 
-// LoadTextDictionary loads the text dictionary from the given data
+// LoadTextDictionary loads the text dictionary from the given data. Keys which were already loaded keep their
+// translation.
 func LoadTextDictionary(dictionaryData []byte) {
-	if lookupTable == nil {
-		lookupTable = make(map[string]string)
+	lookupTableMutex.Lock()
+	defer lookupTableMutex.Unlock()
+
+	current := currentLookupTable()
+	table := make(map[string]string, len(current))
+
+	for key, value := range current {
+		table[key] = value
+	}
+
+	parseTextDictionary(table, dictionaryData)
+	lookupTable.Store(table)
+}
+
+// ReloadTextDictionaries replaces the loaded translations with the given text dictionaries, in the order they were
+// loaded with LoadTextDictionary. Lookups made during the reload return the previous translations.
+func ReloadTextDictionaries(dictionariesData ...[]byte) {
+	lookupTableMutex.Lock()
+	defer lookupTableMutex.Unlock()
+
+	table := make(map[string]string)
+
+	for _, dictionaryData := range dictionariesData {
+		parseTextDictionary(table, dictionaryData)
 	}
 
+	lookupTable.Store(table)
+}
+
+// parseTextDictionary adds the translations of the text dictionary to the table, keeping existing translations
+func parseTextDictionary(lookupTable map[string]string, dictionaryData []byte) {
 	br := CreateStreamReader(dictionaryData)
 	// CRC
 	br.ReadBytes(2)
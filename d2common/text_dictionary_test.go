@@ -0,0 +1,83 @@
+package d2common
+
+import (
+	"testing"
+)
+
+// testTextDictionary returns a text dictionary holding the given keys and values
+func testTextDictionary(keys, values []string) []byte {
+	const (
+		headerSize    = 21
+		hashEntrySize = 17
+	)
+
+	stringOffset := headerSize + len(keys)*2 + len(keys)*hashEntrySize
+
+	sw := CreateStreamWriter()
+	sw.PushUint16(0) // CRC
+	sw.PushUint16(uint16(len(keys)))
+	sw.PushUint32(uint32(len(keys))) // Hash table size
+	sw.PushByte(0)                   // Version
+	sw.PushUint32(uint32(stringOffset))
+	sw.PushUint32(0) // Max tries
+	sw.PushUint32(0) // File size
+
+	for i := range keys {
+		sw.PushUint16(uint16(i))
+	}
+
+	var strings []byte
+
+	for i := range keys {
+		keyOffset := stringOffset + len(strings)
+		strings = append(append(strings, keys[i]...), 0)
+
+		valueOffset := stringOffset + len(strings)
+		strings = append(append(strings, values[i]...), 0)
+
+		sw.PushByte(1) // Active
+		sw.PushUint16(uint16(i))
+		sw.PushUint32(0) // Hash value
+		sw.PushUint32(uint32(keyOffset))
+		sw.PushUint32(uint32(valueOffset))
+		sw.PushUint16(uint16(len(values[i]) + 1))
+	}
+
+	for _, b := range strings {
+		sw.PushByte(b)
+	}
+
+	return sw.GetBytes()
+}
+
+func TestReloadTextDictionaries(t *testing.T) {
+	LoadTextDictionary(testTextDictionary([]string{"greeting", "farewell"}, []string{"Hello", "Goodbye"}))
+
+	if got := TranslateString("greeting"); got != "Hello" {
+		t.Errorf("loaded translation: wanted %q: got %q", "Hello", got)
+	}
+
+	ReloadTextDictionaries(testTextDictionary([]string{"greeting"}, []string{"Greetings"}))
+
+	if got := TranslateString("greeting"); got != "Greetings" {
+		t.Errorf("reloaded translation: wanted %q: got %q", "Greetings", got)
+	}
+
+	// Keys missing from the reloaded dictionaries are dropped
+	if got := TranslateString("farewell"); got != "farewell" {
+		t.Errorf("removed translation: wanted %q: got %q", "farewell", got)
+	}
+}
+
+func TestLoadTextDictionaryKeepsExisting(t *testing.T) {
+	ReloadTextDictionaries(testTextDictionary([]string{"name"}, []string{"Patched"}))
+	LoadTextDictionary(testTextDictionary([]string{"name", "other"}, []string{"Original", "Other"}))
+
+	if got := TranslateString("name"); got != "Patched" {
+		t.Errorf("translation of a key loaded twice: wanted %q: got %q", "Patched", got)
+	}
+
+	if got := TranslateString("other"); got != "Other" {
+		t.Errorf("translation: wanted %q: got %q", "Other", got)
+	}
+}
@@ -83,6 +83,38 @@ func (am *archiveManager) LoadArchive(archivePath string) (d2interface.Archive,
 	return archive, nil
 }
 
+// ReloadArchivesForFile closes and reopens the archives containing the given (in-archive) file path, so changes
+// made to them on disk since they were opened are read
+func (am *archiveManager) ReloadArchivesForFile(filePath string) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	if err := am.CacheArchiveEntries(); err != nil {
+		return err
+	}
+
+	for idx, archive := range am.archives {
+		if !archive.Contains(filePath) {
+			continue
+		}
+
+		archivePath := archive.Path()
+
+		am.cache.Remove(archivePath)
+
+		archive.Close()
+
+		reloaded, err := am.LoadArchive(archivePath)
+		if err != nil {
+			return err
+		}
+
+		am.archives[idx] = reloaded
+	}
+
+	return nil
+}
+
 // CacheArchiveEntries updates the archive entries
 func (am *archiveManager) CacheArchiveEntries() error {
 	if len(am.archives) == len(am.config.MpqLoadOrder) {
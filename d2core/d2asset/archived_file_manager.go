@@ -70,6 +70,15 @@ func (fm *fileManager) FileExists(filePath string) (bool, error) {
 	return fm.archiveManager.FileExistsInArchive(filePath)
 }
 
+// ReloadFile removes a file from the cache and reopens the archives containing it, so changes made to it on disk
+// are read the next time it is loaded
+func (fm *fileManager) ReloadFile(filePath string) error {
+	filePath = fm.fixupFilePath(filePath)
+	fm.cache.Remove(filePath)
+
+	return fm.archiveManager.ReloadArchivesForFile(filePath)
+}
+
 func (fm *fileManager) ClearCache() {
 	fm.cache.Clear()
 }
@@ -0,0 +1,115 @@
+package d2asset
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2mpq"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
+)
+
+// testStringTable returns a string table holding a single key and value
+func testStringTable(key, value string) []byte {
+	const (
+		headerSize    = 21
+		hashEntrySize = 17
+	)
+
+	stringOffset := headerSize + 2 + hashEntrySize
+
+	sw := d2common.CreateStreamWriter()
+	sw.PushUint16(0) // CRC
+	sw.PushUint16(1) // Number of elements
+	sw.PushUint32(1) // Hash table size
+	sw.PushByte(0)   // Version
+	sw.PushUint32(uint32(stringOffset))
+	sw.PushUint32(0) // Max tries
+	sw.PushUint32(0) // File size
+	sw.PushUint16(0) // Element index
+
+	sw.PushByte(1)   // Active
+	sw.PushUint16(0) // Index
+	sw.PushUint32(0) // Hash value
+	sw.PushUint32(uint32(stringOffset))
+	sw.PushUint32(uint32(stringOffset + len(key) + 1))
+	sw.PushUint16(uint16(len(value) + 1))
+
+	for _, b := range append(append(append([]byte(key), 0), value...), 0) {
+		sw.PushByte(b)
+	}
+
+	return sw.GetBytes()
+}
+
+func writeStringTableArchive(t *testing.T, archivePath, tablePath, value string) {
+	writer := d2mpq.NewWriter()
+
+	// the padding depends on the value, so rewriting the archive moves the table and stale block tables read garbage
+	padding := bytes.Repeat([]byte(value), 100)
+
+	if err := writer.AddFile(`data\global\padding.txt`, padding, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.AddFile(tablePath, testStringTable("reloadTestKey", value), false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Save(archivePath); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "d2asset")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	config := &d2config.Configuration{
+		MpqPath:      dir,
+		MpqLoadOrder: []string{"patch.mpq"},
+		Language:     "ENG",
+	}
+
+	fm := createFileManager(config, createArchiveManager(config))
+	tablePath := fm.(*fileManager).fixupFilePath(d2resource.StringTable)
+	archivePath := filepath.Join(dir, "patch.mpq")
+
+	writeStringTableArchive(t, archivePath, tablePath, "Before")
+
+	data, err := fm.LoadFile(d2resource.StringTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d2common.ReloadTextDictionaries(data)
+
+	if got := d2common.TranslateString("reloadTestKey"); got != "Before" {
+		t.Fatalf("translation before reload: wanted %q: got %q", "Before", got)
+	}
+
+	writeStringTableArchive(t, archivePath, tablePath, "After")
+
+	if err := fm.ReloadFile(d2resource.StringTable); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err = fm.LoadFile(d2resource.StringTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d2common.ReloadTextDictionaries(data)
+
+	if got := d2common.TranslateString("reloadTestKey"); got != "After" {
+		t.Errorf("translation after reload: wanted %q: got %q", "After", got)
+	}
+}
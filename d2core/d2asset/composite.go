@@ -3,7 +3,6 @@ package d2asset
 import (
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
@@ -59,7 +58,7 @@ func (c *Composite) Render(target d2interface.Surface) error {
 	}
 
 	direction := d2cof.Dir64ToCof(c.direction, c.mode.cof.NumberOfDirections)
-	for _, layerIndex := range c.mode.cof.Priority[direction][c.mode.frameIndex] {
+	for _, layerIndex := range c.mode.cof.LayerOrder(direction, c.mode.frameIndex) {
 		layer := c.mode.layers[layerIndex]
 		if layer != nil {
 			if err := layer.RenderFromOrigin(target); err != nil {
@@ -123,7 +122,7 @@ func (c *Composite) Equip(equipment *[d2enum.CompositeTypeMax]string) error {
 
 // SetAnimSpeed sets the speed at which the Composite's animation should advance through its frames
 func (c *Composite) SetAnimSpeed(speed int) {
-	c.mode.animationSpeed = 1.0 / ((float64(speed) * 25.0) / 256.0)
+	c.mode.animationSpeed = d2data.AnimationFrameDuration(speed)
 	for layerIdx := range c.mode.layers {
 		layer := c.mode.layers[layerIdx]
 		if layer != nil {
@@ -222,11 +221,13 @@ func (c *Composite) createMode(animationMode animationMode, weaponClass string)
 		return nil, err
 	}
 
-	animationKey := strings.ToLower(c.token + animationMode.String() + weaponClass)
+	// Animations without animation data play every frame of the COF at the default speed
+	frameCount := cof.FramesPerDirection
+	animationSpeed := d2data.AnimationFrameDuration(d2data.DefaultAnimationSpeed)
 
-	animationData := d2data.AnimationData[animationKey]
-	if len(animationData) == 0 {
-		return nil, errors.New("could not find animation data")
+	if animationData, found := d2data.GetAnimationData(c.token, animationMode.String(), weaponClass); found {
+		frameCount = animationData.FramesPerDirection
+		animationSpeed = animationData.FrameDuration()
 	}
 
 	mode := &compositeMode{
@@ -234,8 +235,8 @@ func (c *Composite) createMode(animationMode animationMode, weaponClass string)
 		animationMode:  animationMode,
 		weaponClass:    weaponClass,
 		layers:         make([]d2interface.Animation, d2enum.CompositeTypeMax),
-		frameCount:     animationData[0].FramesPerDirection,
-		animationSpeed: 1.0 / ((float64(animationData[0].AnimationSpeed) * 25.0) / 256.0),
+		frameCount:     frameCount,
+		animationSpeed: animationSpeed,
 	}
 
 	for _, cofLayer := range cof.CofLayers {
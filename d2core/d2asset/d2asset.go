@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2pl2"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2config"
 )
@@ -97,6 +98,12 @@ func LoadFile(filePath string) ([]byte, error) {
 	return data, err
 }
 
+// ReloadFile drops the cached copy of a file and reopens the archives containing it, so changes made to it on disk
+// are read the next time it is loaded
+func ReloadFile(filePath string) error {
+	return singleton.archivedFileManager.ReloadFile(filePath)
+}
+
 // FileExists checks if a file exists on the underlying file system at the given file path.
 func FileExists(filePath string) (bool, error) {
 	return singleton.archivedFileManager.FileExists(filePath)
@@ -127,3 +134,8 @@ func LoadFont(tablePath, spritePath, palettePath string) (d2interface.Font, erro
 func LoadPalette(palettePath string) (d2interface.Palette, error) {
 	return singleton.paletteManager.LoadPalette(palettePath)
 }
+
+// LoadPaletteTransform loads a PL2 palette transform from a given path
+func LoadPaletteTransform(transformPath string) (*d2pl2.PL2, error) {
+	return singleton.paletteTransformManager.loadPaletteTransform(transformPath)
+}
@@ -17,11 +17,17 @@ type fontGlyph struct {
 	height int
 }
 
+// kerningPair is a pair of adjacent characters
+type kerningPair struct {
+	left, right rune
+}
+
 // Font represents a displayable font
 type Font struct {
-	sheet  d2interface.Animation
-	glyphs map[rune]fontGlyph
-	color  color.Color
+	sheet   d2interface.Animation
+	glyphs  map[rune]fontGlyph
+	kerning map[kerningPair]int
+	color   color.Color
 }
 
 func loadFont(tablePath, spritePath, palettePath string) (d2interface.Font, error) {
@@ -55,9 +61,10 @@ func loadFont(tablePath, spritePath, palettePath string) (d2interface.Font, erro
 	}
 
 	font := &Font{
-		sheet:  sheet,
-		glyphs: glyphs,
-		color:  color.White,
+		sheet:   sheet,
+		glyphs:  glyphs,
+		kerning: make(map[kerningPair]int),
+		color:   color.White,
 	}
 
 	return font, nil
@@ -68,29 +75,50 @@ func (f *Font) SetColor(c color.Color) {
 	f.color = c
 }
 
+// SetKerning sets the number of pixels added to the advance from the left character to the right one, negative
+// values move the right character closer
+func (f *Font) SetKerning(left, right rune, adjustment int) {
+	if f.kerning == nil {
+		f.kerning = make(map[kerningPair]int)
+	}
+
+	f.kerning[kerningPair{left, right}] = adjustment
+}
+
+// advance returns how many pixels the glyph of the character at the index moves the following text
+func (f *Font) advance(glyph fontGlyph, line []rune, index int) int {
+	if index+1 >= len(line) {
+		return glyph.width
+	}
+
+	return glyph.width + f.kerning[kerningPair{line[index], line[index+1]}]
+}
+
 // GetTextMetrics returns the dimensions of the Font element in pixels
 func (f *Font) GetTextMetrics(text string) (width, height int) {
 	var (
-		lineWidth   int
-		lineHeight  int
 		totalWidth  int
 		totalHeight int
 	)
 
-	for _, c := range text {
-		if c == '\n' {
-			totalWidth = d2common.MaxInt(totalWidth, lineWidth)
-			totalHeight += lineHeight
-			lineWidth = 0
-			lineHeight = 0
-		} else if glyph, ok := f.glyphs[c]; ok {
-			lineWidth += glyph.width
-			lineHeight = d2common.MaxInt(lineHeight, glyph.height)
+	for _, line := range strings.Split(text, "\n") {
+		var (
+			lineWidth  int
+			lineHeight int
+		)
+
+		runes := []rune(line)
+
+		for i, c := range runes {
+			if glyph, ok := f.glyphs[c]; ok {
+				lineWidth += f.advance(glyph, runes, i)
+				lineHeight = d2common.MaxInt(lineHeight, glyph.height)
+			}
 		}
-	}
 
-	totalWidth = d2common.MaxInt(totalWidth, lineWidth)
-	totalHeight += lineHeight
+		totalWidth = d2common.MaxInt(totalWidth, lineWidth)
+		totalHeight += lineHeight
+	}
 
 	return totalWidth, totalHeight
 }
@@ -107,7 +135,9 @@ func (f *Font) RenderText(text string, target d2interface.Surface) error {
 			lineLength int
 		)
 
-		for _, c := range line {
+		runes := []rune(line)
+
+		for i, c := range runes {
 			glyph, ok := f.glyphs[c]
 			if !ok {
 				continue
@@ -124,7 +154,7 @@ func (f *Font) RenderText(text string, target d2interface.Surface) error {
 			lineHeight = d2common.MaxInt(lineHeight, glyph.height)
 			lineLength++
 
-			target.PushTranslation(glyph.width, 0)
+			target.PushTranslation(f.advance(glyph, runes, i), 0)
 		}
 
 		target.PopN(lineLength)
@@ -0,0 +1,49 @@
+package d2asset
+
+import (
+	"testing"
+)
+
+func testFont() *Font {
+	return &Font{
+		glyphs: map[rune]fontGlyph{
+			'i': {width: 3, height: 10},
+			'l': {width: 3, height: 10},
+			'W': {width: 12, height: 12},
+		},
+	}
+}
+
+func TestFontProportionalMetrics(t *testing.T) {
+	font := testFont()
+
+	width, height := font.GetTextMetrics("iWl")
+
+	if fixedWidth := 3 * 12; width >= fixedWidth {
+		t.Errorf("width: wanted less than the fixed width %d: got %d", fixedWidth, width)
+	}
+
+	if width != 18 || height != 12 {
+		t.Errorf("size: wanted (%d, %d): got (%d, %d)", 18, 12, width, height)
+	}
+}
+
+func TestFontKerning(t *testing.T) {
+	font := testFont()
+	font.SetKerning('W', 'l', -2)
+
+	tests := []struct {
+		text   string
+		wanted int
+	}{
+		{"iWl", 16},
+		{"lW", 15},
+		{"Wl\nWl", 13},
+	}
+
+	for _, test := range tests {
+		if width, _ := font.GetTextMetrics(test.text); width != test.wanted {
+			t.Errorf("width of %q: wanted %d: got %d", test.text, test.wanted, width)
+		}
+	}
+}
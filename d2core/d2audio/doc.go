@@ -0,0 +1,3 @@
+// Package d2audio contains the parts of the audio system that don't depend
+// on an audio backend, such as positional attenuation
+package d2audio
@@ -3,11 +3,11 @@ package ebiten
 
 import (
 	"log"
+	"math"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
-
-	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
-	"github.com/hajimehoshi/ebiten/audio/wav"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2audio"
 
 	"github.com/hajimehoshi/ebiten/audio"
 )
@@ -17,15 +17,22 @@ const sampleRate = 44100
 // AudioProvider represents a provider capable of playing audio
 type AudioProvider struct {
 	audioContext *audio.Context // The Audio context
-	bgmAudio     *audio.Player  // The audio player
-	lastBgm      string
-	sfxVolume    float64
-	bgmVolume    float64
+	music        *d2audio.MusicPlayer
+	volumes      *d2audio.Volumes
+	listener     *d2audio.Listener
+	voices       *d2audio.VoicePool
+	loops        *d2audio.LoopPlayer
+	pitch        *d2audio.PitchVariation
 }
 
 // CreateAudio creates an instance of ebiten's audio provider
 func CreateAudio() (*AudioProvider, error) {
-	result := &AudioProvider{}
+	result := &AudioProvider{
+		volumes:  d2audio.NewVolumes(),
+		listener: d2audio.NewListener(),
+		voices:   d2audio.NewVoicePool(),
+		pitch:    d2audio.NewPitchVariation(nil),
+	}
 
 	var err error
 	result.audioContext, err = audio.NewContext(sampleRate)
@@ -35,75 +42,117 @@ func CreateAudio() (*AudioProvider, error) {
 		return nil, err
 	}
 
+	result.music = d2audio.NewMusicPlayer(func(song string) (d2audio.MusicTrack, error) {
+		return loadMusicTrack(song, result.audioContext)
+	})
+	result.music.SetVolume(result.volumes.Gain(d2enum.SoundCategoryMusic))
+
+	result.loops = d2audio.NewLoopPlayer(func(sfx string) (d2audio.MusicTrack, error) {
+		return newMusicTrack(loadSoundStream(sfx, result.audioContext), result.audioContext)
+	})
+	result.loops.SetVolume(result.volumes.Gain(d2enum.SoundCategoryEffects))
+
 	return result, nil
 }
 
-// PlayBGM loads an audio stream and plays it in the background
+// PlayBGM loads an audio stream and plays it in the background, replacing
+// the current track immediately
 func (eap *AudioProvider) PlayBGM(song string) {
-	if eap.lastBgm == song {
-		return
-	}
-
-	eap.lastBgm = song
-
-	if song == "" && eap.bgmAudio != nil && eap.bgmAudio.IsPlaying() {
-		_ = eap.bgmAudio.Pause()
+	eap.CrossfadeTo(song, 0)
+}
 
-		return
+// CrossfadeTo fades the current background music out while fading the given
+// song in over the duration, in seconds
+func (eap *AudioProvider) CrossfadeTo(song string, duration float64) {
+	if err := eap.music.CrossfadeTo(song, duration); err != nil {
+		log.Printf("failed to play music %s: %v", song, err)
 	}
+}
 
-	if eap.bgmAudio != nil {
-		err := eap.bgmAudio.Close()
-
-		if err != nil {
-			log.Panic(err)
-		}
+// Advance moves the music and looping sound fades along
+func (eap *AudioProvider) Advance(elapsed float64) error {
+	if err := eap.music.Advance(elapsed); err != nil {
+		return err
 	}
 
-	audioStream, err := d2asset.LoadFileStream(song)
+	return eap.loops.Advance(elapsed)
+}
 
+// PlayLoop starts looping a sound, such as area ambience. If the sound is
+// already looping its handle is returned.
+func (eap *AudioProvider) PlayLoop(sfx string) (d2interface.SoundLoop, error) {
+	loop, err := eap.loops.PlayLoop(sfx)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
-	d, err := wav.Decode(eap.audioContext, audioStream)
+	return loop, nil
+}
 
-	if err != nil {
-		log.Fatal(err)
-	}
+// LoadSoundEffect loads a sound affect so that it canb e played at the volume of the given category
+func (eap *AudioProvider) LoadSoundEffect(sfx string, category d2enum.SoundCategory) (d2interface.SoundEffect, error) {
+	result := CreateSoundEffect(sfx, eap.audioContext, eap.volumes, category) // TODO: Split
 
-	s := audio.NewInfiniteLoop(d, d.Length())
-	eap.bgmAudio, err = audio.NewPlayer(eap.audioContext, s)
+	return result, nil
+}
 
-	if err != nil {
-		log.Fatal(err)
-	}
+// SetCategoryVolume sets the volume level of a sound category, between 0 and 1
+func (eap *AudioProvider) SetCategoryVolume(category d2enum.SoundCategory, level float64) {
+	eap.volumes.SetCategoryVolume(category, level)
+	eap.music.SetVolume(eap.volumes.Gain(d2enum.SoundCategoryMusic))
+	eap.loops.SetVolume(eap.volumes.Gain(d2enum.SoundCategoryEffects))
+}
 
-	eap.bgmAudio.SetVolume(eap.bgmVolume)
+// GetCategoryVolume returns the volume level of a sound category
+func (eap *AudioProvider) GetCategoryVolume(category d2enum.SoundCategory) float64 {
+	return eap.volumes.CategoryVolume(category)
+}
 
-	// Play the infinite-length stream. This never ends.
-	err = eap.bgmAudio.Rewind()
+// SetListenerPosition sets the world position positional sounds are heard from
+func (eap *AudioProvider) SetListenerPosition(x, y float64) {
+	eap.listener.SetPosition(x, y)
+}
 
-	if err != nil {
-		panic(err)
+// PlayAt plays a sound effect emitted at the given world position. The sound
+// is attenuated by its distance from the listener, and panned towards the
+// side of the listener it is on. When too many sounds are playing, the
+// quietest one is stopped.
+func (eap *AudioProvider) PlayAt(sfx string, x, y float64) {
+	left, right := eap.listener.Gain(x, y)
+	if left == 0 && right == 0 {
+		return
 	}
 
-	err = eap.bgmAudio.Play()
+	var source audio.ReadSeekCloser = loadSoundStream(sfx, eap.audioContext)
 
-	if err != nil {
-		panic(err)
+	if factor := eap.pitch.Factor(sfx); factor != 1 {
+		resampled, err := resampleStream(source, factor)
+		if err != nil {
+			log.Printf("failed to vary the pitch of %s: %v", sfx, err)
+			return
+		}
+
+		source = resampled
 	}
+
+	stream := newPannedStream(source, left, right)
+	effect := createSoundEffect(stream, eap.audioContext, eap.volumes, d2enum.SoundCategoryEffects)
+
+	eap.voices.Start(sfx, effect, math.Max(left, right))
 }
 
-// LoadSoundEffect loads a sound affect so that it canb e played
-func (eap *AudioProvider) LoadSoundEffect(sfx string) (d2interface.SoundEffect, error) {
-	result := CreateSoundEffect(sfx, eap.audioContext, eap.sfxVolume) // TODO: Split
+// SetPitchVariation sets how many semitones up or down the pitch of a
+// positional sound randomly varies by each time it is played
+func (eap *AudioProvider) SetPitchVariation(sfx string, semitones float64) {
+	eap.pitch.SetRange(sfx, semitones)
+}
 
-	return result, nil
+// SetMaxVoices sets how many positional sounds can play at once
+func (eap *AudioProvider) SetMaxVoices(maxVoices int) {
+	eap.voices.SetMaxVoices(maxVoices)
 }
 
-// SetVolumes sets the volumes of the audio provider
-func (eap *AudioProvider) SetVolumes(bgmVolume, sfxVolume float64) {
-	eap.sfxVolume = sfxVolume
-	eap.bgmVolume = bgmVolume
+// SetMaxInstances sets how many instances of a positional sound can play at once
+func (eap *AudioProvider) SetMaxInstances(sfx string, maxInstances int) {
+	eap.voices.SetMaxInstances(sfx, maxInstances)
 }
@@ -0,0 +1,54 @@
+package ebiten
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/hajimehoshi/ebiten/audio"
+	"github.com/hajimehoshi/ebiten/audio/wav"
+)
+
+// musicTrack is an endlessly looping stream, used for music and ambience
+type musicTrack struct {
+	player *audio.Player
+}
+
+func loadMusicTrack(song string, context *audio.Context) (*musicTrack, error) {
+	audioStream, err := d2asset.LoadFileStream(song)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := wav.Decode(context, audioStream)
+	if err != nil {
+		return nil, err
+	}
+
+	return newMusicTrack(d, context)
+}
+
+func newMusicTrack(d *wav.Stream, context *audio.Context) (*musicTrack, error) {
+	player, err := audio.NewPlayer(context, audio.NewInfiniteLoop(d, d.Length()))
+	if err != nil {
+		return nil, err
+	}
+
+	return &musicTrack{player: player}, nil
+}
+
+// Play plays the track from the start
+func (t *musicTrack) Play() error {
+	if err := t.player.Rewind(); err != nil {
+		return err
+	}
+
+	return t.player.Play()
+}
+
+// Stop stops the track and frees the player
+func (t *musicTrack) Stop() error {
+	return t.player.Close()
+}
+
+// SetVolume sets the volume of the track
+func (t *musicTrack) SetVolume(volume float64) {
+	t.player.SetVolume(volume)
+}
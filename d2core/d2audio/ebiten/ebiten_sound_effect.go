@@ -4,20 +4,41 @@ import (
 	"log"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2audio"
 	"github.com/hajimehoshi/ebiten/audio"
 	"github.com/hajimehoshi/ebiten/audio/wav"
 )
 
 // SoundEffect represents an ebiten implementation of a sound effect
 type SoundEffect struct {
-	player *audio.Player
+	player   *audio.Player
+	volumes  *d2audio.Volumes
+	category d2enum.SoundCategory
 }
 
-// CreateSoundEffect creates a new instance of ebiten's sound effect implementation.
-func CreateSoundEffect(sfx string, context *audio.Context, volume float64) *SoundEffect {
-	result := &SoundEffect{}
+// CreateSoundEffect creates a new instance of ebiten's sound effect implementation. The sound
+// is played at the volume of its category.
+func CreateSoundEffect(sfx string, context *audio.Context, volumes *d2audio.Volumes,
+	category d2enum.SoundCategory) *SoundEffect {
+	return createSoundEffect(loadSoundStream(sfx, context), context, volumes, category)
+}
+
+func createSoundEffect(stream audio.ReadSeekCloser, context *audio.Context, volumes *d2audio.Volumes,
+	category d2enum.SoundCategory) *SoundEffect {
+	player, err := audio.NewPlayer(context, stream)
 
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	return &SoundEffect{player: player, volumes: volumes, category: category}
+}
+
+// loadSoundStream decodes the file of the given sound, which is either a
+// sound name from the sound settings or a file path
+func loadSoundStream(sfx string, context *audio.Context) *wav.Stream {
 	var soundFile string
 
 	if _, exists := d2datadict.Sounds[sfx]; exists {
@@ -39,21 +60,13 @@ func CreateSoundEffect(sfx string, context *audio.Context, volume float64) *Soun
 		log.Fatal(err)
 	}
 
-	player, err := audio.NewPlayer(context, d)
-
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	player.SetVolume(volume)
-
-	result.player = player
-
-	return result
+	return d
 }
 
 // Play plays the sound effect
 func (v *SoundEffect) Play() {
+	v.player.SetVolume(v.volumes.Gain(v.category))
+
 	err := v.player.Rewind()
 
 	if err != nil {
@@ -67,6 +80,11 @@ func (v *SoundEffect) Play() {
 	}
 }
 
+// IsPlaying returns true if the sound effect is playing
+func (v *SoundEffect) IsPlaying() bool {
+	return v.player.IsPlaying()
+}
+
 // Stop stops the sound effect
 func (v *SoundEffect) Stop() {
 	err := v.player.Pause()
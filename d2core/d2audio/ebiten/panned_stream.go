@@ -0,0 +1,67 @@
+package ebiten
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+const (
+	bytesPerSample = 2
+	bytesPerFrame  = bytesPerSample * 2
+)
+
+// pannedStream scales the left and right channels of a decoded stream,
+// which holds 16 bit little endian stereo samples
+type pannedStream struct {
+	src   io.ReadSeeker
+	left  float64
+	right float64
+	pos   int64
+}
+
+func newPannedStream(src io.ReadSeeker, left, right float64) *pannedStream {
+	return &pannedStream{src: src, left: left, right: right}
+}
+
+// Read reads from the source stream and applies the channel gains
+func (s *pannedStream) Read(p []byte) (int, error) {
+	// Only read whole samples, so a sample is never split between reads
+	p = p[:len(p)-len(p)%bytesPerSample]
+
+	n, err := s.src.Read(p)
+
+	for i := 0; i+bytesPerSample <= n; i += bytesPerSample {
+		gain := s.left
+		if (s.pos+int64(i))%bytesPerFrame != 0 {
+			gain = s.right
+		}
+
+		sample := float64(int16(binary.LittleEndian.Uint16(p[i:])))
+		sample = math.Max(math.MinInt16, math.Min(math.MaxInt16, sample*gain))
+		binary.LittleEndian.PutUint16(p[i:], uint16(int16(sample)))
+	}
+
+	s.pos += int64(n)
+
+	return n, err
+}
+
+// Seek seeks the source stream
+func (s *pannedStream) Seek(offset int64, whence int) (int64, error) {
+	pos, err := s.src.Seek(offset, whence)
+	if err == nil {
+		s.pos = pos
+	}
+
+	return pos, err
+}
+
+// Close closes the source stream, if it can be closed
+func (s *pannedStream) Close() error {
+	if closer, ok := s.src.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
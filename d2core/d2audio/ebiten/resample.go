@@ -0,0 +1,59 @@
+package ebiten
+
+import (
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/audio"
+)
+
+// resampleStream reads the whole of a decoded stream, which holds 16 bit
+// little endian stereo samples, and plays it back at a rate scaled by the
+// given factor, which raises or lowers its pitch
+func resampleStream(src io.Reader, factor float64) (audio.ReadSeekCloser, error) {
+	data, err := ioutil.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return audio.BytesReadSeekCloser(resample(data, factor)), nil
+}
+
+func resample(data []byte, factor float64) []byte {
+	frames := len(data) / bytesPerFrame
+	if frames == 0 || factor <= 0 || factor == 1 {
+		return data
+	}
+
+	sample := func(frame, channel int) float64 {
+		offset := frame*bytesPerFrame + channel*bytesPerSample
+		return float64(int16(binary.LittleEndian.Uint16(data[offset:])))
+	}
+
+	outFrames := int(float64(frames) / factor)
+	result := make([]byte, outFrames*bytesPerFrame)
+
+	for i := 0; i < outFrames; i++ {
+		pos := float64(i) * factor
+		frame := int(pos)
+		next := frame + 1
+
+		if next >= frames {
+			next = frames - 1
+		}
+
+		weight := pos - float64(frame)
+
+		for channel := 0; channel < 2; channel++ {
+			value := sample(frame, channel)*(1-weight) + sample(next, channel)*weight
+			value = math.Max(math.MinInt16, math.Min(math.MaxInt16, value))
+
+			offset := i*bytesPerFrame + channel*bytesPerSample
+			binary.LittleEndian.PutUint16(result[offset:], uint16(int16(value)))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,47 @@
+package d2audio
+
+import "math"
+
+// DefaultHearingDistance is the distance, in world tiles, beyond which
+// positional sounds can't be heard
+const DefaultHearingDistance = 15.0
+
+// Listener is the world position positional sounds are heard from, which is
+// usually the local player
+type Listener struct {
+	X               float64
+	Y               float64
+	HearingDistance float64
+}
+
+// NewListener creates a listener at the world origin
+func NewListener() *Listener {
+	return &Listener{HearingDistance: DefaultHearingDistance}
+}
+
+// SetPosition moves the listener to the given world position
+func (l *Listener) SetPosition(x, y float64) {
+	l.X, l.Y = x, y
+}
+
+// Gain returns the left and right channel gains of a sound emitted at the
+// given world position. The gain falls off linearly with the distance from
+// the listener, and the sound is panned towards the side of the screen it
+// is on.
+func (l *Listener) Gain(x, y float64) (left, right float64) {
+	if l.HearingDistance <= 0 {
+		return 0, 0
+	}
+
+	dx, dy := x-l.X, y-l.Y
+
+	attenuation := 1 - math.Hypot(dx, dy)/l.HearingDistance
+	if attenuation <= 0 {
+		return 0, 0
+	}
+
+	// The map is drawn isometrically, so the horizontal screen offset is x-y
+	pan := math.Max(-1, math.Min(1, (dx-dy)/l.HearingDistance))
+
+	return attenuation * math.Min(1, 1-pan), attenuation * math.Min(1, 1+pan)
+}
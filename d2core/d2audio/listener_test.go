@@ -0,0 +1,40 @@
+package d2audio
+
+import "testing"
+
+func TestListenerGainPan(t *testing.T) {
+	listener := NewListener()
+	listener.SetPosition(10, 10)
+
+	// One tile left on screen is -x, +y in world space
+	left, right := listener.Gain(9, 11)
+	if left <= right {
+		t.Errorf("sound left of listener: wanted left gain > right gain: got %v, %v", left, right)
+	}
+
+	left, right = listener.Gain(11, 9)
+	if right <= left {
+		t.Errorf("sound right of listener: wanted right gain > left gain: got %v, %v", left, right)
+	}
+
+	left, right = listener.Gain(10, 10)
+	if left != 1 || right != 1 {
+		t.Errorf("sound at listener: wanted full gain: got %v, %v", left, right)
+	}
+}
+
+func TestListenerGainAttenuation(t *testing.T) {
+	listener := NewListener()
+
+	nearLeft, nearRight := listener.Gain(2, 2)
+	farLeft, farRight := listener.Gain(5, 5)
+
+	if farLeft >= nearLeft || farRight >= nearRight {
+		t.Errorf("wanted distant sound to be quieter: got near %v, %v far %v, %v", nearLeft, nearRight, farLeft, farRight)
+	}
+
+	left, right := listener.Gain(DefaultHearingDistance, 0)
+	if left != 0 || right != 0 {
+		t.Errorf("sound at hearing distance: wanted silence: got %v, %v", left, right)
+	}
+}
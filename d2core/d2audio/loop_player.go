@@ -0,0 +1,127 @@
+package d2audio
+
+// LoopHandle controls a looping sound started by a LoopPlayer
+type LoopHandle struct {
+	player   *LoopPlayer
+	soundID  string
+	track    MusicTrack
+	gain     float64
+	fadeRate float64
+	released bool
+}
+
+// Stop fades the loop out over the duration, in seconds, and then frees it
+func (h *LoopHandle) Stop(fadeDuration float64) error {
+	if h.released {
+		return nil
+	}
+
+	if fadeDuration <= 0 {
+		return h.player.release(h)
+	}
+
+	h.fadeRate = h.gain / fadeDuration
+
+	return nil
+}
+
+// IsPlaying returns true until the loop has been freed
+func (h *LoopHandle) IsPlaying() bool {
+	return !h.released
+}
+
+// IsFading returns true while the loop is fading out
+func (h *LoopHandle) IsFading() bool {
+	return !h.released && h.fadeRate > 0
+}
+
+// Gain returns the fade gain of the loop, between 0 and 1
+func (h *LoopHandle) Gain() float64 {
+	return h.gain
+}
+
+// LoopPlayer plays looping sounds, such as area ambience
+type LoopPlayer struct {
+	load   MusicLoader
+	volume float64
+	loops  map[string]*LoopHandle
+}
+
+// NewLoopPlayer creates a loop player which loads sounds with the given loader
+func NewLoopPlayer(load MusicLoader) *LoopPlayer {
+	return &LoopPlayer{
+		load:   load,
+		volume: 1,
+		loops:  make(map[string]*LoopHandle),
+	}
+}
+
+// SetVolume sets the volume of the loops
+func (p *LoopPlayer) SetVolume(volume float64) {
+	p.volume = volume
+
+	for _, loop := range p.loops {
+		loop.track.SetVolume(loop.gain * p.volume)
+	}
+}
+
+// PlayLoop starts looping the given sound. If the sound is already looping,
+// or fading out, its handle is returned and it plays at full volume again.
+func (p *LoopPlayer) PlayLoop(soundID string) (*LoopHandle, error) {
+	if loop, ok := p.loops[soundID]; ok {
+		loop.gain = 1
+		loop.fadeRate = 0
+		loop.track.SetVolume(p.volume)
+
+		return loop, nil
+	}
+
+	track, err := p.load(soundID)
+	if err != nil {
+		return nil, err
+	}
+
+	track.SetVolume(p.volume)
+
+	if err := track.Play(); err != nil {
+		return nil, err
+	}
+
+	loop := &LoopHandle{player: p, soundID: soundID, track: track, gain: 1}
+	p.loops[soundID] = loop
+
+	return loop, nil
+}
+
+// Advance moves the fades along, and frees the loops which have faded out
+func (p *LoopPlayer) Advance(elapsed float64) error {
+	for _, loop := range p.loops {
+		if loop.fadeRate <= 0 {
+			continue
+		}
+
+		loop.gain -= loop.fadeRate * elapsed
+
+		if loop.gain > 0 {
+			loop.track.SetVolume(loop.gain * p.volume)
+			continue
+		}
+
+		if err := p.release(loop); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *LoopPlayer) release(loop *LoopHandle) error {
+	loop.gain = 0
+	loop.fadeRate = 0
+	loop.released = true
+	loop.track.SetVolume(0)
+
+	delete(p.loops, loop.soundID)
+
+	return loop.track.Stop()
+}
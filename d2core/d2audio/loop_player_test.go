@@ -0,0 +1,110 @@
+package d2audio
+
+import "testing"
+
+func newTestLoopPlayer() (*LoopPlayer, map[string]*testTrack) {
+	tracks := make(map[string]*testTrack)
+
+	player := NewLoopPlayer(func(soundID string) (MusicTrack, error) {
+		track := &testTrack{}
+		tracks[soundID] = track
+
+		return track, nil
+	})
+
+	return player, tracks
+}
+
+func TestLoopPlayerStopFade(t *testing.T) {
+	player, tracks := newTestLoopPlayer()
+
+	loop, err := player.PlayLoop("wind")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wind := tracks["wind"]
+	if !wind.playing || wind.volume != 1 {
+		t.Fatalf("wanted loop playing at full volume: got %v, %v", wind.playing, wind.volume)
+	}
+
+	if err := loop.Stop(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := player.Advance(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if !almostEqual(wind.volume, 0.5) || !loop.IsPlaying() || wind.stopped {
+		t.Errorf("wanted loop half faded and still playing: got %v, %v, %v", wind.volume, loop.IsPlaying(), wind.stopped)
+	}
+
+	if err := player.Advance(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if wind.volume != 0 || loop.IsPlaying() || !wind.stopped {
+		t.Errorf("wanted loop silent and released: got %v, %v, %v", wind.volume, loop.IsPlaying(), wind.stopped)
+	}
+}
+
+func TestLoopPlayerRestartWhileFading(t *testing.T) {
+	player, tracks := newTestLoopPlayer()
+
+	loop, err := player.PlayLoop("drips")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loop.Stop(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := player.Advance(1); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := player.PlayLoop("drips")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if restarted != loop {
+		t.Error("wanted the fading loop's handle reused")
+	}
+
+	if err := player.Advance(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if drips := tracks["drips"]; drips.volume != 1 || drips.stopped || loop.IsFading() {
+		t.Errorf("wanted restarted loop playing at full volume: got %v, %v", drips.volume, drips.stopped)
+	}
+}
+
+func TestLoopPlayerStopImmediately(t *testing.T) {
+	player, tracks := newTestLoopPlayer()
+
+	loop, err := player.PlayLoop("wind")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loop.Stop(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if loop.IsPlaying() || !tracks["wind"].stopped {
+		t.Error("wanted loop released without a fade")
+	}
+
+	next, err := player.PlayLoop("wind")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if next == loop {
+		t.Error("wanted a new handle for a released loop")
+	}
+}
@@ -0,0 +1,169 @@
+package d2audio
+
+import "math"
+
+// MusicTrack is a looping stream, such as music played by a MusicPlayer or
+// ambience played by a LoopPlayer
+type MusicTrack interface {
+	Play() error
+	Stop() error
+	SetVolume(volume float64)
+}
+
+// MusicLoader loads the track with the given ID
+type MusicLoader func(trackID string) (MusicTrack, error)
+
+type musicFade struct {
+	trackID string
+	track   MusicTrack
+	gain    float64
+	rate    float64
+}
+
+// MusicPlayer plays background music, crossfading from one track to the next
+type MusicPlayer struct {
+	load    MusicLoader
+	volume  float64
+	current *musicFade
+	fading  []*musicFade
+}
+
+// NewMusicPlayer creates a music player which loads tracks with the given loader
+func NewMusicPlayer(load MusicLoader) *MusicPlayer {
+	return &MusicPlayer{load: load, volume: 1}
+}
+
+// SetVolume sets the volume of the music
+func (m *MusicPlayer) SetVolume(volume float64) {
+	m.volume = volume
+	m.applyVolumes()
+}
+
+// CurrentTrack returns the ID of the track being faded in or played, which
+// is empty when no music is playing
+func (m *MusicPlayer) CurrentTrack() string {
+	if m.current == nil {
+		return ""
+	}
+
+	return m.current.trackID
+}
+
+// CrossfadeTo fades the current track out while fading the given track in
+// over the duration, in seconds. An empty track ID fades the music out.
+// Calling it again mid-fade continues from the current volumes, so a track
+// which is fading out fades back in from where it was.
+func (m *MusicPlayer) CrossfadeTo(trackID string, duration float64) error {
+	if m.current != nil && m.current.trackID == trackID {
+		return nil
+	}
+
+	rate := math.Inf(1)
+	if duration > 0 {
+		rate = 1 / duration
+	}
+
+	next, err := m.takeFading(trackID)
+	if err != nil {
+		return err
+	}
+
+	if m.current != nil {
+		m.current.rate = -rate
+		m.fading = append(m.fading, m.current)
+	}
+
+	for _, fade := range m.fading {
+		fade.rate = -rate
+	}
+
+	m.current = next
+
+	if next != nil {
+		next.rate = rate
+	}
+
+	return m.Advance(0)
+}
+
+// takeFading removes the given track from the fading out tracks, or loads it
+// if it isn't fading out
+func (m *MusicPlayer) takeFading(trackID string) (*musicFade, error) {
+	if trackID == "" {
+		return nil, nil
+	}
+
+	for i, fade := range m.fading {
+		if fade.trackID == trackID {
+			m.fading = append(m.fading[:i], m.fading[i+1:]...)
+			return fade, nil
+		}
+	}
+
+	track, err := m.load(trackID)
+	if err != nil {
+		return nil, err
+	}
+
+	track.SetVolume(0)
+
+	if err := track.Play(); err != nil {
+		return nil, err
+	}
+
+	return &musicFade{trackID: trackID, track: track}, nil
+}
+
+// Advance moves the fades along, and stops the tracks which have faded out
+func (m *MusicPlayer) Advance(elapsed float64) error {
+	if m.current != nil {
+		m.current.advance(elapsed)
+	}
+
+	fading := m.fading[:0]
+
+	for _, fade := range m.fading {
+		fade.advance(elapsed)
+
+		if fade.gain > 0 {
+			fading = append(fading, fade)
+			continue
+		}
+
+		fade.track.SetVolume(0)
+
+		if err := fade.track.Stop(); err != nil {
+			return err
+		}
+	}
+
+	for i := len(fading); i < len(m.fading); i++ {
+		m.fading[i] = nil
+	}
+
+	m.fading = fading
+	m.applyVolumes()
+
+	return nil
+}
+
+func (m *MusicPlayer) applyVolumes() {
+	if m.current != nil {
+		m.current.track.SetVolume(m.current.gain * m.volume)
+	}
+
+	for _, fade := range m.fading {
+		fade.track.SetVolume(fade.gain * m.volume)
+	}
+}
+
+func (f *musicFade) advance(elapsed float64) {
+	switch {
+	case math.IsInf(f.rate, 1):
+		f.gain = 1
+	case math.IsInf(f.rate, -1):
+		f.gain = 0
+	default:
+		f.gain = math.Max(0, math.Min(1, f.gain+f.rate*elapsed))
+	}
+}
@@ -0,0 +1,128 @@
+package d2audio
+
+import (
+	"math"
+	"testing"
+)
+
+type testTrack struct {
+	volume  float64
+	playing bool
+	stopped bool
+}
+
+func (t *testTrack) Play() error {
+	t.playing = true
+	return nil
+}
+
+func (t *testTrack) Stop() error {
+	t.playing = false
+	t.stopped = true
+
+	return nil
+}
+
+func (t *testTrack) SetVolume(volume float64) {
+	t.volume = volume
+}
+
+func newTestMusicPlayer() (*MusicPlayer, map[string]*testTrack) {
+	tracks := make(map[string]*testTrack)
+
+	player := NewMusicPlayer(func(trackID string) (MusicTrack, error) {
+		track := &testTrack{}
+		tracks[trackID] = track
+
+		return track, nil
+	})
+
+	return player, tracks
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestMusicPlayerCrossfade(t *testing.T) {
+	player, tracks := newTestMusicPlayer()
+
+	if err := player.CrossfadeTo("town", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if town := tracks["town"]; !town.playing || town.volume != 1 {
+		t.Fatalf("wanted first track playing at full volume: got %v, %v", town.playing, town.volume)
+	}
+
+	if err := player.CrossfadeTo("wild", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	town, wild := tracks["town"], tracks["wild"]
+
+	if err := player.Advance(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if !almostEqual(town.volume, 0.5) || !almostEqual(wild.volume, 0.5) {
+		t.Errorf("wanted the gains to cross at the midpoint: got %v, %v", town.volume, wild.volume)
+	}
+
+	if err := player.Advance(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if !town.stopped {
+		t.Error("wanted the old track stopped once faded out")
+	}
+
+	if wild.volume != 1 {
+		t.Errorf("wanted new track at full volume: got %v", wild.volume)
+	}
+
+	if player.CurrentTrack() != "wild" {
+		t.Errorf("wanted current track: wild: got %v", player.CurrentTrack())
+	}
+}
+
+func TestMusicPlayerCrossfadeRetarget(t *testing.T) {
+	player, tracks := newTestMusicPlayer()
+	player.SetVolume(0.5)
+
+	if err := player.CrossfadeTo("town", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := player.CrossfadeTo("wild", 4); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := player.Advance(1); err != nil {
+		t.Fatal(err)
+	}
+
+	town, wild := tracks["town"], tracks["wild"]
+
+	// Retargeting back to the old track continues from the current gains
+	if err := player.CrossfadeTo("town", 4); err != nil {
+		t.Fatal(err)
+	}
+
+	if tracks["town"] != town {
+		t.Error("wanted the fading out track reused")
+	}
+
+	if !almostEqual(town.volume, 0.375) || !almostEqual(wild.volume, 0.125) {
+		t.Errorf("wanted volumes unchanged by retarget: got %v, %v", town.volume, wild.volume)
+	}
+
+	if err := player.Advance(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if !almostEqual(town.volume, 0.5) || wild.volume != 0 || !wild.stopped {
+		t.Errorf("wanted old track back at full volume and new track stopped: got %v, %v, %v",
+			town.volume, wild.volume, wild.stopped)
+	}
+}
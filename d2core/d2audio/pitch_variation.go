@@ -0,0 +1,63 @@
+package d2audio
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+const semitonesPerOctave = 12
+
+// RandomSource provides the random numbers for pitch variation
+type RandomSource interface {
+	Float64() float64
+}
+
+// PitchVariation picks a random pitch for each playback of a sound, so
+// repeated sounds don't sound identical
+type PitchVariation struct {
+	random    RandomSource
+	semitones map[string]float64
+}
+
+// NewPitchVariation creates a pitch variation which uses the given random
+// source, or a time seeded one if it is nil
+func NewPitchVariation(random RandomSource) *PitchVariation {
+	if random == nil {
+		random = rand.New(rand.NewSource(time.Now().UnixNano())) //nolint:gosec // Not security related
+	}
+
+	return &PitchVariation{
+		random:    random,
+		semitones: make(map[string]float64),
+	}
+}
+
+// SetRange sets how many semitones up or down the given sound's pitch can
+// vary by, a range of zero plays the sound at its exact pitch
+func (p *PitchVariation) SetRange(soundID string, semitones float64) {
+	if semitones <= 0 {
+		delete(p.semitones, soundID)
+		return
+	}
+
+	p.semitones[soundID] = semitones
+}
+
+// Range returns how many semitones the given sound's pitch can vary by
+func (p *PitchVariation) Range(soundID string) float64 {
+	return p.semitones[soundID]
+}
+
+// Factor returns a random playback rate factor for the given sound, within
+// its pitch range
+func (p *PitchVariation) Factor(soundID string) float64 {
+	semitones := p.semitones[soundID]
+	if semitones <= 0 {
+		return 1
+	}
+
+	offset := (p.random.Float64()*2 - 1) * semitones
+
+	return math.Pow(2, offset/semitonesPerOctave)
+}
@@ -0,0 +1,41 @@
+package d2audio
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPitchVariationFactor(t *testing.T) {
+	const semitones = 2
+
+	pitch := NewPitchVariation(rand.New(rand.NewSource(1)))
+	pitch.SetRange("step", semitones)
+
+	first, second := pitch.Factor("step"), pitch.Factor("step")
+	if first == second {
+		t.Errorf("wanted consecutive plays to differ in pitch: got %v, %v", first, second)
+	}
+
+	low, high := math.Pow(2, -semitones/12.0), math.Pow(2, semitones/12.0)
+
+	for _, factor := range []float64{first, second} {
+		if factor < low || factor > high {
+			t.Errorf("wanted pitch factor within %v-%v: got %v", low, high, factor)
+		}
+	}
+}
+
+func TestPitchVariationZeroRange(t *testing.T) {
+	pitch := NewPitchVariation(rand.New(rand.NewSource(1)))
+	pitch.SetRange("hit", 2)
+	pitch.SetRange("hit", 0)
+
+	if factor := pitch.Factor("hit"); factor != 1 {
+		t.Errorf("zero range: wanted exact pitch: got %v", factor)
+	}
+
+	if factor := pitch.Factor("other"); factor != 1 {
+		t.Errorf("no range: wanted exact pitch: got %v", factor)
+	}
+}
@@ -0,0 +1,144 @@
+package d2audio
+
+const (
+	// DefaultMaxVoices is the default number of sounds which can play at once
+	DefaultMaxVoices = 32
+
+	// DefaultMaxInstances is the default number of instances of a single
+	// sound which can play at once
+	DefaultMaxInstances = 8
+)
+
+// Voice is a playing sound managed by a VoicePool
+type Voice interface {
+	Play()
+	Stop()
+	IsPlaying() bool
+}
+
+type poolVoice struct {
+	soundID string
+	voice   Voice
+	gain    float64
+}
+
+// VoicePool limits how many sounds play at once. When a limit is reached,
+// starting a sound stops an existing one to free its voice.
+type VoicePool struct {
+	maxVoices    int
+	maxInstances map[string]int
+	voices       []*poolVoice
+}
+
+// NewVoicePool creates a voice pool with the default limits
+func NewVoicePool() *VoicePool {
+	return &VoicePool{
+		maxVoices:    DefaultMaxVoices,
+		maxInstances: make(map[string]int),
+	}
+}
+
+// SetMaxVoices sets how many sounds can play at once
+func (p *VoicePool) SetMaxVoices(maxVoices int) {
+	p.maxVoices = maxVoices
+}
+
+// SetMaxInstances sets how many instances of the given sound can play at once
+func (p *VoicePool) SetMaxInstances(soundID string, maxInstances int) {
+	p.maxInstances[soundID] = maxInstances
+}
+
+// ActiveVoices returns how many sounds are playing
+func (p *VoicePool) ActiveVoices() int {
+	p.removeFinished()
+	return len(p.voices)
+}
+
+// Start plays a voice of the given sound at the given gain. If the sound is
+// at its instance limit its oldest instance is stopped, and if the pool is
+// full the quietest voice is stopped, preferring the oldest of equally quiet
+// voices.
+func (p *VoicePool) Start(soundID string, voice Voice, gain float64) {
+	if p.maxVoices <= 0 {
+		return
+	}
+
+	p.removeFinished()
+
+	maxInstances, ok := p.maxInstances[soundID]
+	if !ok {
+		maxInstances = DefaultMaxInstances
+	}
+
+	if maxInstances <= 0 {
+		return
+	}
+
+	for p.instances(soundID) >= maxInstances {
+		p.steal(p.oldestInstance(soundID))
+	}
+
+	for len(p.voices) >= p.maxVoices {
+		p.steal(p.quietest())
+	}
+
+	p.voices = append(p.voices, &poolVoice{soundID: soundID, voice: voice, gain: gain})
+	voice.Play()
+}
+
+func (p *VoicePool) removeFinished() {
+	voices := p.voices[:0]
+
+	for _, voice := range p.voices {
+		if voice.voice.IsPlaying() {
+			voices = append(voices, voice)
+		}
+	}
+
+	for i := len(voices); i < len(p.voices); i++ {
+		p.voices[i] = nil
+	}
+
+	p.voices = voices
+}
+
+func (p *VoicePool) instances(soundID string) int {
+	count := 0
+
+	for _, voice := range p.voices {
+		if voice.soundID == soundID {
+			count++
+		}
+	}
+
+	return count
+}
+
+// oldestInstance returns the index of the oldest voice of the given sound,
+// voices are kept in the order they were started
+func (p *VoicePool) oldestInstance(soundID string) int {
+	for i, voice := range p.voices {
+		if voice.soundID == soundID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (p *VoicePool) quietest() int {
+	quietest := 0
+
+	for i, voice := range p.voices {
+		if voice.gain < p.voices[quietest].gain {
+			quietest = i
+		}
+	}
+
+	return quietest
+}
+
+func (p *VoicePool) steal(index int) {
+	p.voices[index].voice.Stop()
+	p.voices = append(p.voices[:index], p.voices[index+1:]...)
+}
@@ -0,0 +1,98 @@
+package d2audio
+
+import "testing"
+
+type testVoice struct {
+	playing bool
+}
+
+func (v *testVoice) Play() {
+	v.playing = true
+}
+
+func (v *testVoice) Stop() {
+	v.playing = false
+}
+
+func (v *testVoice) IsPlaying() bool {
+	return v.playing
+}
+
+func TestVoicePoolMaxVoices(t *testing.T) {
+	pool := NewVoicePool()
+	pool.SetMaxVoices(4)
+
+	voices := make([]*testVoice, 6)
+	for i := range voices {
+		voices[i] = &testVoice{}
+		pool.Start(string(rune('a'+i)), voices[i], 1)
+	}
+
+	if active := pool.ActiveVoices(); active != 4 {
+		t.Errorf("active voices: wanted 4: got %d", active)
+	}
+
+	if voices[0].playing || voices[1].playing {
+		t.Error("wanted the oldest voices stolen")
+	}
+
+	for i := 2; i < len(voices); i++ {
+		if !voices[i].playing {
+			t.Errorf("voice %d: wanted playing", i)
+		}
+	}
+}
+
+func TestVoicePoolStealsQuietest(t *testing.T) {
+	pool := NewVoicePool()
+	pool.SetMaxVoices(2)
+
+	loud, quiet, next := &testVoice{}, &testVoice{}, &testVoice{}
+	pool.Start("loud", loud, 1)
+	pool.Start("quiet", quiet, 0.2)
+	pool.Start("next", next, 0.5)
+
+	if !loud.playing || quiet.playing || !next.playing {
+		t.Errorf("wanted the quietest voice stolen: got loud %v, quiet %v, next %v", loud.playing, quiet.playing, next.playing)
+	}
+}
+
+func TestVoicePoolMaxInstances(t *testing.T) {
+	pool := NewVoicePool()
+	pool.SetMaxInstances("hit", 2)
+
+	other := &testVoice{}
+	pool.Start("step", other, 0)
+
+	hits := []*testVoice{{}, {}, {}}
+	for _, hit := range hits {
+		pool.Start("hit", hit, 1)
+	}
+
+	if active := pool.ActiveVoices(); active != 3 {
+		t.Errorf("active voices: wanted 3: got %d", active)
+	}
+
+	if hits[0].playing || !hits[1].playing || !hits[2].playing {
+		t.Error("wanted the oldest instance of the sound stolen")
+	}
+
+	if !other.playing {
+		t.Error("wanted other sounds left playing")
+	}
+}
+
+func TestVoicePoolFinishedVoices(t *testing.T) {
+	pool := NewVoicePool()
+	pool.SetMaxVoices(1)
+
+	first, second := &testVoice{}, &testVoice{}
+	pool.Start("a", first, 1)
+	first.playing = false
+
+	pool.Start("b", second, 1)
+
+	if active := pool.ActiveVoices(); active != 1 || !second.playing {
+		t.Errorf("wanted finished voice freed: got %d active", active)
+	}
+}
@@ -0,0 +1,51 @@
+package d2audio
+
+import (
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+// Volumes holds the volume level of each sound category
+type Volumes struct {
+	levels [d2enum.SoundCategoryCount]float64
+}
+
+// NewVolumes creates volumes with every category at full volume
+func NewVolumes() *Volumes {
+	volumes := &Volumes{}
+
+	for i := range volumes.levels {
+		volumes.levels[i] = 1
+	}
+
+	return volumes
+}
+
+// SetCategoryVolume sets the volume level of a category, between 0 and 1
+func (v *Volumes) SetCategoryVolume(category d2enum.SoundCategory, level float64) {
+	if category < 0 || category >= d2enum.SoundCategoryCount {
+		return
+	}
+
+	v.levels[category] = math.Max(0, math.Min(1, level))
+}
+
+// CategoryVolume returns the volume level of a category
+func (v *Volumes) CategoryVolume(category d2enum.SoundCategory) float64 {
+	if category < 0 || category >= d2enum.SoundCategoryCount {
+		return 0
+	}
+
+	return v.levels[category]
+}
+
+// Gain returns the effective gain of sounds in a category, which is the
+// category volume scaled by the master volume
+func (v *Volumes) Gain(category d2enum.SoundCategory) float64 {
+	if category == d2enum.SoundCategoryMaster {
+		return v.CategoryVolume(category)
+	}
+
+	return v.CategoryVolume(category) * v.CategoryVolume(d2enum.SoundCategoryMaster)
+}
@@ -0,0 +1,40 @@
+package d2audio
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func TestVolumesMutedEffectsLeaveMusic(t *testing.T) {
+	volumes := NewVolumes()
+	volumes.SetCategoryVolume(d2enum.SoundCategoryMusic, 0.5)
+	volumes.SetCategoryVolume(d2enum.SoundCategoryEffects, 0)
+
+	if gain := volumes.Gain(d2enum.SoundCategoryEffects); gain != 0 {
+		t.Errorf("effects gain: wanted 0: got %v", gain)
+	}
+
+	if gain := volumes.Gain(d2enum.SoundCategoryMusic); gain != 0.5 {
+		t.Errorf("music gain: wanted 0.5: got %v", gain)
+	}
+
+	if gain := volumes.Gain(d2enum.SoundCategoryUI); gain != 1 {
+		t.Errorf("ui gain: wanted 1: got %v", gain)
+	}
+}
+
+func TestVolumesMaster(t *testing.T) {
+	volumes := NewVolumes()
+	volumes.SetCategoryVolume(d2enum.SoundCategoryMaster, 0.5)
+	volumes.SetCategoryVolume(d2enum.SoundCategoryMusic, 0.5)
+	volumes.SetCategoryVolume(d2enum.SoundCategoryEffects, 2)
+
+	if gain := volumes.Gain(d2enum.SoundCategoryMusic); gain != 0.25 {
+		t.Errorf("music gain: wanted 0.25: got %v", gain)
+	}
+
+	if gain := volumes.Gain(d2enum.SoundCategoryEffects); gain != 0.5 {
+		t.Errorf("effects gain, clamped: wanted 0.5: got %v", gain)
+	}
+}
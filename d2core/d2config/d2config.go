@@ -5,6 +5,8 @@ import (
 	"log"
 	"os"
 	"path"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 )
 
 // Config holds the configuration from config.json
@@ -17,8 +19,13 @@ type Configuration struct {
 	MpqPath         string
 	TicksPerSecond  int
 	FpsCap          int
+	MasterVolume    float64
 	SfxVolume       float64
 	BgmVolume       float64
+	UIVolume        float64
+	MaxSoundVoices  int
+	KeyBindings     map[string]d2enum.Key
+	GamepadDeadzone float64
 	FullScreen      bool
 	RunInBackground bool
 	VsyncEnabled    bool
@@ -27,7 +34,8 @@ type Configuration struct {
 
 // Load loads a configuration object from disk
 func Load() error {
-	Config = new(Configuration)
+	// Settings missing from the file keep their defaults
+	Config = defaultConfig()
 	return Config.Load()
 }
 
@@ -95,6 +103,36 @@ func (c *Configuration) Save() error {
 	return configFile.Close()
 }
 
+// Volume returns the volume level of a sound category
+func (c *Configuration) Volume(category d2enum.SoundCategory) float64 {
+	switch category {
+	case d2enum.SoundCategoryMaster:
+		return c.MasterVolume
+	case d2enum.SoundCategoryMusic:
+		return c.BgmVolume
+	case d2enum.SoundCategoryEffects:
+		return c.SfxVolume
+	case d2enum.SoundCategoryUI:
+		return c.UIVolume
+	}
+
+	return 0
+}
+
+// SetVolume sets the volume level of a sound category
+func (c *Configuration) SetVolume(category d2enum.SoundCategory, level float64) {
+	switch category {
+	case d2enum.SoundCategoryMaster:
+		c.MasterVolume = level
+	case d2enum.SoundCategoryMusic:
+		c.BgmVolume = level
+	case d2enum.SoundCategoryEffects:
+		c.SfxVolume = level
+	case d2enum.SoundCategoryUI:
+		c.UIVolume = level
+	}
+}
+
 func defaultConfigPath() string {
 	if configDir, err := os.UserConfigDir(); err == nil {
 		return path.Join(configDir, "OpenDiablo2", "config.json")
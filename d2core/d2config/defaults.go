@@ -8,8 +8,12 @@ import (
 
 func defaultConfig() *Configuration {
 	const (
-		defaultSfxVolume = 1.0
-		defaultBgmVolume = 0.3
+		defaultMasterVolume = 1.0
+		defaultSfxVolume    = 1.0
+		defaultBgmVolume    = 0.3
+		defaultUIVolume     = 1.0
+		defaultSoundVoices  = 32
+		defaultDeadzone     = 0.25
 	)
 
 	config := &Configuration{
@@ -18,8 +22,12 @@ func defaultConfig() *Configuration {
 		TicksPerSecond:  -1,
 		RunInBackground: true,
 		VsyncEnabled:    true,
+		MasterVolume:    defaultMasterVolume,
 		SfxVolume:       defaultSfxVolume,
 		BgmVolume:       defaultBgmVolume,
+		UIVolume:        defaultUIVolume,
+		MaxSoundVoices:  defaultSoundVoices,
+		GamepadDeadzone: defaultDeadzone,
 		MpqPath:         "C:/Program Files (x86)/Diablo II",
 		Backend:         "Ebiten",
 		MpqLoadOrder: []string{
@@ -0,0 +1,82 @@
+package d2gui
+
+// widgetAnchor positions a widget relative to an edge or the center of the screen
+type widgetAnchor struct {
+	horizontal HorizontalAlign
+	vertical   VerticalAlign
+	offsetX    int
+	offsetY    int
+
+	// screenX and screenY are the anchored screen position, updated when the screen is resized
+	screenX int
+	screenY int
+}
+
+// SetAnchor positions the widget relative to the screen rather than its parent, so it follows the screen edges when
+// the screen is resized. The offset is the distance from the anchored edge towards the center of the screen, or from
+// the center towards the right and bottom. Anchoring applies to widgets in absolute layouts.
+func (w *widgetBase) SetAnchor(horizontal HorizontalAlign, vertical VerticalAlign, offsetX, offsetY int) {
+	w.anchor = &widgetAnchor{
+		horizontal: horizontal,
+		vertical:   vertical,
+		offsetX:    offsetX,
+		offsetY:    offsetY,
+	}
+
+	if singleton != nil {
+		w.reanchor(singleton.screenWidth, singleton.screenHeight)
+	}
+}
+
+// ClearAnchor removes the anchor set by SetAnchor, so the widget is positioned by its parent again
+func (w *widgetBase) ClearAnchor() {
+	w.anchor = nil
+}
+
+// reanchor updates the anchored screen position for the given screen size
+func (w *widgetBase) reanchor(screenWidth, screenHeight int) {
+	anchor := w.anchor
+	if anchor == nil {
+		return
+	}
+
+	width, height := w.self.getSize()
+
+	switch anchor.horizontal {
+	case HorizontalAlignLeft:
+		anchor.screenX = anchor.offsetX
+	case HorizontalAlignCenter:
+		anchor.screenX = screenWidth/2 - width/2 + anchor.offsetX
+	case HorizontalAlignRight:
+		anchor.screenX = screenWidth - width - anchor.offsetX
+	}
+
+	switch anchor.vertical {
+	case VerticalAlignTop:
+		anchor.screenY = anchor.offsetY
+	case VerticalAlignMiddle:
+		anchor.screenY = screenHeight/2 - height/2 + anchor.offsetY
+	case VerticalAlignBottom:
+		anchor.screenY = screenHeight - height - anchor.offsetY
+	}
+}
+
+// anchoredScreenPos returns the anchored screen position, ok is false if the widget isn't anchored
+func (w *widgetBase) anchoredScreenPos() (x, y int, ok bool) {
+	if w.anchor == nil {
+		return 0, 0, false
+	}
+
+	return w.anchor.screenX, w.anchor.screenY, true
+}
+
+// reanchorAll updates the anchored position of the widget and its children
+func reanchorAll(w widget, screenWidth, screenHeight int) {
+	w.reanchor(screenWidth, screenHeight)
+
+	if container, ok := w.(widgetContainer); ok {
+		for _, child := range container.childWidgets() {
+			reanchorAll(child, screenWidth, screenHeight)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package d2gui
+
+import (
+	"testing"
+)
+
+func TestAnchorFollowsScreenResize(t *testing.T) {
+	m := &manager{}
+
+	layout := createLayout(nil, PositionTypeAbsolute)
+	spacer := layout.AddSpacerStatic(20, 10)
+	spacer.SetAnchor(HorizontalAlignRight, VerticalAlignBottom, 5, 5)
+
+	m.SetLayout(layout)
+
+	tests := []struct {
+		screenWidth, screenHeight int
+		wantedX, wantedY          int
+	}{
+		{800, 600, 775, 585},
+		{1024, 768, 999, 753},
+		{640, 480, 615, 465},
+	}
+
+	for _, test := range tests {
+		m.resize(test.screenWidth, test.screenHeight)
+
+		if x, y := spacer.ScreenPos(); x != test.wantedX || y != test.wantedY {
+			t.Errorf("screen position at %dx%d: wanted (%d, %d): got (%d, %d)",
+				test.screenWidth, test.screenHeight, test.wantedX, test.wantedY, x, y)
+		}
+	}
+}
+
+func TestAnchorCenterInNestedLayout(t *testing.T) {
+	m := &manager{}
+
+	layout := createLayout(nil, PositionTypeAbsolute)
+	nested := layout.AddLayout(PositionTypeAbsolute)
+	nested.SetPosition(100, 50)
+
+	spacer := nested.AddSpacerStatic(20, 10)
+	spacer.SetAnchor(HorizontalAlignCenter, VerticalAlignMiddle, 0, 0)
+
+	m.SetLayout(layout)
+	m.resize(800, 600)
+	nested.AdjustEntryPlacement()
+
+	if x, y := spacer.ScreenPos(); x != 390 || y != 295 {
+		t.Errorf("screen position: wanted (390, 295): got (%d, %d)", x, y)
+	}
+
+	spacer.ClearAnchor()
+	nested.AdjustEntryPlacement()
+
+	if x, y := spacer.ScreenPos(); x != 100 || y != 50 {
+		t.Errorf("screen position without anchor: wanted (100, 50): got (%d, %d)", x, y)
+	}
+}
@@ -2,9 +2,10 @@ package d2gui
 
 import (
 	"errors"
-	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"image/color"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
@@ -19,14 +20,40 @@ const (
 	buttonStatePressedToggled
 )
 
+// ButtonFrames are the frames a button is drawn with in each of its states. Frames which don't exist in the
+// button's sprite are drawn with the default frame.
+type ButtonFrames struct {
+	Default  int
+	Hover    int
+	Pressed  int
+	Disabled int
+}
+
+// defaultButtonFrames uses the pressed frame every button sprite has. The sprites have no hover or disabled frames,
+// disabled buttons are dimmed by their layout instead.
+var defaultButtonFrames = ButtonFrames{
+	Default:  int(buttonStateDefault),
+	Hover:    int(buttonStateDefault),
+	Pressed:  int(buttonStatePressed),
+	Disabled: int(buttonStateDefault),
+}
+
 // Button is a user actionable drawable toggle switch
 type Button struct {
 	widgetBase
 
 	width    int
 	height   int
-	state    buttonState
+	hovered  bool
+	pressed  bool
+	frames   ButtonFrames
 	surfaces []d2interface.Surface
+
+	repeatDelay    float64
+	repeatInterval float64
+	repeatWait     float64
+	repeatEvent    d2interface.MouseEvent
+	repeated       bool
 }
 
 func createButton(renderer d2interface.Renderer, text string, buttonStyle ButtonStyle) (*Button, error) {
@@ -102,29 +129,117 @@ func createButton(renderer d2interface.Renderer, text string, buttonStyle Button
 		surfaces[i] = surface
 	}
 
-	button := &Button{width: buttonWidth, height: buttonHeight, surfaces: surfaces}
+	return newButton(surfaces, buttonWidth, buttonHeight), nil
+}
+
+func newButton(surfaces []d2interface.Surface, width, height int) *Button {
+	button := &Button{
+		width:    width,
+		height:   height,
+		frames:   defaultButtonFrames,
+		surfaces: surfaces,
+	}
+
+	button.self = button
 	button.SetVisible(true)
 
-	return button, nil
+	return button
+}
+
+// SetFrames sets the frames the button is drawn with in each state
+func (b *Button) SetFrames(frames ButtonFrames) {
+	b.frames = frames
+}
+
+// SetRepeat makes holding the button down click it repeatedly, first after the initial delay and then every interval,
+// in seconds. A button which repeated isn't clicked again when it's released. An interval of zero turns repeating off.
+func (b *Button) SetRepeat(initialDelay, interval float64) {
+	b.repeatDelay = initialDelay
+	b.repeatInterval = interval
+}
+
+// IsPressed returns true if the mouse button was pressed on the button and is held while over it
+func (b *Button) IsPressed() bool {
+	return b.pressed && b.hovered
+}
+
+// frame returns the frame for the button's current state
+func (b *Button) frame() int {
+	frame := b.frames.Default
+
+	switch {
+	case b.disabled:
+		frame = b.frames.Disabled
+	case b.IsPressed():
+		frame = b.frames.Pressed
+	case b.hovered:
+		frame = b.frames.Hover
+	}
+
+	if frame < 0 || frame >= len(b.surfaces) {
+		return b.frames.Default
+	}
+
+	return frame
+}
+
+func (b *Button) onMouseEnter(event d2interface.MouseMoveEvent) bool {
+	b.hovered = true
+	return b.widgetBase.onMouseEnter(event)
+}
+
+func (b *Button) onMouseLeave(event d2interface.MouseMoveEvent) bool {
+	b.hovered = false
+	return b.widgetBase.onMouseLeave(event)
 }
 
 func (b *Button) onMouseButtonDown(event d2interface.MouseEvent) bool {
-	b.state = buttonStatePressed
+	if b.disabled || event.Button() != d2enum.MouseButtonLeft {
+		return false
+	}
+
+	// the button is under the mouse when it's pressed, even if no move was seen since it was placed
+	b.hovered = true
+	b.pressed = true
+	b.repeated = false
+	b.repeatWait = b.repeatDelay
+	b.repeatEvent = event
+
 	return false
 }
 
-func (b *Button) onMouseButtonUp(event d2interface.MouseEvent) bool {
-	b.state = buttonStateDefault
-	return false
+// advance repeats the click while the button is held down over it. Moving off the button pauses the repeat.
+func (b *Button) advance(elapsed float64) error {
+	if b.repeatInterval <= 0 || b.disabled || !b.IsPressed() {
+		return nil
+	}
+
+	for b.repeatWait -= elapsed; b.repeatWait <= 0; b.repeatWait += b.repeatInterval {
+		b.repeated = true
+		b.widgetBase.onMouseButtonClick(b.repeatEvent)
+	}
+
+	return nil
 }
 
-func (b *Button) onMouseLeave(event d2interface.MouseMoveEvent) bool {
-	b.state = buttonStateDefault
+// onMouseButtonClick only clicks the button if the mouse button was pressed on it, so dragging onto the button and
+// releasing doesn't click it
+func (b *Button) onMouseButtonClick(event d2interface.MouseEvent) bool {
+	if b.disabled || !b.pressed || b.repeated || !b.contains(event.X(), event.Y()) {
+		return false
+	}
+
+	return b.widgetBase.onMouseButtonClick(event)
+}
+
+func (b *Button) onMouseButtonUp(event d2interface.MouseEvent) bool {
+	b.pressed = false
+	b.repeatEvent = nil
 	return false
 }
 
 func (b *Button) render(target d2interface.Surface) error {
-	return target.Render(b.surfaces[b.state])
+	return target.Render(b.surfaces[b.frame()])
 }
 
 func (b *Button) getSize() (int, int) {
@@ -0,0 +1,154 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+func createTestButton(layout *Layout) *Button {
+	surfaces := []d2interface.Surface{&testSurface{}, &testSurface{}, &testSurface{}, &testSurface{}}
+	button := newButton(surfaces, 20, 10)
+	button.SetFrames(ButtonFrames{Default: 0, Hover: 1, Pressed: 2, Disabled: 3})
+	layout.addEntry(button)
+
+	return button
+}
+
+func TestButtonFrames(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	button := createTestButton(layout)
+
+	m := &manager{doubleClickWindow: defaultDoubleClickWindow, doubleClickRadius: defaultDoubleClickRadius}
+	m.SetLayout(layout)
+
+	inside := &testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft}
+	outside := &testMouseEvent{testMouseMoveEvent{x: 50, y: 5}, d2enum.MouseButtonLeft}
+
+	steps := []struct {
+		name   string
+		action func()
+		frame  int
+	}{
+		{"idle", func() {}, 0},
+		{"hovered", func() { m.OnMouseMove(&inside.testMouseMoveEvent) }, 1},
+		{"pressed", func() { m.OnMouseButtonDown(inside) }, 2},
+		{"pressed and moved off", func() { m.OnMouseMove(&outside.testMouseMoveEvent) }, 0},
+		{"pressed and moved back", func() { m.OnMouseMove(&inside.testMouseMoveEvent) }, 2},
+		{"released", func() { m.OnMouseButtonUp(inside) }, 1},
+		{"disabled", func() { button.SetEnabled(false) }, 3},
+	}
+
+	for _, step := range steps {
+		step.action()
+
+		if got := button.frame(); got != step.frame {
+			t.Errorf("%s frame: wanted %d: got %d", step.name, step.frame, got)
+		}
+	}
+
+	// frames missing from the sprite are drawn with the default frame
+	button.SetFrames(ButtonFrames{Default: 0, Hover: 1, Pressed: 2, Disabled: 4})
+
+	if got := button.frame(); got != 0 {
+		t.Errorf("missing disabled frame: wanted %d: got %d", 0, got)
+	}
+}
+
+func TestButtonReleaseOutside(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	button := createTestButton(layout)
+
+	m := &manager{doubleClickWindow: defaultDoubleClickWindow, doubleClickRadius: defaultDoubleClickRadius}
+	m.SetLayout(layout)
+
+	var clicks int
+
+	button.SetMouseClickHandler(func(event d2interface.MouseEvent) {
+		clicks++
+	})
+
+	inside := &testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft}
+	outside := &testMouseEvent{testMouseMoveEvent{x: 50, y: 5}, d2enum.MouseButtonLeft}
+
+	m.OnMouseButtonDown(inside)
+	m.OnMouseButtonUp(outside)
+
+	if clicks != 0 {
+		t.Errorf("released outside: wanted %d clicks: got %d", 0, clicks)
+	}
+
+	if button.IsPressed() {
+		t.Error("button still pressed after releasing outside")
+	}
+
+	// releasing on the button without pressing on it first isn't a click
+	button.onMouseButtonClick(inside)
+
+	if clicks != 0 {
+		t.Errorf("released without pressing: wanted %d clicks: got %d", 0, clicks)
+	}
+
+	_ = m.advance(1)
+	m.OnMouseButtonDown(inside)
+	m.OnMouseButtonUp(inside)
+
+	if clicks != 1 {
+		t.Errorf("released inside: wanted %d clicks: got %d", 1, clicks)
+	}
+}
+
+func TestButtonRepeat(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	button := createTestButton(layout)
+	button.SetRepeat(0.5, 0.25)
+
+	m := &manager{doubleClickWindow: defaultDoubleClickWindow, doubleClickRadius: defaultDoubleClickRadius}
+	m.SetLayout(layout)
+
+	var clicks int
+
+	button.SetMouseClickHandler(func(event d2interface.MouseEvent) {
+		clicks++
+	})
+
+	inside := &testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft}
+
+	// held for 1.5 seconds, repeating at 0.5, 0.75, 1, 1.25 and 1.5 seconds
+	m.OnMouseButtonDown(inside)
+
+	for i := 0; i < 12; i++ {
+		_ = m.advance(0.125)
+	}
+
+	m.OnMouseButtonUp(inside)
+
+	if clicks != 5 {
+		t.Errorf("clicks after holding: wanted %d: got %d", 5, clicks)
+	}
+
+	_ = m.advance(1)
+
+	if clicks != 5 {
+		t.Errorf("clicks after releasing: wanted %d: got %d", 5, clicks)
+	}
+
+	// released before the initial delay, the button clicks once
+	m.OnMouseButtonDown(inside)
+	_ = m.advance(0.25)
+	m.OnMouseButtonUp(inside)
+
+	if clicks != 6 {
+		t.Errorf("clicks after a short press: wanted %d: got %d", 6, clicks)
+	}
+
+	// one long advance catches up on every repeat it covers
+	m.OnMouseButtonDown(inside)
+	_ = m.advance(1)
+	m.OnMouseButtonUp(inside)
+
+	if clicks != 9 {
+		t.Errorf("clicks after one long advance: wanted %d: got %d", 9, clicks)
+	}
+}
@@ -0,0 +1,89 @@
+package d2gui
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+)
+
+const (
+	checkboxFrameUnchecked = 0
+	checkboxFrameChecked   = 1
+)
+
+// Checkbox is a widget which toggles between checked and unchecked when clicked
+type Checkbox struct {
+	widgetBase
+
+	animation d2interface.Animation
+	checked   bool
+	onChange  func(checked bool)
+}
+
+func createCheckbox(checked bool) (*Checkbox, error) {
+	animation, err := d2asset.LoadAnimation(d2resource.Checkbox, d2resource.PaletteFechar)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCheckbox(animation, checked), nil
+}
+
+func newCheckbox(animation d2interface.Animation, checked bool) *Checkbox {
+	checkbox := &Checkbox{
+		animation: animation,
+		checked:   checked,
+	}
+
+	checkbox.self = checkbox
+	checkbox.SetVisible(true)
+
+	return checkbox
+}
+
+// SetChecked sets the checked state without calling the change callback
+func (c *Checkbox) SetChecked(checked bool) {
+	c.checked = checked
+}
+
+// IsChecked returns true if the checkbox is checked
+func (c *Checkbox) IsChecked() bool {
+	return c.checked
+}
+
+// SetOnChange sets the callback called with the new state when the checkbox is toggled by a click
+func (c *Checkbox) SetOnChange(onChange func(checked bool)) {
+	c.onChange = onChange
+}
+
+func (c *Checkbox) onMouseButtonClick(event d2interface.MouseEvent) bool {
+	if c.disabled || event.Button() != d2enum.MouseButtonLeft {
+		return false
+	}
+
+	c.checked = !c.checked
+
+	if c.onChange != nil {
+		c.onChange(c.checked)
+	}
+
+	return c.widgetBase.onMouseButtonClick(event)
+}
+
+func (c *Checkbox) render(target d2interface.Surface) error {
+	frame := checkboxFrameUnchecked
+	if c.checked {
+		frame = checkboxFrameChecked
+	}
+
+	if err := c.animation.SetCurrentFrame(frame); err != nil {
+		return err
+	}
+
+	return c.animation.Render(target)
+}
+
+func (c *Checkbox) getSize() (int, int) {
+	return c.animation.GetCurrentFrameSize()
+}
@@ -0,0 +1,35 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func TestCheckboxToggle(t *testing.T) {
+	checkbox := newCheckbox(nil, false)
+
+	var changes []bool
+
+	checkbox.SetOnChange(func(checked bool) {
+		changes = append(changes, checked)
+	})
+
+	click := &testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft}
+	checkbox.onMouseButtonClick(click)
+
+	if !checkbox.IsChecked() {
+		t.Error("checkbox not checked after a click")
+	}
+
+	if len(changes) != 1 || !changes[0] {
+		t.Errorf("change callbacks: wanted %v: got %v", []bool{true}, changes)
+	}
+
+	checkbox.SetEnabled(false)
+	checkbox.onMouseButtonClick(click)
+
+	if !checkbox.IsChecked() || len(changes) != 1 {
+		t.Error("disabled checkbox toggled by a click")
+	}
+}
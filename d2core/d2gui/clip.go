@@ -0,0 +1,52 @@
+package d2gui
+
+import (
+	"image"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// SetClipBounds restricts drawing of the widget and its children to the given rectangle, relative to the widget.
+// Clips of nested widgets intersect.
+func (w *widgetBase) SetClipBounds(x, y, width, height int) {
+	w.clipBounds = &d2common.Rectangle{Left: x, Top: y, Width: width, Height: height}
+}
+
+// ClearClipBounds removes the clip rectangle set by SetClipBounds
+func (w *widgetBase) ClearClipBounds() {
+	w.clipBounds = nil
+}
+
+// setInheritedClip sets the screen rectangle the widget is clipped to by its parents, nil if it isn't clipped
+func (w *widgetBase) setInheritedClip(clip *d2common.Rectangle) {
+	w.inheritedClip = clip
+}
+
+// localClip returns the widget's own clip rectangle relative to the widget, for pushing onto the render target
+func (w *widgetBase) localClip() (image.Rectangle, bool) {
+	if w.clipBounds == nil {
+		return image.Rectangle{}, false
+	}
+
+	clip := w.clipBounds
+
+	return image.Rect(clip.Left, clip.Top, clip.Right(), clip.Bottom()), true
+}
+
+// screenClip returns the screen rectangle the widget is drawn within, combining its own clip with its parents'.
+// It returns nil if the widget isn't clipped.
+func (w *widgetBase) screenClip() *d2common.Rectangle {
+	if w.clipBounds == nil {
+		return w.inheritedClip
+	}
+
+	clip := *w.clipBounds
+	clip.Left += w.Sx
+	clip.Top += w.Sy
+
+	if w.inheritedClip != nil {
+		clip = clip.Intersect(*w.inheritedClip)
+	}
+
+	return &clip
+}
@@ -0,0 +1,68 @@
+package d2gui
+
+import (
+	"image"
+	"testing"
+)
+
+func TestLayoutClipCullsChildren(t *testing.T) {
+	var log []string
+
+	layout := createLayout(nil, PositionTypeAbsolute)
+	layout.SetClipBounds(0, 0, 50, 50)
+
+	addTestLogWidget(layout, "inside", &log).SetPosition(10, 10)
+	addTestLogWidget(layout, "outside", &log).SetPosition(60, 60)
+
+	nested := layout.AddLayout(PositionTypeAbsolute)
+	nested.SetPosition(30, 30)
+	nested.SetClipBounds(0, 0, 30, 30)
+
+	addTestLogWidget(nested, "nested inside", &log).SetPosition(5, 5)
+	// Inside the nested clip, but outside the parent's
+	addTestLogWidget(nested, "nested outside", &log).SetPosition(25, 25)
+
+	target := &testSurface{}
+
+	if err := layout.render(target); err != nil {
+		t.Fatal(err)
+	}
+
+	wanted := []string{"render inside", "render nested inside"}
+	if len(log) != len(wanted) {
+		t.Fatalf("rendered widgets: wanted %v: got %v", wanted, log)
+	}
+
+	for i := range wanted {
+		if log[i] != wanted[i] {
+			t.Fatalf("rendered widgets: wanted %v: got %v", wanted, log)
+		}
+	}
+
+	if len(target.clips) != 1 || target.clips[0] != image.Rect(30, 30, 60, 60) {
+		t.Errorf("pushed clips: wanted %v: got %v", []image.Rectangle{image.Rect(30, 30, 60, 60)}, target.clips)
+	}
+
+	if clip := nested.screenClip(); clip == nil || clip.Left != 30 || clip.Top != 30 || clip.Right() != 50 ||
+		clip.Bottom() != 50 {
+		t.Errorf("nested clip: wanted the intersection (30, 30)-(50, 50): got %v", clip)
+	}
+}
+
+func TestClearClipBounds(t *testing.T) {
+	var log []string
+
+	layout := createLayout(nil, PositionTypeAbsolute)
+	layout.SetClipBounds(0, 0, 5, 5)
+	addTestLogWidget(layout, "outside", &log).SetPosition(20, 20)
+
+	layout.ClearClipBounds()
+
+	if err := layout.render(&testSurface{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(log) != 1 {
+		t.Errorf("rendered widgets: wanted [render outside]: got %v", log)
+	}
+}
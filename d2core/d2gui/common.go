@@ -2,6 +2,7 @@ package d2gui
 
 import (
 	"errors"
+	"image/color"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
@@ -46,3 +47,17 @@ func renderSegmented(animation d2interface.Animation, segmentsX, segmentsY, fram
 
 	return nil
 }
+
+// renderOutline draws the outline of a rectangle with its top left corner at the current translation
+func renderOutline(width, height int, drawColor color.Color, target d2interface.Surface) {
+	target.DrawLine(width, 0, drawColor)
+	target.DrawLine(0, height, drawColor)
+
+	target.PushTranslation(width, 0)
+	target.DrawLine(0, height, drawColor)
+	target.Pop()
+
+	target.PushTranslation(0, height)
+	target.DrawLine(width, 0, drawColor)
+	target.Pop()
+}
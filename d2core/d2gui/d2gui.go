@@ -44,6 +44,19 @@ func SetLayout(layout *Layout) {
 	singleton.SetLayout(layout)
 }
 
+// PushModal shows the layout over the current layout, blocking input to everything behind it until PopModal is called.
+// A translucent overlay is drawn behind the layout if dimmed is true.
+func PushModal(layout *Layout, dimmed bool) {
+	verifyWasInit()
+	singleton.pushModal(layout, dimmed)
+}
+
+// PopModal removes the topmost modal pushed with PushModal
+func PopModal() {
+	verifyWasInit()
+	singleton.popModal()
+}
+
 // ShowLoadScreen renders the loading progress screen. The provided progress argument defines the loading animation's state in the range `[0, 1]`, where `0` is initial frame and `1` is the final frame
 func ShowLoadScreen(progress float64) {
 	verifyWasInit()
@@ -55,6 +68,12 @@ func HideLoadScreen() {
 	singleton.hideLoadScreen()
 }
 
+// SetDoubleClickThreshold sets the maximum number of seconds and pixels between the two clicks of a double-click
+func SetDoubleClickThreshold(window float64, radius int) {
+	verifyWasInit()
+	singleton.setDoubleClickThreshold(window, radius)
+}
+
 func ShowCursor() {
 	verifyWasInit()
 	singleton.showCursor()
@@ -0,0 +1,297 @@
+package d2gui
+
+import (
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+const (
+	dropdownPadding         = 4
+	dropdownMaxVisibleItems = 8
+
+	// dropdownPopupLayer is added to the layer of an open dropdown, so its list draws over its siblings
+	dropdownPopupLayer = 1000
+)
+
+// Dropdown is a box showing the selected item, which opens a scrollable list of the items when clicked. The list
+// closes when an item is chosen or the mouse is clicked elsewhere.
+type Dropdown struct {
+	widgetBase
+
+	font  d2interface.Font
+	items []string
+	width int
+
+	selected int
+	open     bool
+	popup    *ScrollContainer
+	onChange func(index int)
+}
+
+func createDropdown(renderer d2interface.Renderer, items []string, width int, fontStyle FontStyle) (*Dropdown, error) {
+	font, err := loadFont(fontStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDropdown(renderer, font, items, width), nil
+}
+
+func newDropdown(renderer d2interface.Renderer, font d2interface.Font, items []string, width int) *Dropdown {
+	dropdown := &Dropdown{
+		font:  font,
+		items: items,
+		width: width,
+	}
+
+	visibleItems := d2common.MinInt(len(items), dropdownMaxVisibleItems)
+	dropdown.popup = createScrollContainer(renderer, width, visibleItems*dropdown.itemHeight())
+	dropdown.popup.SetScrollbarVisible(len(items) > dropdownMaxVisibleItems)
+
+	for index := range items {
+		item := &dropdownItem{dropdown: dropdown, index: index}
+		item.self = item
+		item.SetVisible(true)
+		dropdown.popup.GetContent().addEntry(item)
+	}
+
+	dropdown.self = dropdown
+	dropdown.SetVisible(true)
+
+	return dropdown
+}
+
+// SetOnChange sets the callback called with the index of the item chosen from the list
+func (d *Dropdown) SetOnChange(onChange func(index int)) {
+	d.onChange = onChange
+}
+
+// GetSelected returns the index of the selected item
+func (d *Dropdown) GetSelected() int {
+	return d.selected
+}
+
+// GetSelectedText returns the selected item, or an empty string if there are no items
+func (d *Dropdown) GetSelectedText() string {
+	if d.selected >= len(d.items) {
+		return ""
+	}
+
+	return d.items[d.selected]
+}
+
+// SetSelected selects the item at the index without calling the change callback
+func (d *Dropdown) SetSelected(index int) {
+	if index >= 0 && index < len(d.items) {
+		d.selected = index
+	}
+}
+
+// IsOpen returns true if the list of items is shown
+func (d *Dropdown) IsOpen() bool {
+	return d.open
+}
+
+// Open shows the list of items
+func (d *Dropdown) Open() {
+	d.open = true
+	d.placePopup()
+}
+
+// Close hides the list of items
+func (d *Dropdown) Close() {
+	d.open = false
+}
+
+// SetScreenPos sets the screen position, and moves the list below it
+func (d *Dropdown) SetScreenPos(x, y int) {
+	d.widgetBase.SetScreenPos(x, y)
+	d.placePopup()
+}
+
+func (d *Dropdown) placePopup() {
+	d.popup.SetScreenPos(d.Sx, d.Sy+d.itemHeight())
+}
+
+// choose selects the item chosen from the list and closes it
+func (d *Dropdown) choose(index int) {
+	d.Close()
+
+	if index == d.selected {
+		return
+	}
+
+	d.selected = index
+
+	if d.onChange != nil {
+		d.onChange(index)
+	}
+}
+
+func (d *Dropdown) itemHeight() int {
+	_, height := d.font.GetTextMetrics("A")
+	return height + dropdownPadding*2
+}
+
+func (d *Dropdown) getSize() (int, int) {
+	return d.width, d.itemHeight()
+}
+
+func (d *Dropdown) getLayer() int {
+	if d.open {
+		return d.layer + dropdownPopupLayer
+	}
+
+	return d.layer
+}
+
+func (d *Dropdown) popupContains(x, y int) bool {
+	if !d.open {
+		return false
+	}
+
+	width, height := d.popup.getSize()
+	rect := d2common.Rectangle{Left: d.popup.Sx, Top: d.popup.Sy, Width: width, Height: height}
+
+	return rect.IsInRect(x, y)
+}
+
+func (d *Dropdown) advance(elapsed float64) error {
+	if !d.open {
+		return nil
+	}
+
+	return d.popup.advance(elapsed)
+}
+
+func (d *Dropdown) render(target d2interface.Surface) error {
+	width, height := d.getSize()
+
+	target.DrawRect(width, height, color.RGBA{A: 0xc0})
+	renderOutline(width, height, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}, target)
+
+	target.PushTranslation(dropdownPadding, dropdownPadding)
+	err := d.font.RenderText(d.GetSelectedText(), target)
+	target.Pop()
+
+	if err != nil || !d.open {
+		return err
+	}
+
+	target.PushTranslation(0, height)
+	defer target.Pop()
+
+	popupWidth, popupHeight := d.popup.getSize()
+	target.DrawRect(popupWidth, popupHeight, color.RGBA{A: 0xe0})
+
+	if err := d.popup.render(target); err != nil {
+		return err
+	}
+
+	renderOutline(popupWidth, popupHeight, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}, target)
+
+	return nil
+}
+
+func (d *Dropdown) onMouseButtonDown(event d2interface.MouseEvent) bool {
+	if d.popupContains(event.X(), event.Y()) {
+		return d.popup.onMouseButtonDown(event)
+	}
+
+	return d.widgetBase.onMouseButtonDown(event)
+}
+
+func (d *Dropdown) onMouseButtonUp(event d2interface.MouseEvent) bool {
+	if d.popupContains(event.X(), event.Y()) {
+		return d.popup.onMouseButtonUp(event)
+	}
+
+	return d.widgetBase.onMouseButtonUp(event)
+}
+
+// onMouseButtonClick opens or closes the list when the box is clicked
+func (d *Dropdown) onMouseButtonClick(event d2interface.MouseEvent) bool {
+	if d.popupContains(event.X(), event.Y()) {
+		return true
+	}
+
+	if d.open {
+		d.Close()
+	} else {
+		d.Open()
+	}
+
+	d.widgetBase.onMouseButtonClick(event)
+
+	return true
+}
+
+func (d *Dropdown) onMouseMove(event d2interface.MouseMoveEvent) bool {
+	if d.open {
+		d.popup.onMouseMove(event)
+	}
+
+	return d.widgetBase.onMouseMove(event)
+}
+
+func (d *Dropdown) onMouseLeave(event d2interface.MouseMoveEvent) bool {
+	if d.open {
+		d.popup.onMouseMove(event)
+	}
+
+	return d.widgetBase.onMouseLeave(event)
+}
+
+func (d *Dropdown) onMouseWheel(event d2interface.MouseWheelEvent) bool {
+	if d.popupContains(event.X(), event.Y()) {
+		return d.popup.onMouseWheel(event)
+	}
+
+	return d.widgetBase.onMouseWheel(event)
+}
+
+func (d *Dropdown) onClickOutside(event d2interface.MouseEvent) {
+	d.Close()
+}
+
+// dropdownItem is a row in the list of a dropdown
+type dropdownItem struct {
+	widgetBase
+
+	dropdown *Dropdown
+	index    int
+	hovered  bool
+}
+
+func (i *dropdownItem) getSize() (int, int) {
+	return i.dropdown.popup.contentWidth(), i.dropdown.itemHeight()
+}
+
+func (i *dropdownItem) render(target d2interface.Surface) error {
+	if i.hovered || i.index == i.dropdown.selected {
+		width, height := i.getSize()
+		target.DrawRect(width, height, color.RGBA{R: 0x40, G: 0x40, B: 0x40, A: 0xff})
+	}
+
+	target.PushTranslation(dropdownPadding, dropdownPadding)
+	defer target.Pop()
+
+	return i.dropdown.font.RenderText(i.dropdown.items[i.index], target)
+}
+
+func (i *dropdownItem) onMouseEnter(event d2interface.MouseMoveEvent) bool {
+	i.hovered = true
+	return i.widgetBase.onMouseEnter(event)
+}
+
+func (i *dropdownItem) onMouseLeave(event d2interface.MouseMoveEvent) bool {
+	i.hovered = false
+	return i.widgetBase.onMouseLeave(event)
+}
+
+func (i *dropdownItem) onMouseButtonClick(event d2interface.MouseEvent) bool {
+	i.dropdown.choose(i.index)
+	return true
+}
@@ -0,0 +1,73 @@
+package d2gui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func createTestDropdown() (*Layout, *Dropdown) {
+	items := make([]string, 5)
+	for i := range items {
+		items[i] = fmt.Sprintf("item %d", i)
+	}
+
+	layout := createLayout(nil, PositionTypeAbsolute)
+	dropdown := newDropdown(nil, &testFont{}, items, 100)
+	layout.addEntry(dropdown)
+
+	return layout, dropdown
+}
+
+func clickLayout(layout *Layout, x, y int) {
+	event := &testMouseEvent{testMouseMoveEvent{x: x, y: y}, d2enum.MouseButtonLeft}
+	layout.onMouseButtonDown(event)
+	layout.onMouseButtonUp(event)
+}
+
+func TestDropdownSelect(t *testing.T) {
+	layout, dropdown := createTestDropdown()
+
+	var changes []int
+
+	dropdown.SetOnChange(func(index int) {
+		changes = append(changes, index)
+	})
+
+	clickLayout(layout, 5, 5)
+
+	if !dropdown.IsOpen() {
+		t.Fatal("dropdown not opened by clicking it")
+	}
+
+	// Items are 18 pixels high, and the list starts below the 18 pixel high box
+	clickLayout(layout, 5, 18+3*18+5)
+
+	if dropdown.IsOpen() {
+		t.Error("dropdown not closed by choosing an item")
+	}
+
+	if got := dropdown.GetSelectedText(); got != "item 3" {
+		t.Errorf("selected item: wanted %q: got %q", "item 3", got)
+	}
+
+	if len(changes) != 1 || changes[0] != 3 {
+		t.Errorf("change callbacks: wanted %v: got %v", []int{3}, changes)
+	}
+}
+
+func TestDropdownClickOutside(t *testing.T) {
+	layout, dropdown := createTestDropdown()
+
+	clickLayout(layout, 5, 5)
+	clickLayout(layout, 500, 500)
+
+	if dropdown.IsOpen() {
+		t.Error("dropdown not closed by clicking elsewhere")
+	}
+
+	if got := dropdown.GetSelected(); got != 0 {
+		t.Errorf("selected item: wanted %v: got %v", 0, got)
+	}
+}
@@ -0,0 +1,139 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+type testKeyEvent struct {
+	testMouseMoveEvent
+	key    d2enum.Key
+	keyMod d2enum.KeyMod
+}
+
+func (e *testKeyEvent) KeyMod() d2enum.KeyMod {
+	return e.keyMod
+}
+
+func (e *testKeyEvent) Key() d2enum.Key {
+	return e.key
+}
+
+func (e *testKeyEvent) Duration() int {
+	return 0
+}
+
+type testKeyWidget struct {
+	SpacerStatic
+
+	keys []d2enum.Key
+}
+
+func (w *testKeyWidget) onKeyDown(event d2interface.KeyEvent) bool {
+	w.keys = append(w.keys, event.Key())
+	return true
+}
+
+func createTestKeyWidget(layout *Layout) *testKeyWidget {
+	w := &testKeyWidget{SpacerStatic: SpacerStatic{width: 10, height: 10}}
+	w.self = w
+	w.SetVisible(true)
+	w.SetFocusable(true)
+	layout.addEntry(w)
+
+	return w
+}
+
+func TestFocusTabOrder(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	first := createTestKeyWidget(layout)
+	nested := layout.AddLayout(PositionTypeHorizontal)
+	second := createTestKeyWidget(nested)
+	layout.AddSpacerStatic(10, 10)
+	third := createTestKeyWidget(layout)
+
+	m := &manager{}
+	m.SetLayout(layout)
+
+	tab := &testKeyEvent{key: d2enum.KeyTab}
+	shiftTab := &testKeyEvent{key: d2enum.KeyTab, keyMod: d2enum.KeyModShift}
+
+	tests := []struct {
+		event  *testKeyEvent
+		wanted widget
+	}{
+		{tab, first},
+		{tab, second},
+		{tab, third},
+		{tab, first},
+		{shiftTab, third},
+		{shiftTab, second},
+	}
+
+	for i, test := range tests {
+		m.OnKeyDown(test.event)
+
+		if m.focused != test.wanted {
+			t.Errorf("tab %d: wrong widget focused", i)
+		}
+	}
+
+	third.SetTabIndex(-1)
+	m.setFocus(nil)
+	m.OnKeyDown(tab)
+
+	if m.focused != third {
+		t.Error("tab index: wanted the widget with the lowest tab index to be focused first")
+	}
+
+	if !third.IsFocused() || first.IsFocused() || second.IsFocused() {
+		t.Error("focus flags don't match the focused widget")
+	}
+}
+
+func TestFocusKeyEvents(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	first := createTestKeyWidget(layout)
+	second := createTestKeyWidget(layout)
+
+	m := &manager{}
+	m.SetLayout(layout)
+
+	if m.OnKeyDown(&testKeyEvent{key: d2enum.KeyA}) {
+		t.Error("key event handled without a focused widget")
+	}
+
+	m.setFocus(second)
+	m.OnKeyDown(&testKeyEvent{key: d2enum.KeyA})
+
+	if len(first.keys) != 0 || len(second.keys) != 1 {
+		t.Errorf("key events (first, second): wanted (0, 1): got (%d, %d)", len(first.keys), len(second.keys))
+	}
+
+	m.OnMouseButtonDown(&testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft})
+	m.OnKeyDown(&testKeyEvent{key: d2enum.KeyB})
+
+	if len(first.keys) != 1 || len(second.keys) != 1 {
+		t.Errorf("key events after clicking (first, second): wanted (1, 1): got (%d, %d)", len(first.keys),
+			len(second.keys))
+	}
+}
+
+func TestRadioButtonArrowKeys(t *testing.T) {
+	group := CreateRadioGroup()
+	buttons := []*RadioButton{newRadioButton(group, nil), newRadioButton(group, nil), newRadioButton(group, nil)}
+
+	buttons[0].onKeyDown(&testKeyEvent{key: d2enum.KeyDown})
+
+	if group.GetSelected() != 1 {
+		t.Errorf("selection after down arrow: wanted %d: got %d", 1, group.GetSelected())
+	}
+
+	buttons[1].onKeyDown(&testKeyEvent{key: d2enum.KeyUp})
+
+	if group.GetSelected() != 0 {
+		t.Errorf("selection after up arrow: wanted %d: got %d", 0, group.GetSelected())
+	}
+}
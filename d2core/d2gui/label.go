@@ -1,10 +1,20 @@
 package d2gui
 
 import (
+	"image"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 )
 
+const (
+	// defaultLabelScrollSpeed is the number of pixels per second an auto scrolling label scrolls by
+	defaultLabelScrollSpeed = 30
+
+	// defaultLabelScrollPause is the number of seconds an auto scrolling label waits at each end of its text
+	defaultLabelScrollPause = 1
+)
+
 type Label struct {
 	widgetBase
 
@@ -12,6 +22,15 @@ type Label struct {
 	text     string
 	font     d2interface.Font
 	surface  d2interface.Surface
+
+	width int
+
+	autoScroll      bool
+	scrollSpeed     float64
+	scrollPause     float64
+	scrollOffset    float64
+	scrollDirection float64
+	scrollWait      float64
 }
 
 func createLabel(renderer d2interface.Renderer, text string, fontStyle FontStyle) (*Label, error) {
@@ -20,23 +39,114 @@ func createLabel(renderer d2interface.Renderer, text string, fontStyle FontStyle
 		return nil, err
 	}
 
+	return newLabel(renderer, font, text), nil
+}
+
+func newLabel(renderer d2interface.Renderer, font d2interface.Font, text string) *Label {
 	label := &Label{
-		font:     font,
-		renderer: renderer,
+		font:        font,
+		renderer:    renderer,
+		scrollSpeed: defaultLabelScrollSpeed,
+		scrollPause: defaultLabelScrollPause,
 	}
 
 	_ = label.setText(text)
+	label.self = label
 	label.SetVisible(true)
 
-	return label, nil
+	return label
+}
+
+// SetWidth sets the width the label is drawn at, zero fits the text. Text wider than the label is cut off, unless
+// the label scrolls automatically.
+func (l *Label) SetWidth(width int) {
+	l.width = width
+	l.resetScroll()
+}
+
+// SetAutoScroll sets whether text wider than the label scrolls back and forth, pausing at each end. Labels don't
+// scroll by default.
+func (l *Label) SetAutoScroll(autoScroll bool) {
+	l.autoScroll = autoScroll
+	l.resetScroll()
+}
+
+// SetScrollSpeed sets the number of pixels per second an auto scrolling label scrolls by
+func (l *Label) SetScrollSpeed(speed float64) {
+	l.scrollSpeed = speed
+}
+
+// SetScrollPause sets the number of seconds an auto scrolling label waits at each end of its text
+func (l *Label) SetScrollPause(pause float64) {
+	l.scrollPause = pause
+}
+
+func (l *Label) resetScroll() {
+	l.scrollOffset = 0
+	l.scrollDirection = 1
+	l.scrollWait = l.scrollPause
+}
+
+// maxScrollOffset returns how far the text can be scrolled, which is zero if it fits the label
+func (l *Label) maxScrollOffset() float64 {
+	textWidth, _ := l.surface.GetSize()
+	if l.width <= 0 || textWidth <= l.width {
+		return 0
+	}
+
+	return float64(textWidth - l.width)
+}
+
+func (l *Label) advance(elapsed float64) error {
+	maxOffset := l.maxScrollOffset()
+	if !l.autoScroll || maxOffset == 0 {
+		return nil
+	}
+
+	if l.scrollWait > 0 {
+		l.scrollWait -= elapsed
+		if l.scrollWait > 0 {
+			return nil
+		}
+
+		elapsed = -l.scrollWait
+		l.scrollWait = 0
+	}
+
+	l.scrollOffset += l.scrollDirection * l.scrollSpeed * elapsed
+
+	switch {
+	case l.scrollOffset >= maxOffset:
+		l.scrollOffset = maxOffset
+		l.scrollDirection = -1
+		l.scrollWait = l.scrollPause
+	case l.scrollOffset <= 0:
+		l.scrollOffset = 0
+		l.scrollDirection = 1
+		l.scrollWait = l.scrollPause
+	}
+
+	return nil
 }
 
 func (l *Label) render(target d2interface.Surface) error {
-	return target.Render(l.surface)
+	textWidth, textHeight := l.surface.GetSize()
+	if l.width <= 0 || textWidth <= l.width {
+		return target.Render(l.surface)
+	}
+
+	offset := int(l.scrollOffset)
+
+	return target.RenderSection(l.surface, image.Rect(offset, 0, offset+l.width, textHeight))
 }
 
 func (l *Label) getSize() (int, int) {
-	return l.surface.GetSize()
+	width, height := l.surface.GetSize()
+	if l.width > 0 {
+		width = l.width
+	}
+
+	return width, height
 }
 
 func (l *Label) GetText() string {
@@ -51,15 +161,16 @@ func (l *Label) SetText(text string) error {
 }
 
 func (l *Label) setText(text string) error {
-	width, height := l.font.GetTextMetrics(text)
+	width, height := MeasureText(l.font, text)
 	surface, err := l.renderer.NewSurface(width, height, d2enum.FilterNearest)
 	if err != nil {
 		return err
 	}
-	if err := l.font.RenderText(text, surface); err != nil {
+	if err := renderColoredText(l.font, text, surface); err != nil {
 		return err
 	}
 	l.surface = surface
 	l.text = text
+	l.resetScroll()
 	return nil
 }
@@ -0,0 +1,93 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// testRenderer creates test surfaces of the requested size
+type testRenderer struct {
+	d2interface.Renderer
+}
+
+func (r *testRenderer) NewSurface(width, height int, filter d2enum.Filter) (d2interface.Surface, error) {
+	return &sizedTestSurface{width: width, height: height}, nil
+}
+
+// renderedOffset returns the horizontal offset of the label's text in the section it renders, or -1 if it renders the
+// whole text
+func renderedOffset(t *testing.T, label *Label) int {
+	t.Helper()
+
+	target := &testSurface{}
+	if err := label.render(target); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(target.sections) == 0 {
+		return -1
+	}
+
+	return target.sections[0].bounds.Min.X
+}
+
+func TestLabelAutoScroll(t *testing.T) {
+	// 230 pixels of text in a 50 pixel label
+	label := newLabel(&testRenderer{}, &testFont{}, "a long item description")
+	label.SetWidth(50)
+	label.SetAutoScroll(true)
+	label.SetScrollSpeed(100)
+	label.SetScrollPause(1)
+
+	steps := []struct {
+		name    string
+		elapsed float64
+		offset  int
+	}{
+		{"start", 0, 0},
+		{"pausing at the start", 0.5, 0},
+		{"scrolling", 0.75, 25},
+		{"scrolling further", 1, 125},
+		{"reaching the end", 1, 180},
+		{"pausing at the end", 0.5, 180},
+		{"scrolling back", 0.75, 155},
+		{"reaching the start", 2, 0},
+	}
+
+	for _, step := range steps {
+		_ = label.advance(step.elapsed)
+
+		if got := renderedOffset(t, label); got != step.offset {
+			t.Errorf("offset %s: wanted %d: got %d", step.name, step.offset, got)
+		}
+	}
+
+	if width, _ := label.getSize(); width != 50 {
+		t.Errorf("scrolling label width: wanted %d: got %d", 50, width)
+	}
+}
+
+func TestLabelShortTextDoesNotScroll(t *testing.T) {
+	label := newLabel(&testRenderer{}, &testFont{}, "short")
+	label.SetWidth(100)
+	label.SetAutoScroll(true)
+
+	for i := 0; i < 100; i++ {
+		_ = label.advance(0.1)
+
+		if got := renderedOffset(t, label); got != -1 {
+			t.Fatalf("short text offset after %d steps: wanted the whole text: got offset %d", i+1, got)
+		}
+	}
+
+	// long text doesn't scroll unless auto scroll is on
+	_ = label.SetText("a long item description")
+	label.SetAutoScroll(false)
+	_ = label.advance(5)
+
+	if got := renderedOffset(t, label); got != 0 {
+		t.Errorf("offset without auto scroll: wanted %d: got %d", 0, got)
+	}
+}
@@ -2,12 +2,20 @@ package d2gui
 
 import (
 	"image/color"
+	"sort"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 )
 
+// widgetContainer is a widget containing other widgets
+type widgetContainer interface {
+	widgetAt(x, y int) widget
+	childWidgets() []widget
+}
+
 type layoutEntry struct {
 	widget widget
 
@@ -42,6 +50,7 @@ const (
 	PositionTypeAbsolute PositionType = iota
 	PositionTypeVertical
 	PositionTypeHorizontal
+	PositionTypeGrid
 )
 
 type Layout struct {
@@ -54,6 +63,8 @@ type Layout struct {
 	verticalAlign   VerticalAlign
 	horizontalAlign HorizontalAlign
 	positionType    PositionType
+	spacing         int
+	gridColumns     int
 	entries         []*layoutEntry
 }
 
@@ -63,6 +74,7 @@ func createLayout(renderer d2interface.Renderer, positionType PositionType) *Lay
 		positionType: positionType,
 	}
 
+	layout.self = layout
 	layout.SetVisible(true)
 
 	return layout
@@ -81,21 +93,33 @@ func (l *Layout) SetHorizontalAlign(horizontalAlign HorizontalAlign) {
 	l.horizontalAlign = horizontalAlign
 }
 
+// SetSpacing sets the number of pixels between entries of vertical, horizontal and grid layouts
+func (l *Layout) SetSpacing(spacing int) {
+	l.spacing = spacing
+	l.AdjustEntryPlacement()
+}
+
+// SetGridColumns sets the number of columns of a grid layout. Entries fill the grid row by row.
+func (l *Layout) SetGridColumns(columns int) {
+	l.gridColumns = columns
+	l.AdjustEntryPlacement()
+}
+
 func (l *Layout) AddLayout(positionType PositionType) *Layout {
 	layout := createLayout(l.renderer, positionType)
-	l.entries = append(l.entries, &layoutEntry{widget: layout})
+	l.addEntry(layout)
 	return layout
 }
 
 func (l *Layout) AddSpacerStatic(width, height int) *SpacerStatic {
 	spacer := createSpacerStatic(width, height)
-	l.entries = append(l.entries, &layoutEntry{widget: spacer})
+	l.addEntry(spacer)
 	return spacer
 }
 
 func (l *Layout) AddSpacerDynamic() *SpacerDynamic {
 	spacer := createSpacerDynamic()
-	l.entries = append(l.entries, &layoutEntry{widget: spacer})
+	l.addEntry(spacer)
 	return spacer
 }
 
@@ -105,7 +129,7 @@ func (l *Layout) AddSprite(imagePath, palettePath string) (*Sprite, error) {
 		return nil, err
 	}
 
-	l.entries = append(l.entries, &layoutEntry{widget: sprite})
+	l.addEntry(sprite)
 	return sprite, nil
 }
 
@@ -115,17 +139,28 @@ func (l *Layout) AddAnimatedSprite(imagePath, palettePath string, direction Anim
 		return nil, err
 	}
 
-	l.entries = append(l.entries, &layoutEntry{widget: sprite})
+	l.addEntry(sprite)
 	return sprite, nil
 }
 
+// AddNineSlice adds a frame drawn from a border sprite, which stretches to its size without distorting its corners
+func (l *Layout) AddNineSlice(imagePath, palettePath string) (*NineSlice, error) {
+	nineSlice, err := createNineSlice(l.renderer, imagePath, palettePath)
+	if err != nil {
+		return nil, err
+	}
+
+	l.addEntry(nineSlice)
+	return nineSlice, nil
+}
+
 func (l *Layout) AddLabel(text string, fontStyle FontStyle) (*Label, error) {
 	label, err := createLabel(l.renderer, text, fontStyle)
 	if err != nil {
 		return nil, err
 	}
 
-	l.entries = append(l.entries, &layoutEntry{widget: label})
+	l.addEntry(label)
 	return label, nil
 }
 
@@ -135,22 +170,139 @@ func (l *Layout) AddButton(text string, buttonStyle ButtonStyle) (*Button, error
 		return nil, err
 	}
 
-	l.entries = append(l.entries, &layoutEntry{widget: button})
+	l.addEntry(button)
 	return button, nil
 }
 
+// AddCheckbox adds a checkbox with the given initial state
+func (l *Layout) AddCheckbox(checked bool) (*Checkbox, error) {
+	checkbox, err := createCheckbox(checked)
+	if err != nil {
+		return nil, err
+	}
+
+	l.addEntry(checkbox)
+	return checkbox, nil
+}
+
+// AddRadioButton adds a radio button as the next option of the group. The first option added is selected.
+func (l *Layout) AddRadioButton(group *RadioGroup) (*RadioButton, error) {
+	button, err := createRadioButton(group)
+	if err != nil {
+		return nil, err
+	}
+
+	l.addEntry(button)
+	return button, nil
+}
+
+// AddSlider adds a slider with a track of the given length, and a range from 0 to 1
+func (l *Layout) AddSlider(orientation SliderOrientation, length int) *Slider {
+	slider := createSlider(orientation, length)
+	l.addEntry(slider)
+	return slider
+}
+
+// AddProgressBar adds a track of the given size which fills in the given direction
+func (l *Layout) AddProgressBar(direction FillDirection, width, height int) *ProgressBar {
+	bar := createProgressBar(direction, width, height)
+	l.addEntry(bar)
+	return bar
+}
+
+// AddDropdown adds a box showing the selected item, which opens a list of the items when clicked
+func (l *Layout) AddDropdown(items []string, width int, fontStyle FontStyle) (*Dropdown, error) {
+	dropdown, err := createDropdown(l.renderer, items, width, fontStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	l.addEntry(dropdown)
+	return dropdown, nil
+}
+
+// AddScrollContainer adds a fixed size container which scrolls its content vertically
+func (l *Layout) AddScrollContainer(width, height int) *ScrollContainer {
+	container := createScrollContainer(l.renderer, width, height)
+	l.addEntry(container)
+	return container
+}
+
+// AddTabContainer adds a fixed size container of named pages, with a tab along the top for each page
+func (l *Layout) AddTabContainer(width, height int, fontStyle FontStyle) (*TabContainer, error) {
+	container, err := createTabContainer(l.renderer, width, height, fontStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	l.addEntry(container)
+	return container, nil
+}
+
+// AddTextInput adds a single line text field of the given width
+func (l *Layout) AddTextInput(width int, fontStyle FontStyle) (*TextInput, error) {
+	input, err := createTextInput(width, fontStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	l.addEntry(input)
+	return input, nil
+}
+
+// AddTooltip adds a tooltip which is shown near the cursor after it hovers over the target widget. The tooltip
+// draws over the entries added before it.
+func (l *Layout) AddTooltip(target widget, text string, fontStyle FontStyle) (*Tooltip, error) {
+	tooltip, err := createTooltip(target, text, fontStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	l.addEntry(tooltip)
+	return tooltip, nil
+}
+
+// RemoveWidget removes a widget from the layout, returning false if the layout doesn't contain it
+func (l *Layout) RemoveWidget(w widget) bool {
+	for i, entry := range l.entries {
+		if entry.widget == w {
+			copy(l.entries[i:], l.entries[i+1:])
+			l.entries = l.entries[:len(l.entries)-1]
+			l.AdjustEntryPlacement()
+
+			return true
+		}
+	}
+
+	return false
+}
+
 func (l *Layout) Clear() {
 	l.entries = nil
 }
 
+func (l *Layout) addEntry(w widget) {
+	l.entries = append(l.entries, &layoutEntry{widget: w})
+	l.AdjustEntryPlacement()
+}
+
 func (l *Layout) render(target d2interface.Surface) error {
 	l.AdjustEntryPlacement()
 
-	for _, entry := range l.entries {
+	clip := l.screenClip()
+
+	for _, entry := range l.entriesByLayer() {
 		if !entry.widget.isVisible() {
 			continue
 		}
 
+		// Cull entries entirely outside the clip
+		if clip != nil && !clip.Intersects(entry.screenRect()) {
+			continue
+		}
+
+		entry.widget.setInheritedClip(clip)
+
 		if err := l.renderEntry(entry, target); err != nil {
 			return err
 		}
@@ -166,19 +318,88 @@ func (l *Layout) render(target d2interface.Surface) error {
 
 func (l *Layout) advance(elapsed float64) error {
 	for _, entry := range l.entries {
+		entry.widget.advanceFade(elapsed)
+
 		if err := entry.widget.advance(elapsed); err != nil {
 			return err
 		}
 	}
 
+	if l.entrySizeChanged() {
+		l.AdjustEntryPlacement()
+	}
+
 	return nil
 }
 
+// entrySizeChanged returns true if an entry's size differs from its size when it was placed
+func (l *Layout) entrySizeChanged() bool {
+	for _, entry := range l.entries {
+		if !entry.widget.isVisible() || entry.widget.isExpanding() {
+			continue
+		}
+
+		if width, height := entry.widget.getSize(); width != entry.width || height != entry.height {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (l *Layout) renderEntry(entry *layoutEntry, target d2interface.Surface) error {
 	target.PushTranslation(entry.x, entry.y)
 	defer target.Pop()
 
-	return entry.widget.render(target)
+	if clip, ok := entry.widget.localClip(); ok {
+		target.PushClip(clip)
+		defer target.Pop()
+	}
+
+	entry.widget.setInheritedOpacity(l.renderOpacity())
+
+	if drawColor, ok := entryColor(entry); ok {
+		target.PushColor(drawColor)
+		defer target.Pop()
+	}
+
+	// widgets with the normal blend mode are drawn with their parent's
+	if mode := entry.widget.getBlendMode(); mode != d2enum.BlendModeNormal {
+		previous := target.GetBlendMode()
+		target.SetBlendMode(mode)
+
+		defer target.SetBlendMode(previous)
+	}
+
+	if err := entry.widget.render(target); err != nil {
+		return err
+	}
+
+	if entry.widget.isFocused() {
+		renderOutline(entry.width, entry.height, color.RGBA{R: 0xc8, G: 0xb4, B: 0x64, A: 0xff}, target)
+	}
+
+	return nil
+}
+
+// entryColor returns the color an entry is drawn with, which is dimmed if it is disabled and translucent if it or
+// the layout isn't opaque
+func entryColor(entry *layoutEntry) (color.Color, bool) {
+	opacity := entry.widget.renderOpacity()
+	enabled := entry.widget.isEnabled()
+
+	if enabled && opacity >= 1 {
+		return nil, false
+	}
+
+	drawColor := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	if !enabled {
+		drawColor = color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xc3}
+	}
+
+	drawColor.A = uint8(float64(drawColor.A) * opacity)
+
+	return drawColor, true
 }
 
 func (l *Layout) renderEntryDebug(entry *layoutEntry, target d2interface.Surface) error {
@@ -195,26 +416,28 @@ func (l *Layout) renderEntryDebug(entry *layoutEntry, target d2interface.Surface
 		drawColor = color.RGBA{R: 0x00, G: 0x00, B: 0xff, A: 0xff}
 	case *Button:
 		drawColor = color.RGBA{R: 0xff, G: 0xff, B: 0x00, A: 0xff}
+	case *ScrollContainer:
+		drawColor = color.RGBA{R: 0x00, G: 0xff, B: 0x00, A: 0xff}
 	}
 
-	target.DrawLine(entry.width, 0, drawColor)
-	target.DrawLine(0, entry.height, drawColor)
-
-	target.PushTranslation(entry.width, 0)
-	target.DrawLine(0, entry.height, drawColor)
-	target.Pop()
-
-	target.PushTranslation(0, entry.height)
-	target.DrawLine(entry.width, 0, drawColor)
-	target.Pop()
+	renderOutline(entry.width, entry.height, drawColor, target)
 
 	return nil
 }
 
 func (l *Layout) getContentSize() (int, int) {
-	var width, height int
+	if l.positionType == PositionTypeGrid {
+		columnWidths, rowHeights := l.getGridCellSizes()
+		return sumWithSpacing(columnWidths, l.spacing), sumWithSpacing(rowHeights, l.spacing)
+	}
+
+	var width, height, count int
 
 	for _, entry := range l.entries {
+		if !entry.widget.isVisible() {
+			continue
+		}
+
 		x, y := entry.widget.getPosition()
 		w, h := entry.widget.getSize()
 
@@ -229,44 +452,158 @@ func (l *Layout) getContentSize() (int, int) {
 			width = d2common.MaxInt(width, x+w)
 			height = d2common.MaxInt(height, y+h)
 		}
+
+		count++
+	}
+
+	if count > 1 {
+		switch l.positionType {
+		case PositionTypeVertical:
+			height += l.spacing * (count - 1)
+		case PositionTypeHorizontal:
+			width += l.spacing * (count - 1)
+		}
 	}
 
 	return width, height
 }
 
+// getGridCellSizes returns the width of each column and the height of each row of a grid layout
+func (l *Layout) getGridCellSizes() (columnWidths, rowHeights []int) {
+	columns := d2common.MaxInt(1, l.gridColumns)
+
+	var index int
+
+	for _, entry := range l.entries {
+		if !entry.widget.isVisible() {
+			continue
+		}
+
+		column, row := index%columns, index/columns
+		if row == len(rowHeights) {
+			rowHeights = append(rowHeights, 0)
+		}
+
+		if column == len(columnWidths) {
+			columnWidths = append(columnWidths, 0)
+		}
+
+		w, h := entry.widget.getSize()
+		columnWidths[column] = d2common.MaxInt(columnWidths[column], w)
+		rowHeights[row] = d2common.MaxInt(rowHeights[row], h)
+
+		index++
+	}
+
+	return columnWidths, rowHeights
+}
+
+func sumWithSpacing(sizes []int, spacing int) int {
+	var sum int
+
+	for i, size := range sizes {
+		if i > 0 {
+			sum += spacing
+		}
+
+		sum += size
+	}
+
+	return sum
+}
+
 func (l *Layout) getSize() (int, int) {
 	width, height := l.getContentSize()
 	return d2common.MaxInt(width, l.width), d2common.MaxInt(height, l.height)
 }
 
+// entriesByLayer returns the entries sorted by layer from bottom to top, in the order they were added within a layer
+func (l *Layout) entriesByLayer() []*layoutEntry {
+	entries := make([]*layoutEntry, len(l.entries))
+	copy(entries, l.entries)
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].widget.getLayer() < entries[j].widget.getLayer()
+	})
+
+	return entries
+}
+
+// entriesTopDown returns the entries in the order mouse events are dispatched to them, from top to bottom
+func (l *Layout) entriesTopDown() []*layoutEntry {
+	entries := l.entriesByLayer()
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	return entries
+}
+
+// onMouseButtonDown dispatches the event to the entries under the cursor from top to bottom, until one consumes it
 func (l *Layout) onMouseButtonDown(event d2interface.MouseEvent) bool {
 	for _, entry := range l.entries {
-		if entry.IsIn(event) {
-			entry.widget.onMouseButtonDown(event)
+		if !entry.IsIn(event) {
+			entry.widget.onClickOutside(event)
+		}
+	}
+
+	for _, entry := range l.entriesTopDown() {
+		if entry.widget.isEnabled() && entry.IsIn(event) {
 			entry.mouseDown[event.Button()] = true
+
+			if entry.widget.onMouseButtonDown(event) {
+				return true
+			}
 		}
 	}
 
 	return false
 }
 
+// onMouseButtonUp dispatches the event to the entries under the cursor from top to bottom, until one consumes it.
+// Entries the button was pressed on which don't get the event are still released, without being clicked.
 func (l *Layout) onMouseButtonUp(event d2interface.MouseEvent) bool {
-	for _, entry := range l.entries {
-		if entry.IsIn(event) {
-			if entry.mouseDown[event.Button()] {
-				entry.widget.onMouseButtonClick(event)
-				entry.widget.onMouseButtonUp(event)
-			}
+	var handled bool
+
+	for _, entry := range l.entriesTopDown() {
+		if !handled && entry.widget.isEnabled() && entry.IsIn(event) && entry.mouseDown[event.Button()] {
+			handled = l.releaseEntry(entry, event)
+		} else if entry.mouseDown[event.Button()] {
+			entry.widget.onMouseButtonUp(event)
 		}
 
 		entry.mouseDown[event.Button()] = false
 	}
 
-	return false
+	return handled
+}
+
+// releaseEntry dispatches a button release to an entry which the button was pressed on, returning true if the
+// entry consumed it
+func (l *Layout) releaseEntry(entry *layoutEntry, event d2interface.MouseEvent) bool {
+	var handled bool
+
+	switch event.(type) {
+	case *doubleClickEvent:
+		handled = entry.widget.onMouseButtonDoubleClick(event)
+	case *dropEvent:
+		// Releasing a dragged widget isn't a click
+	default:
+		handled = entry.widget.onMouseButtonClick(event)
+	}
+
+	return entry.widget.onMouseButtonUp(event) || handled
 }
 
 func (l *Layout) onMouseMove(event d2interface.MouseMoveEvent) bool {
-	for _, entry := range l.entries {
+	for _, entry := range l.entriesTopDown() {
+		if !entry.widget.isEnabled() {
+			// Disabled widgets receive neither enter nor leave, so they're entered again once re-enabled
+			entry.mouseOver = false
+			continue
+		}
+
 		if entry.IsIn(event) {
 			entry.widget.onMouseMove(event)
 
@@ -286,6 +623,56 @@ func (l *Layout) onMouseMove(event d2interface.MouseMoveEvent) bool {
 	return false
 }
 
+func (l *Layout) onClickOutside(event d2interface.MouseEvent) {
+	for _, entry := range l.entries {
+		entry.widget.onClickOutside(event)
+	}
+}
+
+func (l *Layout) childWidgets() []widget {
+	widgets := make([]widget, len(l.entries))
+	for i, entry := range l.entries {
+		widgets[i] = entry.widget
+	}
+
+	return widgets
+}
+
+// widgetAt returns the topmost widget at the screen position, searching within nested containers, or nil if there is
+// none
+func (l *Layout) widgetAt(x, y int) widget {
+	for _, entry := range l.entriesTopDown() {
+		if !entry.widget.isVisible() || !entry.contains(x, y) {
+			continue
+		}
+
+		if container, ok := entry.widget.(widgetContainer); ok {
+			if w := container.widgetAt(x, y); w != nil {
+				return w
+			}
+		}
+
+		return entry.widget
+	}
+
+	return nil
+}
+
+// onMouseWheel routes the event to the topmost entry under the cursor
+func (l *Layout) onMouseWheel(event d2interface.MouseWheelEvent) bool {
+	for _, entry := range l.entriesTopDown() {
+		if entry.widget.isVisible() && entry.IsIn(event) {
+			if !entry.widget.isEnabled() {
+				return false
+			}
+
+			return entry.widget.onMouseWheel(event)
+		}
+	}
+
+	return l.widgetBase.onMouseWheel(event)
+}
+
 func (l *Layout) AdjustEntryPlacement() {
 	width, height := l.getSize()
 
@@ -311,13 +698,18 @@ func (l *Layout) AdjustEntryPlacement() {
 		expanderHeight = d2common.MaxInt(0, expanderHeight)
 	}
 
-	var offsetX, offsetY int
+	var columnWidths, rowHeights []int
+	if l.positionType == PositionTypeGrid {
+		columnWidths, rowHeights = l.getGridCellSizes()
+	}
+
+	var offsetX, offsetY, index int
 	for _, entry := range l.entries {
 		if !entry.widget.isVisible() {
 			continue
 		}
 
-		if entry.widget.isExpanding() {
+		if entry.widget.isExpanding() && l.positionType != PositionTypeGrid {
 			entry.width, entry.height = expanderWidth, expanderHeight
 		} else {
 			entry.width, entry.height = entry.widget.getSize()
@@ -326,7 +718,7 @@ func (l *Layout) AdjustEntryPlacement() {
 		switch l.positionType {
 		case PositionTypeVertical:
 			entry.y = offsetY
-			offsetY += entry.height
+			offsetY += entry.height + l.spacing
 			switch l.horizontalAlign {
 			case HorizontalAlignLeft:
 				entry.x = 0
@@ -337,7 +729,7 @@ func (l *Layout) AdjustEntryPlacement() {
 			}
 		case PositionTypeHorizontal:
 			entry.x = offsetX
-			offsetX += entry.width
+			offsetX += entry.width + l.spacing
 			switch l.verticalAlign {
 			case VerticalAlignTop:
 				entry.y = 0
@@ -347,18 +739,71 @@ func (l *Layout) AdjustEntryPlacement() {
 				entry.y = height - entry.height
 			}
 		case PositionTypeAbsolute:
-			entry.x, entry.y = entry.widget.getPosition()
+			entry.x, entry.y = l.getAbsoluteEntryPosition(entry)
+		case PositionTypeGrid:
+			entry.x, entry.y = l.getGridEntryPosition(entry, index, columnWidths, rowHeights)
 		}
 
+		index++
+
 		sx, sy := l.ScreenPos()
-		entry.widget.SetScreenPos(entry.x + sx, entry.y + sy)
+		entry.widget.SetScreenPos(entry.x+sx, entry.y+sy)
 		entry.widget.setOffset(offsetX, offsetY)
 	}
 }
 
+// getAbsoluteEntryPosition returns the position of the entry, which follows its anchor if it has one
+func (l *Layout) getAbsoluteEntryPosition(entry *layoutEntry) (x, y int) {
+	if ax, ay, ok := entry.widget.anchoredScreenPos(); ok {
+		sx, sy := l.ScreenPos()
+		return ax - sx, ay - sy
+	}
+
+	return entry.widget.getPosition()
+}
+
+// getGridEntryPosition returns the position of the entry in the grid cell at the given index, aligned within the cell
+func (l *Layout) getGridEntryPosition(entry *layoutEntry, index int, columnWidths, rowHeights []int) (x, y int) {
+	column, row := index%len(columnWidths), index/len(columnWidths)
+	x = sumWithSpacing(columnWidths[:column], l.spacing)
+	y = sumWithSpacing(rowHeights[:row], l.spacing)
+
+	if column > 0 {
+		x += l.spacing
+	}
+
+	if row > 0 {
+		y += l.spacing
+	}
+
+	switch l.horizontalAlign {
+	case HorizontalAlignCenter:
+		x += columnWidths[column]/2 - entry.width/2
+	case HorizontalAlignRight:
+		x += columnWidths[column] - entry.width
+	}
+
+	switch l.verticalAlign {
+	case VerticalAlignMiddle:
+		y += rowHeights[row]/2 - entry.height/2
+	case VerticalAlignBottom:
+		y += rowHeights[row] - entry.height
+	}
+
+	return x, y
+}
+
 // IsIn layout entry, spc. of an event.
 func (l *layoutEntry) IsIn(event d2interface.HandlerEvent) bool {
+	return l.contains(event.X(), event.Y())
+}
+
+func (l *layoutEntry) contains(x, y int) bool {
+	rect := l.screenRect()
+	return rect.IsInRect(x, y) || l.widget.popupContains(x, y)
+}
+
+func (l *layoutEntry) screenRect() d2common.Rectangle {
 	sx, sy := l.widget.ScreenPos()
-	rect := d2common.Rectangle{Left: sx, Top: sy, Width: l.width, Height: l.height}
-	return rect.IsInRect(event.X(), event.Y())
+	return d2common.Rectangle{Left: sx, Top: sy, Width: l.width, Height: l.height}
 }
@@ -0,0 +1,304 @@
+package d2gui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// testSurface is a surface which tracks its translation, scale, clips and blend mode, records the sections rendered
+// onto it, and draws nothing
+type testSurface struct {
+	translations [][2]int
+	scales       [][2]float64
+	clips        []image.Rectangle
+	blendMode    d2enum.BlendMode
+	blendLog     []string
+	sections     []testSection
+}
+
+// testSection is a section of a surface rendered onto a test surface
+type testSection struct {
+	x, y           int
+	scaleX, scaleY float64
+	bounds         image.Rectangle
+}
+
+func (s *testSurface) translation() (x, y int) {
+	for _, t := range s.translations {
+		x += t[0]
+		y += t[1]
+	}
+
+	return x, y
+}
+
+func (s *testSurface) scale() (scaleX, scaleY float64) {
+	scaleX, scaleY = 1, 1
+
+	for _, scale := range s.scales {
+		scaleX *= scale[0]
+		scaleY *= scale[1]
+	}
+
+	return scaleX, scaleY
+}
+
+func (s *testSurface) push(x, y int) {
+	s.pushScaled(x, y, 1, 1)
+}
+
+func (s *testSurface) pushScaled(x, y int, scaleX, scaleY float64) {
+	s.translations = append(s.translations, [2]int{x, y})
+	s.scales = append(s.scales, [2]float64{scaleX, scaleY})
+}
+
+func (s *testSurface) Clear(color color.Color) error {
+	return nil
+}
+
+func (s *testSurface) DrawRect(width, height int, color color.Color) {
+	s.blendLog = append(s.blendLog, fmt.Sprintf("draw %d", s.blendMode))
+}
+
+func (s *testSurface) DrawLine(x, y int, color color.Color) {}
+
+func (s *testSurface) DrawText(format string, params ...interface{}) {}
+
+func (s *testSurface) GetSize() (width, height int) {
+	return 800, 600
+}
+
+func (s *testSurface) GetDepth() int {
+	return len(s.translations)
+}
+
+func (s *testSurface) Pop() {
+	s.PopN(1)
+}
+
+func (s *testSurface) PopN(n int) {
+	s.translations = s.translations[:len(s.translations)-n]
+	s.scales = s.scales[:len(s.scales)-n]
+}
+
+func (s *testSurface) PushColor(color color.Color) {
+	s.push(0, 0)
+}
+
+func (s *testSurface) PushEffect(effect d2enum.DrawEffect) {
+	s.push(0, 0)
+}
+
+func (s *testSurface) PushFilter(filter d2enum.Filter) {
+	s.push(0, 0)
+}
+
+func (s *testSurface) PushTranslation(x, y int) {
+	s.push(x, y)
+}
+
+func (s *testSurface) PushBrightness(brightness float64) {
+	s.push(0, 0)
+}
+
+func (s *testSurface) PushScale(scaleX, scaleY float64) {
+	s.pushScaled(0, 0, scaleX, scaleY)
+}
+
+func (s *testSurface) PushClip(bounds image.Rectangle) {
+	x, y := s.translation()
+	s.clips = append(s.clips, bounds.Add(image.Point{X: x, Y: y}))
+	s.push(0, 0)
+}
+
+func (s *testSurface) SetBlendMode(mode d2enum.BlendMode) {
+	s.blendMode = mode
+	s.blendLog = append(s.blendLog, fmt.Sprintf("set %d", mode))
+}
+
+func (s *testSurface) GetBlendMode() d2enum.BlendMode {
+	return s.blendMode
+}
+
+func (s *testSurface) Render(surface d2interface.Surface) error {
+	return nil
+}
+
+func (s *testSurface) RenderSection(surface d2interface.Surface, b image.Rectangle) error {
+	x, y := s.translation()
+	scaleX, scaleY := s.scale()
+	s.sections = append(s.sections, testSection{x: x, y: y, scaleX: scaleX, scaleY: scaleY, bounds: b})
+
+	return nil
+}
+
+func (s *testSurface) ReplacePixels(pixels []byte) error {
+	return nil
+}
+
+func (s *testSurface) Screenshot() *image.RGBA {
+	return nil
+}
+
+func TestLayoutVerticalSpacing(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	layout.SetSpacing(5)
+
+	layout.AddSpacerStatic(20, 10)
+	layout.AddSpacerStatic(30, 20)
+	layout.AddSpacerStatic(10, 10)
+
+	wantedY := []int{0, 15, 40}
+	for i, entry := range layout.entries {
+		if entry.y != wantedY[i] {
+			t.Errorf("entry %d y: wanted %d: got %d", i, wantedY[i], entry.y)
+		}
+	}
+
+	if width, height := layout.getSize(); width != 30 || height != 50 {
+		t.Errorf("layout size: wanted (30, 50): got (%d, %d)", width, height)
+	}
+
+	hidden := layout.AddSpacerStatic(10, 10)
+	hidden.SetVisible(false)
+	layout.AdjustEntryPlacement()
+
+	if _, height := layout.getSize(); height != 50 {
+		t.Errorf("layout height with a hidden entry: wanted %d: got %d", 50, height)
+	}
+
+	if !layout.RemoveWidget(layout.entries[1].widget) {
+		t.Fatal("failed to remove entry")
+	}
+
+	if entry := layout.entries[1]; entry.y != 15 {
+		t.Errorf("entry y after removal: wanted %d: got %d", 15, entry.y)
+	}
+}
+
+func TestLayoutExpandingEntry(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	layout.SetSize(40, 100)
+	layout.SetSpacing(5)
+
+	layout.AddSpacerStatic(20, 10)
+	expander := layout.AddSpacerDynamic()
+	layout.AddSpacerStatic(20, 20)
+
+	layout.AdjustEntryPlacement()
+
+	expanderEntry := layout.entries[1]
+	if expanderEntry.widget != expander {
+		t.Fatal("unexpected entry order")
+	}
+
+	if expanderEntry.height != 60 {
+		t.Errorf("expanding entry height: wanted %d: got %d", 60, expanderEntry.height)
+	}
+
+	if last := layout.entries[2]; last.y != 80 {
+		t.Errorf("last entry y: wanted %d: got %d", 80, last.y)
+	}
+}
+
+func TestLayoutGrid(t *testing.T) {
+	layout := createLayout(nil, PositionTypeGrid)
+	layout.SetGridColumns(2)
+	layout.SetSpacing(2)
+
+	layout.AddSpacerStatic(10, 10)
+	layout.AddSpacerStatic(20, 5)
+	layout.AddSpacerStatic(5, 15)
+
+	wanted := [][2]int{{0, 0}, {12, 0}, {0, 12}}
+	for i, entry := range layout.entries {
+		if entry.x != wanted[i][0] || entry.y != wanted[i][1] {
+			t.Errorf("entry %d position: wanted %v: got (%d, %d)", i, wanted[i], entry.x, entry.y)
+		}
+	}
+
+	if width, height := layout.getSize(); width != 32 || height != 27 {
+		t.Errorf("grid size: wanted (32, 27): got (%d, %d)", width, height)
+	}
+}
+
+func TestLayoutEntrySizeChange(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+
+	first := layout.AddSpacerStatic(20, 10)
+	layout.AddSpacerStatic(20, 10)
+
+	first.height = 30
+
+	if err := layout.advance(0); err != nil {
+		t.Fatal(err)
+	}
+
+	if entry := layout.entries[1]; entry.y != 30 {
+		t.Errorf("entry y after resize: wanted %d: got %d", 30, entry.y)
+	}
+}
+
+type testLogWidget struct {
+	SpacerStatic
+
+	name string
+	log  *[]string
+}
+
+func (w *testLogWidget) render(target d2interface.Surface) error {
+	*w.log = append(*w.log, "render "+w.name)
+	return nil
+}
+
+func (w *testLogWidget) onMouseButtonClick(event d2interface.MouseEvent) bool {
+	*w.log = append(*w.log, "click "+w.name)
+	return false
+}
+
+func addTestLogWidget(layout *Layout, name string, log *[]string) *testLogWidget {
+	w := &testLogWidget{SpacerStatic: SpacerStatic{width: 10, height: 10}, name: name, log: log}
+	w.self = w
+	w.SetVisible(true)
+	layout.addEntry(w)
+
+	return w
+}
+
+func TestLayoutLayerOrder(t *testing.T) {
+	var log []string
+
+	layout := createLayout(nil, PositionTypeAbsolute)
+	top := addTestLogWidget(layout, "top", &log)
+	addTestLogWidget(layout, "bottom", &log)
+
+	top.SetLayer(10)
+
+	if err := layout.render(&testSurface{}); err != nil {
+		t.Fatal(err)
+	}
+
+	event := &testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft}
+	layout.onMouseButtonDown(event)
+	layout.onMouseButtonUp(event)
+
+	wanted := []string{"render bottom", "render top", "click top", "click bottom"}
+	if len(log) != len(wanted) {
+		t.Fatalf("events: wanted %v: got %v", wanted, log)
+	}
+
+	for i := range wanted {
+		if log[i] != wanted[i] {
+			t.Fatalf("events: wanted %v: got %v", wanted, log)
+		}
+	}
+
+	if w := layout.widgetAt(5, 5); w != top {
+		t.Error("widget at position: wanted the top layer widget")
+	}
+}
@@ -3,7 +3,9 @@ package d2gui
 import (
 	"image/color"
 	"math"
+	"sort"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
@@ -11,6 +13,41 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2input"
 )
 
+const (
+	defaultDoubleClickWindow = 0.3
+	defaultDoubleClickRadius = 4
+
+	// dragThreshold is how many pixels the cursor moves with a button held before a drag starts
+	dragThreshold = 4
+)
+
+// doubleClickEvent marks a button release which completes a double-click
+type doubleClickEvent struct {
+	d2interface.MouseEvent
+}
+
+// dropEvent marks a button release which drops a dragged widget
+type dropEvent struct {
+	d2interface.MouseEvent
+}
+
+// dragState tracks a widget which is held or dragged with a mouse button
+type dragState struct {
+	source   widget
+	button   d2enum.MouseButton
+	pressX   int
+	pressY   int
+	grabX    int
+	grabY    int
+	dragging bool
+}
+
+// modal is a layout which blocks input to everything behind it
+type modal struct {
+	layout *Layout
+	dimmed bool
+}
+
 type manager struct {
 	layout        *Layout
 	cursorAnim    d2interface.Animation
@@ -19,6 +56,22 @@ type manager struct {
 	loadingAnim   d2interface.Animation
 	cursorVisible bool
 	loading       bool
+
+	time float64
+
+	doubleClickWindow float64
+	doubleClickRadius int
+	lastClick         d2interface.MouseEvent
+	lastClickTime     float64
+
+	drag *dragState
+
+	focused widget
+
+	screenWidth  int
+	screenHeight int
+
+	modals []*modal
 }
 
 func createGuiManager() (*manager, error) {
@@ -33,9 +86,11 @@ func createGuiManager() (*manager, error) {
 	}
 
 	manager := &manager{
-		cursorAnim:    cursorAnim,
-		loadingAnim:   loadingAnim,
-		cursorVisible: true,
+		cursorAnim:        cursorAnim,
+		loadingAnim:       loadingAnim,
+		cursorVisible:     true,
+		doubleClickWindow: defaultDoubleClickWindow,
+		doubleClickRadius: defaultDoubleClickRadius,
 	}
 
 	if err := d2input.BindHandler(manager); err != nil {
@@ -47,36 +102,315 @@ func createGuiManager() (*manager, error) {
 
 func (m *manager) SetLayout(layout *Layout) {
 	m.layout = layout
+	m.modals = nil
+	m.drag = nil
+	m.setFocus(nil)
 	if m.layout != nil {
+		reanchorAll(m.layout, m.screenWidth, m.screenHeight)
 		m.layout.AdjustEntryPlacement()
 	}
 }
 
+// pushModal shows the layout over the current layout and modals, and blocks input to them until it is popped. The
+// layout covers the screen, and a translucent overlay is drawn behind it if dimmed is true.
+func (m *manager) pushModal(layout *Layout, dimmed bool) {
+	m.modals = append(m.modals, &modal{layout: layout, dimmed: dimmed})
+	m.drag = nil
+	m.setFocus(nil)
+
+	layout.SetSize(m.screenWidth, m.screenHeight)
+	reanchorAll(layout, m.screenWidth, m.screenHeight)
+	layout.AdjustEntryPlacement()
+}
+
+// popModal removes the topmost modal, restoring input to the layout or modal below it
+func (m *manager) popModal() {
+	if len(m.modals) == 0 {
+		return
+	}
+
+	m.modals = m.modals[:len(m.modals)-1]
+	m.drag = nil
+	m.setFocus(nil)
+}
+
+// activeLayout returns the layout receiving input, which is the topmost modal if there is one
+func (m *manager) activeLayout() *Layout {
+	if len(m.modals) > 0 {
+		return m.modals[len(m.modals)-1].layout
+	}
+
+	return m.layout
+}
+
+// blocking returns true if a modal blocks input to the handlers behind the gui
+func (m *manager) blocking() bool {
+	return len(m.modals) > 0
+}
+
+// resize re-anchors the widgets when the screen size changes
+func (m *manager) resize(width, height int) {
+	if width == m.screenWidth && height == m.screenHeight {
+		return
+	}
+
+	m.screenWidth, m.screenHeight = width, height
+
+	if m.layout != nil {
+		m.layout.SetSize(width, height)
+		reanchorAll(m.layout, width, height)
+		m.layout.AdjustEntryPlacement()
+	}
+
+	for _, modal := range m.modals {
+		modal.layout.SetSize(width, height)
+		reanchorAll(modal.layout, width, height)
+		modal.layout.AdjustEntryPlacement()
+	}
+}
+
 func (m *manager) OnMouseButtonDown(event d2interface.MouseEvent) bool {
-	if m.layout == nil {
+	layout := m.activeLayout()
+	if layout == nil {
 		return false
 	}
 
-	return m.layout.onMouseButtonDown(event)
+	clicked := layout.widgetAt(event.X(), event.Y())
+	if clicked != nil && !clicked.isEnabled() {
+		clicked = nil
+	}
+
+	if clicked != nil && clicked.isFocusable() {
+		m.setFocus(clicked)
+	} else {
+		m.setFocus(nil)
+	}
+
+	if m.drag == nil {
+		if source := clicked; source != nil && source.isDraggable() {
+			sx, sy := source.ScreenPos()
+			m.drag = &dragState{
+				source: source,
+				button: event.Button(),
+				pressX: event.X(),
+				pressY: event.Y(),
+				grabX:  event.X() - sx,
+				grabY:  event.Y() - sy,
+			}
+		}
+	}
+
+	return layout.onMouseButtonDown(event) || m.blocking()
 }
 
 func (m *manager) OnMouseButtonUp(event d2interface.MouseEvent) bool {
-	if m.layout == nil {
+	if m.drag != nil && m.drag.button == event.Button() {
+		drag := m.drag
+		m.drag = nil
+
+		if drag.dragging {
+			return m.drop(drag, event)
+		}
+	}
+
+	if m.isDoubleClick(event) {
+		m.lastClick = nil
+		event = &doubleClickEvent{event}
+	} else {
+		m.lastClick = event
+		m.lastClickTime = m.time
+	}
+
+	layout := m.activeLayout()
+	if layout == nil {
+		return false
+	}
+
+	return layout.onMouseButtonUp(event) || m.blocking()
+}
+
+// isDoubleClick returns true if the click follows the last click with the same button, within the double-click
+// window and radius
+func (m *manager) isDoubleClick(event d2interface.MouseEvent) bool {
+	if m.lastClick == nil || m.lastClick.Button() != event.Button() {
+		return false
+	}
+
+	if m.time-m.lastClickTime > m.doubleClickWindow {
 		return false
 	}
 
-	return m.layout.onMouseButtonUp(event)
+	dx := event.X() - m.lastClick.X()
+	dy := event.Y() - m.lastClick.Y()
+
+	return dx*dx+dy*dy <= m.doubleClickRadius*m.doubleClickRadius
+}
+
+func (m *manager) setDoubleClickThreshold(window float64, radius int) {
+	m.doubleClickWindow = window
+	m.doubleClickRadius = radius
 }
 
 func (m *manager) OnMouseMove(event d2interface.MouseMoveEvent) bool {
 	m.cursorX = event.X()
 	m.cursorY = event.Y()
 
-	if m.layout == nil {
+	layout := m.activeLayout()
+	if layout == nil {
+		return false
+	}
+
+	if m.drag != nil {
+		m.updateDrag(layout, event)
+	}
+
+	return layout.onMouseMove(event) || m.blocking()
+}
+
+// OnKeyDown cycles focus on Tab and Shift+Tab, and forwards other keys to the focused widget
+func (m *manager) OnKeyDown(event d2interface.KeyEvent) bool {
+	if event.Key() == d2enum.KeyTab {
+		return m.cycleFocus(event.KeyMod()&d2enum.KeyModShift != 0) || m.blocking()
+	}
+
+	if m.focused == nil || !m.focused.isEnabled() {
+		return m.blocking()
+	}
+
+	return m.focused.onKeyDown(event) || m.blocking()
+}
+
+// OnKeyChars forwards typed characters to the focused widget
+func (m *manager) OnKeyChars(event d2interface.KeyCharsEvent) bool {
+	if m.focused == nil || !m.focused.isEnabled() {
+		return m.blocking()
+	}
+
+	return m.focused.onKeyChars(event) || m.blocking()
+}
+
+func (m *manager) setFocus(w widget) {
+	if m.focused == w {
+		return
+	}
+
+	if m.focused != nil {
+		m.focused.setFocused(false)
+	}
+
+	m.focused = w
+
+	if w != nil {
+		w.setFocused(true)
+	}
+}
+
+// cycleFocus moves focus to the next widget in the tab order, or the previous one if backwards is true
+func (m *manager) cycleFocus(backwards bool) bool {
+	widgets := m.tabOrder()
+	if len(widgets) == 0 {
 		return false
 	}
 
-	return m.layout.onMouseMove(event)
+	index := -1
+
+	for i, w := range widgets {
+		if w == m.focused {
+			index = i
+			break
+		}
+	}
+
+	switch {
+	case backwards && index <= 0:
+		index = len(widgets) - 1
+	case backwards:
+		index--
+	default:
+		index = (index + 1) % len(widgets)
+	}
+
+	m.setFocus(widgets[index])
+
+	return true
+}
+
+// tabOrder returns the visible focusable widgets of the active layout, sorted by tab index and then layout order
+func (m *manager) tabOrder() []widget {
+	layout := m.activeLayout()
+	if layout == nil {
+		return nil
+	}
+
+	widgets := appendFocusable(nil, layout)
+
+	sort.SliceStable(widgets, func(i, j int) bool {
+		return widgets[i].getTabIndex() < widgets[j].getTabIndex()
+	})
+
+	return widgets
+}
+
+func appendFocusable(widgets []widget, w widget) []widget {
+	if !w.isVisible() || !w.isEnabled() {
+		return widgets
+	}
+
+	if w.isFocusable() {
+		widgets = append(widgets, w)
+	}
+
+	if container, ok := w.(widgetContainer); ok {
+		for _, child := range container.childWidgets() {
+			widgets = appendFocusable(widgets, child)
+		}
+	}
+
+	return widgets
+}
+
+func (m *manager) OnMouseWheel(event d2interface.MouseWheelEvent) bool {
+	layout := m.activeLayout()
+	if layout == nil {
+		return false
+	}
+
+	return layout.onMouseWheel(event) || m.blocking()
+}
+
+// updateDrag starts dragging the held widget once the cursor moves far enough, and notifies widgets it passes over
+func (m *manager) updateDrag(layout *Layout, event d2interface.MouseMoveEvent) {
+	if !m.drag.dragging {
+		dx := event.X() - m.drag.pressX
+		dy := event.Y() - m.drag.pressY
+
+		if dx*dx+dy*dy <= dragThreshold*dragThreshold {
+			return
+		}
+
+		m.drag.dragging = true
+		m.drag.source.onDragStart(event)
+	}
+
+	if target := layout.widgetAt(event.X(), event.Y()); target != nil && target != m.drag.source && target.isEnabled() {
+		target.onDragOver(m.drag.source, event)
+	}
+}
+
+// drop drops the dragged widget on the widget under the cursor
+func (m *manager) drop(drag *dragState, event d2interface.MouseEvent) bool {
+	m.lastClick = nil
+
+	layout := m.activeLayout()
+	if layout == nil {
+		return false
+	}
+
+	if target := layout.widgetAt(event.X(), event.Y()); target != nil && target != drag.source && target.isEnabled() {
+		target.onDrop(drag.source, event)
+	}
+
+	return layout.onMouseButtonUp(&dropEvent{event}) || m.blocking()
 }
 
 func (m *manager) render(target d2interface.Surface) error {
@@ -85,8 +419,19 @@ func (m *manager) render(target d2interface.Surface) error {
 			return err
 		}
 	} else if m.layout != nil {
+		m.resize(target.GetSize())
 		m.layout.SetSize(target.GetSize())
-		if err := m.layout.render(target); err != nil {
+		if err := m.renderLayout(m.layout, target); err != nil {
+			return err
+		}
+
+		if err := m.renderModals(target); err != nil {
+			return err
+		}
+	}
+
+	if m.drag != nil && m.drag.dragging {
+		if err := m.renderDragGhost(target); err != nil {
 			return err
 		}
 	}
@@ -100,6 +445,32 @@ func (m *manager) render(target d2interface.Surface) error {
 	return nil
 }
 
+func (m *manager) renderLayout(layout *Layout, target d2interface.Surface) error {
+	layout.setInheritedClip(nil)
+
+	if clip, ok := layout.localClip(); ok {
+		target.PushClip(clip)
+		defer target.Pop()
+	}
+
+	return layout.render(target)
+}
+
+func (m *manager) renderModals(target d2interface.Surface) error {
+	for _, modal := range m.modals {
+		if modal.dimmed {
+			screenWidth, screenHeight := target.GetSize()
+			target.DrawRect(screenWidth, screenHeight, color.RGBA{A: 0xa0})
+		}
+
+		if err := m.renderLayout(modal.layout, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (m *manager) renderLoadScreen(target d2interface.Surface) error {
 	target.Clear(color.Black)
 
@@ -112,6 +483,15 @@ func (m *manager) renderLoadScreen(target d2interface.Surface) error {
 	return m.loadingAnim.Render(target)
 }
 
+// renderDragGhost renders a translucent copy of the dragged widget following the cursor
+func (m *manager) renderDragGhost(target d2interface.Surface) error {
+	target.PushTranslation(m.cursorX-m.drag.grabX, m.cursorY-m.drag.grabY)
+	target.PushColor(color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0x80})
+	defer target.PopN(2)
+
+	return m.drag.source.render(target)
+}
+
 func (m *manager) renderCursor(target d2interface.Surface) error {
 	_, height := m.cursorAnim.GetCurrentFrameSize()
 	target.PushTranslation(m.cursorX, m.cursorY)
@@ -122,10 +502,22 @@ func (m *manager) renderCursor(target d2interface.Surface) error {
 }
 
 func (m *manager) advance(elapsed float64) error {
+	m.time += elapsed
+
 	if !m.loading && m.layout != nil {
+		m.layout.advanceFade(elapsed)
+
 		if err := m.layout.advance(elapsed); err != nil {
 			return err
 		}
+
+		for _, modal := range m.modals {
+			modal.layout.advanceFade(elapsed)
+
+			if err := modal.layout.advance(elapsed); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,64 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func clickManager(m *manager, x, y int) bool {
+	event := &testMouseEvent{testMouseMoveEvent{x: x, y: y}, d2enum.MouseButtonLeft}
+	down := m.OnMouseButtonDown(event)
+	up := m.OnMouseButtonUp(event)
+
+	return down || up
+}
+
+func TestModalBlocksInput(t *testing.T) {
+	var log []string
+
+	m := &manager{}
+
+	layout := createLayout(nil, PositionTypeAbsolute)
+	addTestLogWidget(layout, "behind", &log)
+	m.SetLayout(layout)
+
+	dialog := createLayout(nil, PositionTypeAbsolute)
+	m.pushModal(dialog, true)
+
+	if !clickManager(m, 5, 5) {
+		t.Error("click on the modal overlay wasn't consumed")
+	}
+
+	if len(log) != 0 {
+		t.Errorf("widget behind the modal: wanted no events: got %v", log)
+	}
+
+	m.popModal()
+
+	// Let the double-click window pass, so the click isn't a double-click
+	_ = m.advance(1)
+
+	clickManager(m, 5, 5)
+
+	if len(log) != 1 || log[0] != "click behind" {
+		t.Errorf("widget after popping the modal: wanted %v: got %v", []string{"click behind"}, log)
+	}
+}
+
+func TestModalReceivesInput(t *testing.T) {
+	var log []string
+
+	m := &manager{}
+	m.SetLayout(createLayout(nil, PositionTypeAbsolute))
+
+	dialog := createLayout(nil, PositionTypeAbsolute)
+	addTestLogWidget(dialog, "dialog", &log)
+	m.pushModal(dialog, false)
+
+	clickManager(m, 5, 5)
+
+	if len(log) != 1 || log[0] != "click dialog" {
+		t.Errorf("widget in the modal: wanted %v: got %v", []string{"click dialog"}, log)
+	}
+}
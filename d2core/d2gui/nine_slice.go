@@ -0,0 +1,125 @@
+package d2gui
+
+import (
+	"image"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+)
+
+// NineSlice is a frame drawn from a border sprite at any size. The sprite is split into a grid of nine by the
+// border widths: the corners are drawn unscaled, the edges are stretched along the frame and the center is stretched
+// to fill it.
+type NineSlice struct {
+	widgetBase
+
+	source       d2interface.Surface
+	sourceWidth  int
+	sourceHeight int
+
+	left   int
+	top    int
+	right  int
+	bottom int
+
+	width  int
+	height int
+}
+
+func createNineSlice(renderer d2interface.Renderer, imagePath, palettePath string) (*NineSlice, error) {
+	animation, err := d2asset.LoadAnimation(imagePath, palettePath)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := animation.GetCurrentFrameSize()
+
+	source, err := renderer.NewSurface(width, height, d2enum.FilterNearest)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := animation.Render(source); err != nil {
+		return nil, err
+	}
+
+	return newNineSlice(source), nil
+}
+
+func newNineSlice(source d2interface.Surface) *NineSlice {
+	width, height := source.GetSize()
+
+	nineSlice := &NineSlice{
+		source:       source,
+		sourceWidth:  width,
+		sourceHeight: height,
+		width:        width,
+		height:       height,
+	}
+
+	nineSlice.self = nineSlice
+	nineSlice.SetVisible(true)
+
+	return nineSlice
+}
+
+// SetBorderWidths sets the widths of the sprite's borders, which are drawn unscaled along their length. The borders
+// are limited to the size of the sprite.
+func (n *NineSlice) SetBorderWidths(left, top, right, bottom int) {
+	n.left = d2common.MaxInt(0, d2common.MinInt(left, n.sourceWidth))
+	n.right = d2common.MaxInt(0, d2common.MinInt(right, n.sourceWidth-n.left))
+	n.top = d2common.MaxInt(0, d2common.MinInt(top, n.sourceHeight))
+	n.bottom = d2common.MaxInt(0, d2common.MinInt(bottom, n.sourceHeight-n.top))
+}
+
+// SetSize sets the size the frame is drawn at. It is never smaller than its borders.
+func (n *NineSlice) SetSize(width, height int) {
+	n.width = width
+	n.height = height
+}
+
+func (n *NineSlice) getSize() (int, int) {
+	return d2common.MaxInt(n.width, n.left+n.right), d2common.MaxInt(n.height, n.top+n.bottom)
+}
+
+func (n *NineSlice) render(target d2interface.Surface) error {
+	width, height := n.getSize()
+
+	sourceColumns := [4]int{0, n.left, n.sourceWidth - n.right, n.sourceWidth}
+	sourceRows := [4]int{0, n.top, n.sourceHeight - n.bottom, n.sourceHeight}
+	columns := [4]int{0, n.left, width - n.right, width}
+	rows := [4]int{0, n.top, height - n.bottom, height}
+
+	for row := 0; row < 3; row++ {
+		for column := 0; column < 3; column++ {
+			bounds := image.Rect(sourceColumns[column], sourceRows[row], sourceColumns[column+1], sourceRows[row+1])
+			cellWidth := columns[column+1] - columns[column]
+			cellHeight := rows[row+1] - rows[row]
+
+			if err := n.renderCell(target, bounds, columns[column], rows[row], cellWidth, cellHeight); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// renderCell draws the section of the sprite enclosed by bounds, stretched over the cell at (x, y)
+func (n *NineSlice) renderCell(target d2interface.Surface, bounds image.Rectangle, x, y, width, height int) error {
+	if bounds.Empty() || width <= 0 || height <= 0 {
+		return nil
+	}
+
+	target.PushTranslation(x, y)
+	defer target.Pop()
+
+	if width != bounds.Dx() || height != bounds.Dy() {
+		target.PushScale(float64(width)/float64(bounds.Dx()), float64(height)/float64(bounds.Dy()))
+		defer target.Pop()
+	}
+
+	return target.RenderSection(n.source, bounds)
+}
@@ -0,0 +1,77 @@
+package d2gui
+
+import (
+	"image"
+	"testing"
+)
+
+// sizedTestSurface is a test surface with a given size
+type sizedTestSurface struct {
+	testSurface
+	width, height int
+}
+
+func (s *sizedTestSurface) GetSize() (width, height int) {
+	return s.width, s.height
+}
+
+func TestNineSliceRender(t *testing.T) {
+	nineSlice := newNineSlice(&sizedTestSurface{width: 30, height: 30})
+	nineSlice.SetBorderWidths(10, 10, 10, 10)
+
+	tests := []struct {
+		width, height  int
+		centerScaleX   float64
+		centerScaleY   float64
+		rightX, bottom int
+	}{
+		{100, 50, 8, 3, 90, 40},
+		{60, 30, 4, 1, 50, 20},
+		{30, 30, 1, 1, 20, 20},
+	}
+
+	for _, test := range tests {
+		nineSlice.SetSize(test.width, test.height)
+
+		target := &testSurface{}
+		if err := nineSlice.render(target); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(target.sections) != 9 {
+			t.Fatalf("%dx%d sections: wanted %d: got %d", test.width, test.height, 9, len(target.sections))
+		}
+
+		corners := map[int]testSection{
+			0: {x: 0, y: 0, scaleX: 1, scaleY: 1, bounds: image.Rect(0, 0, 10, 10)},
+			2: {x: test.rightX, y: 0, scaleX: 1, scaleY: 1, bounds: image.Rect(20, 0, 30, 10)},
+			6: {x: 0, y: test.bottom, scaleX: 1, scaleY: 1, bounds: image.Rect(0, 20, 10, 30)},
+			8: {x: test.rightX, y: test.bottom, scaleX: 1, scaleY: 1, bounds: image.Rect(20, 20, 30, 30)},
+		}
+
+		for index, want := range corners {
+			if got := target.sections[index]; got != want {
+				t.Errorf("%dx%d corner %d: wanted %+v: got %+v", test.width, test.height, index, want, got)
+			}
+		}
+
+		want := testSection{x: 10, y: 10, scaleX: test.centerScaleX, scaleY: test.centerScaleY,
+			bounds: image.Rect(10, 10, 20, 20)}
+		if got := target.sections[4]; got != want {
+			t.Errorf("%dx%d center: wanted %+v: got %+v", test.width, test.height, want, got)
+		}
+
+		// the top edge only stretches horizontally
+		if got := target.sections[1]; got.scaleX != test.centerScaleX || got.scaleY != 1 {
+			t.Errorf("%dx%d top edge scale: wanted (%v, 1): got (%v, %v)", test.width, test.height,
+				test.centerScaleX, got.scaleX, got.scaleY)
+		}
+	}
+
+	// a frame smaller than its borders is drawn at the borders' size
+	nineSlice.SetSize(5, 5)
+
+	if width, height := nineSlice.getSize(); width != 20 || height != 20 {
+		t.Errorf("size smaller than the borders: wanted (20, 20): got (%d, %d)", width, height)
+	}
+}
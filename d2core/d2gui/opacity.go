@@ -0,0 +1,68 @@
+package d2gui
+
+import (
+	"math"
+)
+
+// opacityFade animates a widget's opacity towards a target
+type opacityFade struct {
+	from     float64
+	to       float64
+	duration float64
+	elapsed  float64
+}
+
+// SetOpacity sets the opacity of the widget and its children, from 0 (invisible) to 1 (opaque), stopping any fade
+func (w *widgetBase) SetOpacity(opacity float64) {
+	w.fade = nil
+	w.transparency = 1 - clampOpacity(opacity)
+}
+
+// GetOpacity returns the opacity of the widget, from 0 (invisible) to 1 (opaque)
+func (w *widgetBase) GetOpacity() float64 {
+	return 1 - w.transparency
+}
+
+// FadeTo animates the opacity of the widget from its current opacity to the target over the duration in seconds
+func (w *widgetBase) FadeTo(target, duration float64) {
+	if duration <= 0 {
+		w.SetOpacity(target)
+		return
+	}
+
+	w.fade = &opacityFade{
+		from:     w.GetOpacity(),
+		to:       clampOpacity(target),
+		duration: duration,
+	}
+}
+
+func (w *widgetBase) advanceFade(elapsed float64) {
+	if w.fade == nil {
+		return
+	}
+
+	fade := w.fade
+	fade.elapsed += elapsed
+
+	if fade.elapsed >= fade.duration {
+		w.SetOpacity(fade.to)
+		return
+	}
+
+	w.transparency = 1 - (fade.from + (fade.to-fade.from)*fade.elapsed/fade.duration)
+}
+
+// setInheritedOpacity sets the opacity the widget is drawn with by its parent
+func (w *widgetBase) setInheritedOpacity(opacity float64) {
+	w.inheritedTransparency = 1 - opacity
+}
+
+// renderOpacity returns the opacity the widget is drawn with, combining its own opacity with its parents'
+func (w *widgetBase) renderOpacity() float64 {
+	return (1 - w.inheritedTransparency) * w.GetOpacity()
+}
+
+func clampOpacity(opacity float64) float64 {
+	return math.Max(0, math.Min(opacity, 1))
+}
@@ -0,0 +1,80 @@
+package d2gui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOpacityClamp(t *testing.T) {
+	spacer := createSpacerStatic(10, 10)
+
+	if got := spacer.GetOpacity(); got != 1 {
+		t.Errorf("default opacity: wanted %v: got %v", 1, got)
+	}
+
+	tests := []struct {
+		opacity, wanted float64
+	}{
+		{-1, 0},
+		{2, 1},
+		{0.25, 0.25},
+	}
+
+	for _, test := range tests {
+		spacer.SetOpacity(test.opacity)
+
+		if got := spacer.GetOpacity(); got != test.wanted {
+			t.Errorf("SetOpacity(%v): wanted %v: got %v", test.opacity, test.wanted, got)
+		}
+	}
+}
+
+func TestOpacityFadeTo(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	spacer := layout.AddSpacerStatic(10, 10)
+
+	spacer.FadeTo(0, 1)
+	_ = layout.advance(0.5)
+
+	if got := spacer.GetOpacity(); math.Abs(got-0.5) > 0.0001 {
+		t.Errorf("opacity half way through the fade: wanted %v: got %v", 0.5, got)
+	}
+
+	_ = layout.advance(0.5)
+
+	if got := spacer.GetOpacity(); got != 0 {
+		t.Errorf("opacity after the fade: wanted %v: got %v", 0, got)
+	}
+
+	_ = layout.advance(0.5)
+
+	if got := spacer.GetOpacity(); got != 0 {
+		t.Errorf("opacity after the fade finished: wanted %v: got %v", 0, got)
+	}
+}
+
+func TestOpacityInherited(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	nested := layout.AddLayout(PositionTypeVertical)
+	spacer := nested.AddSpacerStatic(10, 10)
+
+	layout.SetOpacity(0.5)
+	nested.SetOpacity(0.5)
+
+	entry := layout.entries[0]
+	entry.widget.setInheritedOpacity(layout.renderOpacity())
+	nested.entries[0].widget.setInheritedOpacity(nested.renderOpacity())
+
+	if got := spacer.renderOpacity(); got != 0.25 {
+		t.Errorf("inherited opacity: wanted %v: got %v", 0.25, got)
+	}
+
+	drawColor, ok := entryColor(nested.entries[0])
+	if !ok {
+		t.Fatal("translucent entry drawn without a color")
+	}
+
+	if _, _, _, a := drawColor.RGBA(); a>>8 != 0x3f {
+		t.Errorf("entry alpha: wanted %#x: got %#x", 0x3f, a>>8)
+	}
+}
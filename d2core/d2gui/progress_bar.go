@@ -0,0 +1,120 @@
+package d2gui
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+)
+
+// FillDirection is the direction a progress bar fills in
+type FillDirection int
+
+const (
+	// FillDirectionHorizontal bars fill from left to right
+	FillDirectionHorizontal FillDirection = iota
+	// FillDirectionVertical bars fill from bottom to top
+	FillDirectionVertical
+)
+
+// ProgressBar is a track which is filled according to a progress fraction
+type ProgressBar struct {
+	widgetBase
+
+	direction FillDirection
+	width     int
+	height    int
+	progress  float64
+
+	filledColor color.Color
+	emptyColor  color.Color
+	fillSprite  d2interface.Animation
+}
+
+func createProgressBar(direction FillDirection, width, height int) *ProgressBar {
+	bar := &ProgressBar{
+		direction:   direction,
+		width:       width,
+		height:      height,
+		filledColor: color.RGBA{R: 0xc8, G: 0xb4, B: 0x64, A: 0xff},
+		emptyColor:  color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff},
+	}
+
+	bar.self = bar
+	bar.SetVisible(true)
+
+	return bar
+}
+
+// SetProgress sets the filled fraction of the track, clamped to [0, 1]
+func (b *ProgressBar) SetProgress(progress float64) {
+	b.progress = math.Max(0, math.Min(progress, 1))
+}
+
+// GetProgress returns the filled fraction of the track
+func (b *ProgressBar) GetProgress() float64 {
+	return b.progress
+}
+
+// SetColors sets the colors of the filled and empty parts of the track
+func (b *ProgressBar) SetColors(filled, empty color.Color) {
+	b.filledColor, b.emptyColor = filled, empty
+}
+
+// SetFillSprite sets a sprite drawn over the filled part of the track instead of the filled color. The sprite is
+// clipped to the progress, so it should be the size of the whole track.
+func (b *ProgressBar) SetFillSprite(imagePath, palettePath string) error {
+	animation, err := d2asset.LoadAnimation(imagePath, palettePath)
+	if err != nil {
+		return err
+	}
+
+	b.fillSprite = animation
+
+	return nil
+}
+
+// fillRect returns the filled part of the track, relative to the bar
+func (b *ProgressBar) fillRect() d2common.Rectangle {
+	if b.direction == FillDirectionVertical {
+		filled := int(math.Round(float64(b.height) * b.progress))
+		return d2common.Rectangle{Left: 0, Top: b.height - filled, Width: b.width, Height: filled}
+	}
+
+	filled := int(math.Round(float64(b.width) * b.progress))
+
+	return d2common.Rectangle{Left: 0, Top: 0, Width: filled, Height: b.height}
+}
+
+func (b *ProgressBar) getSize() (int, int) {
+	return b.width, b.height
+}
+
+func (b *ProgressBar) render(target d2interface.Surface) error {
+	if b.emptyColor != nil {
+		target.DrawRect(b.width, b.height, b.emptyColor)
+	}
+
+	fill := b.fillRect()
+	if fill.Width == 0 || fill.Height == 0 {
+		return nil
+	}
+
+	if b.fillSprite != nil {
+		target.PushClip(image.Rect(fill.Left, fill.Top, fill.Right(), fill.Bottom()))
+		defer target.Pop()
+
+		return b.fillSprite.Render(target)
+	}
+
+	if b.filledColor != nil {
+		target.PushTranslation(fill.Left, fill.Top)
+		target.DrawRect(fill.Width, fill.Height, b.filledColor)
+		target.Pop()
+	}
+
+	return nil
+}
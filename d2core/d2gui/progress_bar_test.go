@@ -0,0 +1,47 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+func TestProgressBarHalfFilled(t *testing.T) {
+	bar := createProgressBar(FillDirectionHorizontal, 100, 10)
+	bar.SetProgress(0.5)
+
+	wanted := d2common.Rectangle{Left: 0, Top: 0, Width: 50, Height: 10}
+	if got := bar.fillRect(); got != wanted {
+		t.Errorf("fill: wanted %v: got %v", wanted, got)
+	}
+}
+
+func TestProgressBarVertical(t *testing.T) {
+	bar := createProgressBar(FillDirectionVertical, 10, 100)
+	bar.SetProgress(0.25)
+
+	wanted := d2common.Rectangle{Left: 0, Top: 75, Width: 10, Height: 25}
+	if got := bar.fillRect(); got != wanted {
+		t.Errorf("fill: wanted %v: got %v", wanted, got)
+	}
+}
+
+func TestProgressBarClamp(t *testing.T) {
+	bar := createProgressBar(FillDirectionHorizontal, 100, 10)
+
+	tests := []struct {
+		progress, wanted float64
+	}{
+		{-1, 0},
+		{1.5, 1},
+		{0.3, 0.3},
+	}
+
+	for _, test := range tests {
+		bar.SetProgress(test.progress)
+
+		if got := bar.GetProgress(); got != test.wanted {
+			t.Errorf("progress %v: wanted %v: got %v", test.progress, test.wanted, got)
+		}
+	}
+}
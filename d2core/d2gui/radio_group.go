@@ -0,0 +1,151 @@
+package d2gui
+
+import (
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+)
+
+// RadioGroup manages a set of radio buttons, of which at most one is selected
+type RadioGroup struct {
+	buttons  []*RadioButton
+	selected int
+	onSelect func(index int)
+}
+
+// CreateRadioGroup creates an empty radio group. Buttons are added to it with Layout.AddRadioButton.
+func CreateRadioGroup() *RadioGroup {
+	return &RadioGroup{selected: -1}
+}
+
+// SetOnSelect sets the callback called with the index of the newly selected button
+func (g *RadioGroup) SetOnSelect(onSelect func(index int)) {
+	g.onSelect = onSelect
+}
+
+// GetSelected returns the index of the selected button, or -1 if none is selected
+func (g *RadioGroup) GetSelected() int {
+	return g.selected
+}
+
+// Select selects the button at the index, deselecting the others
+func (g *RadioGroup) Select(index int) {
+	if index < 0 || index >= len(g.buttons) || index == g.selected {
+		return
+	}
+
+	g.selected = index
+
+	for i, button := range g.buttons {
+		button.selected = i == index
+	}
+
+	if g.onSelect != nil {
+		g.onSelect(index)
+	}
+}
+
+// SelectNext selects the button after the selected one, wrapping around to the first
+func (g *RadioGroup) SelectNext() {
+	if len(g.buttons) > 0 {
+		g.Select((g.selected + 1) % len(g.buttons))
+	}
+}
+
+// SelectPrevious selects the button before the selected one, wrapping around to the last
+func (g *RadioGroup) SelectPrevious() {
+	if len(g.buttons) > 0 {
+		g.Select((g.selected - 1 + len(g.buttons)) % len(g.buttons))
+	}
+}
+
+func (g *RadioGroup) add(button *RadioButton) {
+	button.group = g
+	button.index = len(g.buttons)
+	g.buttons = append(g.buttons, button)
+
+	if g.selected < 0 {
+		g.Select(button.index)
+	}
+}
+
+// RadioButton is a widget which selects its option in a radio group when clicked
+type RadioButton struct {
+	widgetBase
+
+	animation d2interface.Animation
+	group     *RadioGroup
+	index     int
+	selected  bool
+}
+
+func createRadioButton(group *RadioGroup) (*RadioButton, error) {
+	animation, err := d2asset.LoadAnimation(d2resource.Checkbox, d2resource.PaletteFechar)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRadioButton(group, animation), nil
+}
+
+func newRadioButton(group *RadioGroup, animation d2interface.Animation) *RadioButton {
+	button := &RadioButton{animation: animation}
+
+	button.self = button
+	button.SetVisible(true)
+	group.add(button)
+
+	return button
+}
+
+// IsSelected returns true if the button is the selected option of its group
+func (b *RadioButton) IsSelected() bool {
+	return b.selected
+}
+
+// GetIndex returns the index of the button within its group
+func (b *RadioButton) GetIndex() int {
+	return b.index
+}
+
+func (b *RadioButton) onMouseButtonClick(event d2interface.MouseEvent) bool {
+	if event.Button() != d2enum.MouseButtonLeft {
+		return false
+	}
+
+	b.group.Select(b.index)
+
+	return b.widgetBase.onMouseButtonClick(event)
+}
+
+// onKeyDown selects the previous or next option of the group with the arrow keys
+func (b *RadioButton) onKeyDown(event d2interface.KeyEvent) bool {
+	switch event.Key() {
+	case d2enum.KeyUp, d2enum.KeyLeft:
+		b.group.SelectPrevious()
+	case d2enum.KeyDown, d2enum.KeyRight:
+		b.group.SelectNext()
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (b *RadioButton) render(target d2interface.Surface) error {
+	frame := checkboxFrameUnchecked
+	if b.selected {
+		frame = checkboxFrameChecked
+	}
+
+	if err := b.animation.SetCurrentFrame(frame); err != nil {
+		return err
+	}
+
+	return b.animation.Render(target)
+}
+
+func (b *RadioButton) getSize() (int, int) {
+	return b.animation.GetCurrentFrameSize()
+}
@@ -0,0 +1,54 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func TestRadioGroupSelect(t *testing.T) {
+	group := CreateRadioGroup()
+
+	buttons := make([]*RadioButton, 3)
+	for i := range buttons {
+		buttons[i] = newRadioButton(group, nil)
+	}
+
+	var selections []int
+
+	group.SetOnSelect(func(index int) {
+		selections = append(selections, index)
+	})
+
+	if group.GetSelected() != 0 || !buttons[0].IsSelected() {
+		t.Fatalf("initial selection: wanted %d: got %d", 0, group.GetSelected())
+	}
+
+	buttons[2].onMouseButtonClick(&testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft})
+
+	if group.GetSelected() != 2 {
+		t.Errorf("selection: wanted %d: got %d", 2, group.GetSelected())
+	}
+
+	for i, button := range buttons {
+		if button.IsSelected() != (i == 2) {
+			t.Errorf("button %d selected: wanted %v: got %v", i, i == 2, button.IsSelected())
+		}
+	}
+
+	if len(selections) != 1 || selections[0] != 2 {
+		t.Errorf("select callbacks: wanted %v: got %v", []int{2}, selections)
+	}
+
+	group.SelectNext()
+
+	if group.GetSelected() != 0 {
+		t.Errorf("selection after wrapping: wanted %d: got %d", 0, group.GetSelected())
+	}
+
+	group.SelectPrevious()
+
+	if group.GetSelected() != 2 {
+		t.Errorf("selection after wrapping back: wanted %d: got %d", 2, group.GetSelected())
+	}
+}
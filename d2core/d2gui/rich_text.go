@@ -0,0 +1,120 @@
+package d2gui
+
+import (
+	"image/color"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// colorCodePrefix starts a color code in game text, it is followed by a character selecting the color of the text
+// after it, for example "ÿc3" for the blue of magic items
+const colorCodePrefix = "ÿc"
+
+// defaultTextColor is the color of text before its first color code, and of text after the white code
+var defaultTextColor = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+
+// textColors are the colors selected by color codes
+var textColors = map[rune]color.RGBA{
+	'0': defaultTextColor,
+	'1': {R: 0xff, G: 0x4d, B: 0x4d, A: 0xff}, // red
+	'2': {R: 0x00, G: 0xff, B: 0x00, A: 0xff}, // green, set items
+	'3': {R: 0x69, G: 0x69, B: 0xff, A: 0xff}, // blue, magic items
+	'4': {R: 0xc7, G: 0xb3, B: 0x77, A: 0xff}, // gold, unique items
+	'5': {R: 0x69, G: 0x69, B: 0x69, A: 0xff}, // gray, socketed and ethereal items
+	'6': {R: 0x00, G: 0x00, B: 0x00, A: 0xff}, // black
+	'7': {R: 0xd0, G: 0xc2, B: 0x7d, A: 0xff}, // tan
+	'8': {R: 0xff, G: 0xa8, B: 0x00, A: 0xff}, // orange, crafted items
+	'9': {R: 0xff, G: 0xff, B: 0x64, A: 0xff}, // yellow, rare items
+	':': {R: 0x00, G: 0x80, B: 0x00, A: 0xff}, // dark green
+	';': {R: 0xae, G: 0x00, B: 0xff, A: 0xff}, // purple
+}
+
+// textSegment is a run of text drawn in one color
+type textSegment struct {
+	text  string
+	color color.Color
+}
+
+// parseColorCodes splits text into runs of a single color, removing the color codes. Codes selecting an unknown
+// color are left in the text. Empty runs are dropped.
+func parseColorCodes(text string) []textSegment {
+	var segments []textSegment
+
+	var current strings.Builder
+
+	currentColor := color.Color(defaultTextColor)
+
+	for len(text) > 0 {
+		index := strings.Index(text, colorCodePrefix)
+		if index < 0 {
+			current.WriteString(text)
+			break
+		}
+
+		current.WriteString(text[:index])
+		text = text[index+len(colorCodePrefix):]
+
+		code, size := utf8.DecodeRuneInString(text)
+
+		newColor, ok := textColors[code]
+		if !ok {
+			current.WriteString(colorCodePrefix)
+			continue
+		}
+
+		text = text[size:]
+
+		if current.Len() > 0 {
+			segments = append(segments, textSegment{text: current.String(), color: currentColor})
+			current.Reset()
+		}
+
+		currentColor = newColor
+	}
+
+	if current.Len() > 0 {
+		segments = append(segments, textSegment{text: current.String(), color: currentColor})
+	}
+
+	return segments
+}
+
+// StripColorCodes returns the text with its color codes removed
+func StripColorCodes(text string) string {
+	var stripped strings.Builder
+
+	for _, segment := range parseColorCodes(text) {
+		stripped.WriteString(segment.text)
+	}
+
+	return stripped.String()
+}
+
+// MeasureText returns the size of the text drawn with the font, ignoring its color codes
+func MeasureText(font d2interface.Font, text string) (width, height int) {
+	return font.GetTextMetrics(StripColorCodes(text))
+}
+
+// renderColoredText draws text with the font, in the colors selected by its color codes
+func renderColoredText(font d2interface.Font, text string, target d2interface.Surface) error {
+	var x int
+
+	for _, segment := range parseColorCodes(text) {
+		font.SetColor(segment.color)
+
+		target.PushTranslation(x, 0)
+		err := font.RenderText(segment.text, target)
+		target.Pop()
+
+		if err != nil {
+			return err
+		}
+
+		width, _ := font.GetTextMetrics(segment.text)
+		x += width
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package d2gui
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// testColorFont is a test font which records the text it renders and the color it's rendered in
+type testColorFont struct {
+	testFont
+	color  color.Color
+	texts  []string
+	colors []color.Color
+}
+
+func (f *testColorFont) SetColor(c color.Color) {
+	f.color = c
+}
+
+func (f *testColorFont) RenderText(text string, target d2interface.Surface) error {
+	f.texts = append(f.texts, text)
+	f.colors = append(f.colors, f.color)
+
+	return nil
+}
+
+func TestLabelColorCodes(t *testing.T) {
+	font := &testColorFont{}
+	label := newLabel(&testRenderer{}, font, "ÿc3Magicÿc0 Sword")
+
+	plainWidth, _ := font.GetTextMetrics("Magic Sword")
+	if width, _ := label.getSize(); width != plainWidth {
+		t.Errorf("width: wanted %d: got %d", plainWidth, width)
+	}
+
+	wantTexts := []string{"Magic", " Sword"}
+	wantColors := []color.Color{textColors['3'], textColors['0']}
+
+	if len(font.texts) != len(wantTexts) {
+		t.Fatalf("rendered segments: wanted %q: got %q", wantTexts, font.texts)
+	}
+
+	for i := range wantTexts {
+		if font.texts[i] != wantTexts[i] || font.colors[i] != wantColors[i] {
+			t.Errorf("segment %d: wanted %q in %v: got %q in %v", i, wantTexts[i], wantColors[i], font.texts[i],
+				font.colors[i])
+		}
+	}
+}
+
+func TestStripColorCodes(t *testing.T) {
+	tests := []struct {
+		text     string
+		stripped string
+	}{
+		{"plain text", "plain text"},
+		{"ÿc4Unique ÿc1Red", "Unique Red"},
+		{"ÿc:ÿc;Purple", "Purple"},
+		// unknown and unfinished codes are left in the text
+		{"ÿcZunknown", "ÿcZunknown"},
+		{"unfinished ÿc", "unfinished ÿc"},
+		{"ÿcÿc2Green", "ÿcGreen"},
+	}
+
+	for _, test := range tests {
+		if got := StripColorCodes(test.text); got != test.stripped {
+			t.Errorf("stripping %q: wanted %q: got %q", test.text, test.stripped, got)
+		}
+
+		width, _ := MeasureText(&testFont{}, test.text)
+		if want := len(test.stripped) * 10; width != want {
+			t.Errorf("measuring %q: wanted %d: got %d", test.text, want, width)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package d2gui
+
+import (
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+const (
+	scrollbarWidth    = 10
+	scrollbarMinThumb = 10
+
+	// scrollWheelDistance is the number of pixels scrolled per wheel step
+	scrollWheelDistance = 20
+)
+
+// ScrollContainer is a fixed size viewport onto a vertical layout, which is
+// scrolled with the mouse wheel, the scrollbar or by calling ScrollBy. Children outside the
+// viewport are clipped.
+type ScrollContainer struct {
+	widgetBase
+
+	renderer d2interface.Renderer
+	surface  d2interface.Surface
+	content  *Layout
+
+	width  int
+	height int
+
+	scrollOffset     int
+	scrollbarVisible bool
+
+	dragging        bool
+	dragStartY      int
+	dragStartOffset int
+}
+
+func createScrollContainer(renderer d2interface.Renderer, width, height int) *ScrollContainer {
+	container := &ScrollContainer{
+		renderer:         renderer,
+		content:          createLayout(renderer, PositionTypeVertical),
+		width:            width,
+		height:           height,
+		scrollbarVisible: true,
+	}
+
+	container.content.SetSize(container.contentWidth(), 0)
+	container.self = container
+	container.SetVisible(true)
+
+	return container
+}
+
+// GetContent returns the layout holding the scrolled children
+func (c *ScrollContainer) GetContent() *Layout {
+	return c.content
+}
+
+// GetContentSize returns the size of the scrolled children
+func (c *ScrollContainer) GetContentSize() (width, height int) {
+	return c.content.getSize()
+}
+
+// SetScrollbarVisible sets whether the scrollbar is drawn and can be dragged
+func (c *ScrollContainer) SetScrollbarVisible(visible bool) {
+	c.scrollbarVisible = visible
+	c.content.SetSize(c.contentWidth(), 0)
+}
+
+// GetScrollOffset returns how many pixels the content is scrolled by
+func (c *ScrollContainer) GetScrollOffset() int {
+	return c.scrollOffset
+}
+
+// SetScrollOffset scrolls the content to the given offset, clamped to the content size
+func (c *ScrollContainer) SetScrollOffset(offset int) {
+	c.scrollOffset = d2common.MaxInt(0, d2common.MinInt(offset, c.maxScrollOffset()))
+	c.placeContent()
+}
+
+// ScrollBy scrolls the content by the given number of pixels, positive values scroll down
+func (c *ScrollContainer) ScrollBy(delta int) {
+	c.SetScrollOffset(c.scrollOffset + delta)
+}
+
+// SetScreenPos sets the screen position, and moves the content to match
+func (c *ScrollContainer) SetScreenPos(x, y int) {
+	c.widgetBase.SetScreenPos(x, y)
+	c.placeContent()
+}
+
+// placeContent moves the content's screen position to the scroll offset
+func (c *ScrollContainer) placeContent() {
+	c.content.SetScreenPos(c.Sx, c.Sy-c.scrollOffset)
+	c.content.AdjustEntryPlacement()
+}
+
+// visibleRect returns the part of the content shown in the viewport, in content coordinates
+func (c *ScrollContainer) visibleRect() d2common.Rectangle {
+	return d2common.Rectangle{Left: 0, Top: c.scrollOffset, Width: c.contentWidth(), Height: c.height}
+}
+
+func (c *ScrollContainer) contentWidth() int {
+	if c.scrollbarVisible {
+		return d2common.MaxInt(0, c.width-scrollbarWidth)
+	}
+
+	return c.width
+}
+
+func (c *ScrollContainer) maxScrollOffset() int {
+	_, contentHeight := c.content.getSize()
+	return d2common.MaxInt(0, contentHeight-c.height)
+}
+
+// thumbRect returns the scrollbar thumb, relative to the container
+func (c *ScrollContainer) thumbRect() d2common.Rectangle {
+	_, contentHeight := c.content.getSize()
+
+	thumbHeight := c.height
+	if contentHeight > c.height {
+		thumbHeight = d2common.MaxInt(scrollbarMinThumb, c.height*c.height/contentHeight)
+	}
+
+	var thumbY int
+	if maxOffset := c.maxScrollOffset(); maxOffset > 0 {
+		thumbY = c.scrollOffset * (c.height - thumbHeight) / maxOffset
+	}
+
+	return d2common.Rectangle{Left: c.width - scrollbarWidth, Top: thumbY, Width: scrollbarWidth, Height: thumbHeight}
+}
+
+func (c *ScrollContainer) getSize() (int, int) {
+	return c.width, c.height
+}
+
+func (c *ScrollContainer) advance(elapsed float64) error {
+	return c.content.advance(elapsed)
+}
+
+func (c *ScrollContainer) render(target d2interface.Surface) error {
+	if c.surface == nil {
+		surface, err := c.renderer.NewSurface(c.width, c.height, d2enum.FilterNearest)
+		if err != nil {
+			return err
+		}
+
+		c.surface = surface
+	}
+
+	if err := c.surface.Clear(color.Transparent); err != nil {
+		return err
+	}
+
+	c.placeContent()
+
+	// Cull content outside the viewport
+	viewport := d2common.Rectangle{Left: c.Sx, Top: c.Sy, Width: c.contentWidth(), Height: c.height}
+	if clip := c.screenClip(); clip != nil {
+		viewport = viewport.Intersect(*clip)
+	}
+
+	c.content.setInheritedClip(&viewport)
+
+	c.surface.PushTranslation(0, -c.scrollOffset)
+	err := c.content.render(c.surface)
+	c.surface.Pop()
+
+	if err != nil {
+		return err
+	}
+
+	if err := target.Render(c.surface); err != nil {
+		return err
+	}
+
+	if c.scrollbarVisible {
+		c.renderScrollbar(target)
+	}
+
+	return nil
+}
+
+func (c *ScrollContainer) renderScrollbar(target d2interface.Surface) {
+	target.PushTranslation(c.width-scrollbarWidth, 0)
+	target.DrawRect(scrollbarWidth, c.height, color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff})
+	target.Pop()
+
+	thumb := c.thumbRect()
+
+	target.PushTranslation(thumb.Left, thumb.Top)
+	target.DrawRect(thumb.Width, thumb.Height, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff})
+	target.Pop()
+}
+
+func (c *ScrollContainer) onMouseButtonDown(event d2interface.MouseEvent) bool {
+	thumb := c.thumbRect()
+	thumb.Left += c.Sx
+	thumb.Top += c.Sy
+
+	if c.scrollbarVisible && thumb.IsInRect(event.X(), event.Y()) {
+		c.dragging = true
+		c.dragStartY = event.Y()
+		c.dragStartOffset = c.scrollOffset
+
+		return true
+	}
+
+	return c.content.onMouseButtonDown(event)
+}
+
+func (c *ScrollContainer) onMouseButtonUp(event d2interface.MouseEvent) bool {
+	if c.dragging {
+		c.dragging = false
+		return true
+	}
+
+	return c.content.onMouseButtonUp(event)
+}
+
+func (c *ScrollContainer) onMouseMove(event d2interface.MouseMoveEvent) bool {
+	if c.dragging {
+		thumb := c.thumbRect()
+		if track := c.height - thumb.Height; track > 0 {
+			c.SetScrollOffset(c.dragStartOffset + (event.Y()-c.dragStartY)*c.maxScrollOffset()/track)
+		}
+
+		return true
+	}
+
+	return c.content.onMouseMove(event)
+}
+
+func (c *ScrollContainer) onMouseLeave(event d2interface.MouseMoveEvent) bool {
+	c.dragging = false
+
+	// Forward the move so hovered children see the cursor leave
+	c.content.onMouseMove(event)
+
+	return c.widgetBase.onMouseLeave(event)
+}
+
+func (c *ScrollContainer) onClickOutside(event d2interface.MouseEvent) {
+	c.content.onClickOutside(event)
+}
+
+func (c *ScrollContainer) childWidgets() []widget {
+	return []widget{c.content}
+}
+
+func (c *ScrollContainer) widgetAt(x, y int) widget {
+	return c.content.widgetAt(x, y)
+}
+
+// onMouseWheel scrolls the content, unless a child under the cursor handles the event
+func (c *ScrollContainer) onMouseWheel(event d2interface.MouseWheelEvent) bool {
+	if c.content.onMouseWheel(event) {
+		return true
+	}
+
+	c.widgetBase.onMouseWheel(event)
+	c.ScrollBy(int(-event.DeltaY() * scrollWheelDistance))
+
+	return true
+}
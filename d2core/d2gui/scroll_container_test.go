@@ -0,0 +1,86 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+func TestScrollContainerVisibleChildren(t *testing.T) {
+	container := createScrollContainer(nil, 50, 40)
+
+	for i := 0; i < 5; i++ {
+		container.GetContent().AddSpacerStatic(10, 20)
+	}
+
+	container.SetScreenPos(0, 0)
+
+	visibleChildren := func() []int {
+		var visible []int
+
+		view := container.visibleRect()
+
+		for i, entry := range container.GetContent().entries {
+			rect := d2common.Rectangle{Left: entry.x, Top: entry.y, Width: entry.width, Height: entry.height}
+			if rect.Top < view.Top+view.Height && view.Top < rect.Top+rect.Height {
+				visible = append(visible, i)
+			}
+		}
+
+		return visible
+	}
+
+	tests := []struct {
+		offset  int
+		wanted  int
+		visible []int
+	}{
+		{0, 0, []int{0, 1}},
+		{40, 40, []int{2, 3}},
+		{30, 30, []int{1, 2, 3}},
+		{100, 60, []int{3, 4}},
+		{-10, 0, []int{0, 1}},
+	}
+
+	for _, test := range tests {
+		container.SetScrollOffset(test.offset)
+
+		if got := container.GetScrollOffset(); got != test.wanted {
+			t.Errorf("scroll offset %d: wanted %d: got %d", test.offset, test.wanted, got)
+		}
+
+		visible := visibleChildren()
+		if len(visible) != len(test.visible) {
+			t.Errorf("scroll offset %d visible children: wanted %v: got %v", test.offset, test.visible, visible)
+			continue
+		}
+
+		for i := range visible {
+			if visible[i] != test.visible[i] {
+				t.Errorf("scroll offset %d visible children: wanted %v: got %v", test.offset, test.visible, visible)
+				break
+			}
+		}
+	}
+}
+
+func TestScrollContainerMouseWheel(t *testing.T) {
+	container := createScrollContainer(nil, 50, 40)
+
+	for i := 0; i < 5; i++ {
+		container.GetContent().AddSpacerStatic(10, 20)
+	}
+
+	container.SetScreenPos(0, 0)
+	container.onMouseWheel(&testMouseWheelEvent{testMouseMoveEvent{x: 5, y: 5}, -1})
+
+	if got := container.GetScrollOffset(); got != scrollWheelDistance {
+		t.Errorf("scroll offset: wanted %d: got %d", scrollWheelDistance, got)
+	}
+
+	container.onMouseWheel(&testMouseWheelEvent{testMouseMoveEvent{x: 5, y: 5}, 1})
+
+	if got := container.GetScrollOffset(); got != 0 {
+		t.Errorf("scroll offset: wanted %d: got %d", 0, got)
+	}
+}
@@ -0,0 +1,174 @@
+package d2gui
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+const (
+	sliderThickness = 10
+	sliderThumbSize = 8
+)
+
+// SliderOrientation is the direction a slider's track runs in
+type SliderOrientation int
+
+const (
+	// SliderOrientationHorizontal sliders have their minimum on the left
+	SliderOrientationHorizontal SliderOrientation = iota
+	// SliderOrientationVertical sliders have their minimum at the bottom
+	SliderOrientationVertical
+)
+
+// Slider is a widget for choosing a value within a range by dragging a thumb along a track
+type Slider struct {
+	widgetBase
+
+	orientation SliderOrientation
+	length      int
+
+	min      float64
+	max      float64
+	value    float64
+	dragging bool
+	onChange func(value float64)
+}
+
+func createSlider(orientation SliderOrientation, length int) *Slider {
+	slider := &Slider{
+		orientation: orientation,
+		length:      length,
+		max:         1,
+	}
+
+	slider.self = slider
+	slider.SetVisible(true)
+
+	return slider
+}
+
+// SetRange sets the minimum and maximum value, clamping the current value to the range
+func (s *Slider) SetRange(min, max float64) {
+	s.min, s.max = min, max
+	s.value = s.clamp(s.value)
+}
+
+// GetRange returns the minimum and maximum value
+func (s *Slider) GetRange() (min, max float64) {
+	return s.min, s.max
+}
+
+// SetValue sets the value, clamped to the range, without calling the change callback
+func (s *Slider) SetValue(value float64) {
+	s.value = s.clamp(value)
+}
+
+// GetValue returns the value
+func (s *Slider) GetValue() float64 {
+	return s.value
+}
+
+// SetOnChange sets the callback called with the new value when the slider is dragged or its track is clicked
+func (s *Slider) SetOnChange(onChange func(value float64)) {
+	s.onChange = onChange
+}
+
+func (s *Slider) clamp(value float64) float64 {
+	return math.Max(s.min, math.Min(value, s.max))
+}
+
+// fraction returns how far along the range the value is, from 0 to 1
+func (s *Slider) fraction() float64 {
+	if s.max <= s.min {
+		return 0
+	}
+
+	return (s.value - s.min) / (s.max - s.min)
+}
+
+// setValueAt sets the value from a screen position along the track, calling the change callback if it changed
+func (s *Slider) setValueAt(x, y int) {
+	var fraction float64
+
+	if s.length > 0 {
+		switch s.orientation {
+		case SliderOrientationHorizontal:
+			fraction = float64(x-s.Sx) / float64(s.length)
+		case SliderOrientationVertical:
+			fraction = float64(s.Sy+s.length-y) / float64(s.length)
+		}
+	}
+
+	value := s.clamp(s.min + fraction*(s.max-s.min))
+	if value == s.value {
+		return
+	}
+
+	s.value = value
+
+	if s.onChange != nil {
+		s.onChange(value)
+	}
+}
+
+func (s *Slider) onMouseButtonDown(event d2interface.MouseEvent) bool {
+	if event.Button() != d2enum.MouseButtonLeft {
+		return false
+	}
+
+	s.dragging = true
+	s.setValueAt(event.X(), event.Y())
+
+	return true
+}
+
+func (s *Slider) onMouseButtonUp(event d2interface.MouseEvent) bool {
+	s.dragging = false
+	return false
+}
+
+func (s *Slider) onMouseMove(event d2interface.MouseMoveEvent) bool {
+	if !s.dragging {
+		return false
+	}
+
+	s.setValueAt(event.X(), event.Y())
+
+	return true
+}
+
+func (s *Slider) onMouseLeave(event d2interface.MouseMoveEvent) bool {
+	s.dragging = false
+	return s.widgetBase.onMouseLeave(event)
+}
+
+func (s *Slider) render(target d2interface.Surface) error {
+	width, height := s.getSize()
+	target.DrawRect(width, height, color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff})
+
+	offset := int(s.fraction()*float64(s.length)) - sliderThumbSize/2
+
+	switch s.orientation {
+	case SliderOrientationHorizontal:
+		target.PushTranslation(offset, 0)
+		target.DrawRect(sliderThumbSize, sliderThickness, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff})
+	case SliderOrientationVertical:
+		target.PushTranslation(0, s.length-offset-sliderThumbSize)
+		target.DrawRect(sliderThickness, sliderThumbSize, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff})
+	}
+
+	target.Pop()
+
+	return nil
+}
+
+func (s *Slider) getSize() (int, int) {
+	if s.orientation == SliderOrientationVertical {
+		return sliderThickness, s.length
+	}
+
+	return s.length, sliderThickness
+}
@@ -0,0 +1,90 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func TestSliderClick(t *testing.T) {
+	tests := []struct {
+		orientation SliderOrientation
+		x, y        int
+		wanted      float64
+	}{
+		{SliderOrientationHorizontal, 25, 5, 2.5},
+		{SliderOrientationHorizontal, 75, 5, 7.5},
+		{SliderOrientationVertical, 5, 75, 2.5},
+		{SliderOrientationVertical, 5, 25, 7.5},
+	}
+
+	for _, test := range tests {
+		slider := createSlider(test.orientation, 100)
+		slider.SetRange(0, 10)
+
+		var changes []float64
+
+		slider.SetOnChange(func(value float64) {
+			changes = append(changes, value)
+		})
+
+		event := &testMouseEvent{testMouseMoveEvent{x: test.x, y: test.y}, d2enum.MouseButtonLeft}
+		slider.onMouseButtonDown(event)
+		slider.onMouseButtonUp(event)
+
+		if got := slider.GetValue(); got != test.wanted {
+			t.Errorf("click at (%d, %d): wanted %v: got %v", test.x, test.y, test.wanted, got)
+		}
+
+		if len(changes) != 1 || changes[0] != test.wanted {
+			t.Errorf("click at (%d, %d) change callbacks: wanted %v: got %v", test.x, test.y,
+				[]float64{test.wanted}, changes)
+		}
+	}
+}
+
+func TestSliderDrag(t *testing.T) {
+	slider := createSlider(SliderOrientationHorizontal, 100)
+	slider.SetRange(0, 10)
+
+	slider.onMouseButtonDown(&testMouseEvent{testMouseMoveEvent{x: 10, y: 5}, d2enum.MouseButtonLeft})
+	slider.onMouseMove(&testMouseMoveEvent{x: 200, y: 5})
+
+	if got := slider.GetValue(); got != 10 {
+		t.Errorf("dragged past the end: wanted %v: got %v", 10, got)
+	}
+
+	slider.onMouseButtonUp(&testMouseEvent{testMouseMoveEvent{x: 200, y: 5}, d2enum.MouseButtonLeft})
+	slider.onMouseMove(&testMouseMoveEvent{x: 50, y: 5})
+
+	if got := slider.GetValue(); got != 10 {
+		t.Errorf("moved after release: wanted %v: got %v", 10, got)
+	}
+}
+
+func TestSliderClamp(t *testing.T) {
+	slider := createSlider(SliderOrientationHorizontal, 100)
+	slider.SetRange(-5, 5)
+
+	tests := []struct {
+		value, wanted float64
+	}{
+		{-10, -5},
+		{10, 5},
+		{2, 2},
+	}
+
+	for _, test := range tests {
+		slider.SetValue(test.value)
+
+		if got := slider.GetValue(); got != test.wanted {
+			t.Errorf("SetValue(%v): wanted %v: got %v", test.value, test.wanted, got)
+		}
+	}
+
+	slider.SetRange(0, 1)
+
+	if got := slider.GetValue(); got != 1 {
+		t.Errorf("value after narrowing the range: wanted %v: got %v", 1, got)
+	}
+}
@@ -9,6 +9,7 @@ type SpacerStatic struct {
 
 func createSpacerStatic(width, height int) *SpacerStatic {
 	spacer := &SpacerStatic{width: width, height: height}
+	spacer.self = spacer
 	spacer.SetVisible(true)
 
 	return spacer
@@ -24,6 +25,7 @@ type SpacerDynamic struct {
 
 func createSpacerDynamic() *SpacerDynamic {
 	spacer := &SpacerDynamic{}
+	spacer.self = spacer
 	spacer.SetVisible(true)
 	spacer.SetExpanding(true)
 
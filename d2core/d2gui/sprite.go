@@ -35,6 +35,7 @@ func createSprite(imagePath, palettePath string) (*Sprite, error) {
 
 	sprite := &Sprite{}
 	sprite.animation = animation
+	sprite.self = sprite
 	sprite.SetVisible(true)
 
 	return sprite, nil
@@ -54,6 +55,7 @@ func createAnimatedSprite(imagePath, palettePath string, direction AnimationDire
 	} else {
 		sprite.animation.PlayBackward()
 	}
+	sprite.self = sprite
 	sprite.SetVisible(true)
 
 	return sprite, nil
@@ -0,0 +1,343 @@
+package d2gui
+
+import (
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+const tabPadding = 4
+
+// TabContainer is a fixed size container of named pages, with a row of tabs along the top. Only the page of the
+// active tab is shown and receives events, clicking a tab makes it active.
+type TabContainer struct {
+	widgetBase
+
+	font   d2interface.Font
+	width  int
+	height int
+
+	tabBar *Layout
+	names  []string
+	pages  []*Layout
+
+	active      int
+	onTabChange func(index int)
+}
+
+func createTabContainer(renderer d2interface.Renderer, width, height int, fontStyle FontStyle) (*TabContainer, error) {
+	font, err := loadFont(fontStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTabContainer(renderer, font, width, height), nil
+}
+
+func newTabContainer(renderer d2interface.Renderer, font d2interface.Font, width, height int) *TabContainer {
+	container := &TabContainer{
+		font:   font,
+		width:  width,
+		height: height,
+		tabBar: createLayout(renderer, PositionTypeHorizontal),
+	}
+
+	container.self = container
+	container.SetVisible(true)
+
+	return container
+}
+
+// AddTab adds a named page after the existing ones and returns its layout. The first page added is active.
+func (c *TabContainer) AddTab(name string) *Layout {
+	index := len(c.pages)
+
+	page := createLayout(c.tabBar.renderer, PositionTypeVertical)
+	page.SetSize(c.width, c.pageHeight())
+
+	c.names = append(c.names, name)
+	c.pages = append(c.pages, page)
+
+	tab := &tabButton{container: c, index: index}
+	tab.self = tab
+	tab.SetVisible(true)
+	c.tabBar.addEntry(tab)
+	c.placeContent()
+
+	return page
+}
+
+// GetTab returns the layout of the page at the index, or nil if there is no such page
+func (c *TabContainer) GetTab(index int) *Layout {
+	if index < 0 || index >= len(c.pages) {
+		return nil
+	}
+
+	return c.pages[index]
+}
+
+// GetTabCount returns the number of pages
+func (c *TabContainer) GetTabCount() int {
+	return len(c.pages)
+}
+
+// GetActiveTab returns the index of the active page
+func (c *TabContainer) GetActiveTab() int {
+	return c.active
+}
+
+// SetActiveTab shows the page at the index without calling the tab change callback
+func (c *TabContainer) SetActiveTab(index int) {
+	if index < 0 || index >= len(c.pages) || index == c.active {
+		return
+	}
+
+	// Forward a move away from the page, so hovered children see the cursor leave
+	c.activePage().onMouseMove(&leaveEvent{})
+
+	c.active = index
+	c.placeContent()
+}
+
+// SetOnTabChange sets the callback called with the index of the page activated by clicking its tab
+func (c *TabContainer) SetOnTabChange(onTabChange func(index int)) {
+	c.onTabChange = onTabChange
+}
+
+// selectTab activates the page of a clicked tab
+func (c *TabContainer) selectTab(index int) {
+	if index == c.active {
+		return
+	}
+
+	c.SetActiveTab(index)
+
+	if c.onTabChange != nil {
+		c.onTabChange(index)
+	}
+}
+
+// SetScreenPos sets the screen position, and moves the tabs and pages to match
+func (c *TabContainer) SetScreenPos(x, y int) {
+	c.widgetBase.SetScreenPos(x, y)
+	c.placeContent()
+}
+
+func (c *TabContainer) placeContent() {
+	c.tabBar.SetScreenPos(c.Sx, c.Sy)
+	c.tabBar.AdjustEntryPlacement()
+
+	if page := c.activePage(); page != nil {
+		page.SetScreenPos(c.Sx, c.Sy+c.tabHeight())
+		page.AdjustEntryPlacement()
+	}
+}
+
+func (c *TabContainer) activePage() *Layout {
+	return c.GetTab(c.active)
+}
+
+func (c *TabContainer) tabHeight() int {
+	_, height := c.font.GetTextMetrics("A")
+	return height + tabPadding*2
+}
+
+func (c *TabContainer) pageHeight() int {
+	return c.height - c.tabHeight()
+}
+
+func (c *TabContainer) getSize() (int, int) {
+	return c.width, c.height
+}
+
+func (c *TabContainer) advance(elapsed float64) error {
+	if err := c.tabBar.advance(elapsed); err != nil {
+		return err
+	}
+
+	if page := c.activePage(); page != nil {
+		return page.advance(elapsed)
+	}
+
+	return nil
+}
+
+func (c *TabContainer) render(target d2interface.Surface) error {
+	c.placeContent()
+
+	for _, content := range c.content() {
+		content.setInheritedClip(c.screenClip())
+		content.setInheritedOpacity(c.renderOpacity())
+	}
+
+	if err := c.tabBar.render(target); err != nil {
+		return err
+	}
+
+	page := c.activePage()
+	if page == nil {
+		return nil
+	}
+
+	target.PushTranslation(0, c.tabHeight())
+	defer target.Pop()
+
+	return page.render(target)
+}
+
+// content returns the tab bar and the active page, the parts of the container which are shown
+func (c *TabContainer) content() []*Layout {
+	if page := c.activePage(); page != nil {
+		return []*Layout{c.tabBar, page}
+	}
+
+	return []*Layout{c.tabBar}
+}
+
+func (c *TabContainer) onMouseButtonDown(event d2interface.MouseEvent) bool {
+	for _, content := range c.content() {
+		if content.onMouseButtonDown(event) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *TabContainer) onMouseButtonUp(event d2interface.MouseEvent) bool {
+	var handled bool
+
+	// Both are released, as the button may have been pressed on the other
+	for _, content := range c.content() {
+		handled = content.onMouseButtonUp(event) || handled
+	}
+
+	return handled
+}
+
+func (c *TabContainer) onMouseMove(event d2interface.MouseMoveEvent) bool {
+	var handled bool
+
+	for _, content := range c.content() {
+		handled = content.onMouseMove(event) || handled
+	}
+
+	return handled
+}
+
+func (c *TabContainer) onMouseLeave(event d2interface.MouseMoveEvent) bool {
+	// Forward the move so hovered children see the cursor leave
+	c.onMouseMove(event)
+
+	return c.widgetBase.onMouseLeave(event)
+}
+
+func (c *TabContainer) onMouseWheel(event d2interface.MouseWheelEvent) bool {
+	if page := c.activePage(); page != nil && page.onMouseWheel(event) {
+		return true
+	}
+
+	return c.widgetBase.onMouseWheel(event)
+}
+
+func (c *TabContainer) onClickOutside(event d2interface.MouseEvent) {
+	for _, content := range c.content() {
+		content.onClickOutside(event)
+	}
+}
+
+// childWidgets only returns the shown parts, so widgets on inactive pages can't be focused
+func (c *TabContainer) childWidgets() []widget {
+	content := c.content()
+
+	widgets := make([]widget, len(content))
+	for i, layout := range content {
+		widgets[i] = layout
+	}
+
+	return widgets
+}
+
+func (c *TabContainer) widgetAt(x, y int) widget {
+	content := c.content()
+
+	for i := len(content) - 1; i >= 0; i-- {
+		if w := content[i].widgetAt(x, y); w != nil {
+			return w
+		}
+	}
+
+	return nil
+}
+
+// leaveEvent is a mouse move outside every widget, sent to a page when it's hidden
+type leaveEvent struct{}
+
+func (e *leaveEvent) KeyMod() d2enum.KeyMod {
+	return 0
+}
+
+func (e *leaveEvent) ButtonMod() d2enum.MouseButtonMod {
+	return 0
+}
+
+func (e *leaveEvent) X() int {
+	return -1
+}
+
+func (e *leaveEvent) Y() int {
+	return -1
+}
+
+// tabButton is a tab of a tab container, which activates its page when clicked
+type tabButton struct {
+	widgetBase
+
+	container *TabContainer
+	index     int
+	hovered   bool
+}
+
+func (t *tabButton) getSize() (int, int) {
+	width, _ := t.container.font.GetTextMetrics(t.container.names[t.index])
+	return width + tabPadding*2, t.container.tabHeight()
+}
+
+func (t *tabButton) render(target d2interface.Surface) error {
+	width, height := t.getSize()
+
+	switch {
+	case t.index == t.container.active:
+		target.DrawRect(width, height, color.RGBA{R: 0x60, G: 0x60, B: 0x60, A: 0xff})
+	case t.hovered:
+		target.DrawRect(width, height, color.RGBA{R: 0x40, G: 0x40, B: 0x40, A: 0xff})
+	default:
+		target.DrawRect(width, height, color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff})
+	}
+
+	target.PushTranslation(tabPadding, tabPadding)
+	defer target.Pop()
+
+	return t.container.font.RenderText(t.container.names[t.index], target)
+}
+
+func (t *tabButton) onMouseEnter(event d2interface.MouseMoveEvent) bool {
+	t.hovered = true
+	return t.widgetBase.onMouseEnter(event)
+}
+
+func (t *tabButton) onMouseLeave(event d2interface.MouseMoveEvent) bool {
+	t.hovered = false
+	return t.widgetBase.onMouseLeave(event)
+}
+
+func (t *tabButton) onMouseButtonClick(event d2interface.MouseEvent) bool {
+	if event.Button() != d2enum.MouseButtonLeft {
+		return false
+	}
+
+	t.container.selectTab(t.index)
+
+	return true
+}
@@ -0,0 +1,72 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+func TestTabContainerSwitch(t *testing.T) {
+	layout := createLayout(nil, PositionTypeAbsolute)
+	container := newTabContainer(nil, &testFont{}, 200, 100)
+	layout.addEntry(container)
+
+	// the tabs are 18 pixels high, and 58, 58 and 48 pixels wide
+	var clicks [3]int
+
+	for i, name := range []string{"Items", "Skill", "Merc"} {
+		index := i
+		spacer := container.AddTab(name).AddSpacerStatic(50, 50)
+		spacer.SetMouseClickHandler(func(event d2interface.MouseEvent) {
+			clicks[index]++
+		})
+	}
+
+	var changes []int
+
+	container.SetOnTabChange(func(index int) {
+		changes = append(changes, index)
+	})
+
+	if _, y := container.GetTab(0).ScreenPos(); y != 18 {
+		t.Errorf("page top: wanted %d: got %d", 18, y)
+	}
+
+	clickLayout(layout, 10, 30)
+
+	if clicks != [3]int{1, 0, 0} {
+		t.Errorf("page clicks before switching: wanted %v: got %v", [3]int{1, 0, 0}, clicks)
+	}
+
+	clickLayout(layout, 130, 5)
+
+	if got := container.GetActiveTab(); got != 2 {
+		t.Errorf("active tab: wanted %d: got %d", 2, got)
+	}
+
+	if len(changes) != 1 || changes[0] != 2 {
+		t.Errorf("tab change callbacks: wanted %v: got %v", []int{2}, changes)
+	}
+
+	clickLayout(layout, 10, 30)
+
+	if clicks != [3]int{1, 0, 1} {
+		t.Errorf("page clicks after switching: wanted %v: got %v", [3]int{1, 0, 1}, clicks)
+	}
+
+	if w := layout.widgetAt(10, 30); w != container.GetTab(2).entries[0].widget {
+		t.Error("widget under the cursor isn't on the active page")
+	}
+
+	surface := &testSurface{}
+	if err := layout.render(surface); err != nil {
+		t.Fatal(err)
+	}
+
+	// clicking the active tab again doesn't change it
+	clickLayout(layout, 130, 5)
+
+	if len(changes) != 1 {
+		t.Errorf("tab change callbacks after clicking the active tab: wanted %d: got %d", 1, len(changes))
+	}
+}
@@ -0,0 +1,225 @@
+package d2gui
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+const (
+	// caretBlinkInterval is the number of seconds the caret is shown, then hidden, while blinking
+	caretBlinkInterval = 0.5
+
+	textInputPadding = 2
+	textInputMask    = '*'
+)
+
+// TextInput is a single line text field, which is edited while it has keyboard focus
+type TextInput struct {
+	widgetBase
+
+	font      d2interface.Font
+	width     int
+	text      []rune
+	caret     int
+	maxLength int
+	masked    bool
+	onSubmit  func(text string)
+
+	blinkTime    float64
+	caretVisible bool
+}
+
+func createTextInput(width int, fontStyle FontStyle) (*TextInput, error) {
+	font, err := loadFont(fontStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTextInput(font, width), nil
+}
+
+func newTextInput(font d2interface.Font, width int) *TextInput {
+	input := &TextInput{
+		font:         font,
+		width:        width,
+		caretVisible: true,
+	}
+
+	input.self = input
+	input.SetVisible(true)
+	input.SetFocusable(true)
+
+	return input
+}
+
+// GetText returns the text entered
+func (t *TextInput) GetText() string {
+	return string(t.text)
+}
+
+// SetText sets the text, truncated to the maximum length, and moves the caret to the end
+func (t *TextInput) SetText(text string) {
+	t.text = []rune(text)
+
+	if t.maxLength > 0 && len(t.text) > t.maxLength {
+		t.text = t.text[:t.maxLength]
+	}
+
+	t.caret = len(t.text)
+}
+
+// SetMaxLength sets the maximum number of characters, 0 means no limit. Longer text is truncated.
+func (t *TextInput) SetMaxLength(maxLength int) {
+	t.maxLength = maxLength
+	t.SetText(string(t.text))
+}
+
+// SetMasked sets whether the text is shown as mask characters, for passwords
+func (t *TextInput) SetMasked(masked bool) {
+	t.masked = masked
+}
+
+// SetOnSubmit sets the callback called with the text when Enter is pressed
+func (t *TextInput) SetOnSubmit(onSubmit func(text string)) {
+	t.onSubmit = onSubmit
+}
+
+// GetCaret returns the index of the character the caret is before
+func (t *TextInput) GetCaret() int {
+	return t.caret
+}
+
+// SetCaret moves the caret before the character at the index, clamped to the text
+func (t *TextInput) SetCaret(caret int) {
+	switch {
+	case caret < 0:
+		caret = 0
+	case caret > len(t.text):
+		caret = len(t.text)
+	}
+
+	t.caret = caret
+	t.resetBlink()
+}
+
+func (t *TextInput) insert(chars []rune) {
+	for _, c := range chars {
+		if t.maxLength > 0 && len(t.text) >= t.maxLength {
+			break
+		}
+
+		t.text = append(t.text, 0)
+		copy(t.text[t.caret+1:], t.text[t.caret:])
+		t.text[t.caret] = c
+		t.caret++
+	}
+
+	t.resetBlink()
+}
+
+// deleteBackward deletes the character before the caret
+func (t *TextInput) deleteBackward() {
+	if t.caret == 0 {
+		return
+	}
+
+	t.text = append(t.text[:t.caret-1], t.text[t.caret:]...)
+	t.caret--
+	t.resetBlink()
+}
+
+// deleteForward deletes the character after the caret
+func (t *TextInput) deleteForward() {
+	if t.caret == len(t.text) {
+		return
+	}
+
+	t.text = append(t.text[:t.caret], t.text[t.caret+1:]...)
+	t.resetBlink()
+}
+
+func (t *TextInput) resetBlink() {
+	t.blinkTime = 0
+	t.caretVisible = true
+}
+
+// displayText returns the text as shown, masked if required
+func (t *TextInput) displayText(length int) string {
+	if t.masked {
+		return strings.Repeat(string(textInputMask), length)
+	}
+
+	return string(t.text[:length])
+}
+
+func (t *TextInput) onKeyDown(event d2interface.KeyEvent) bool {
+	switch event.Key() {
+	case d2enum.KeyLeft:
+		t.SetCaret(t.caret - 1)
+	case d2enum.KeyRight:
+		t.SetCaret(t.caret + 1)
+	case d2enum.KeyHome:
+		t.SetCaret(0)
+	case d2enum.KeyEnd:
+		t.SetCaret(len(t.text))
+	case d2enum.KeyBackspace:
+		t.deleteBackward()
+	case d2enum.KeyDelete:
+		t.deleteForward()
+	case d2enum.KeyEnter, d2enum.KeyKPEnter:
+		if t.onSubmit != nil {
+			t.onSubmit(t.GetText())
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (t *TextInput) onKeyChars(event d2interface.KeyCharsEvent) bool {
+	t.insert(event.Chars())
+	return true
+}
+
+func (t *TextInput) advance(elapsed float64) error {
+	t.blinkTime += elapsed
+
+	for t.blinkTime >= caretBlinkInterval {
+		t.blinkTime -= caretBlinkInterval
+		t.caretVisible = !t.caretVisible
+	}
+
+	return nil
+}
+
+func (t *TextInput) render(target d2interface.Surface) error {
+	_, height := t.getSize()
+	target.DrawRect(t.width, height, color.RGBA{A: 0xc0})
+
+	target.PushTranslation(textInputPadding, textInputPadding)
+	defer target.Pop()
+
+	if err := t.font.RenderText(t.displayText(len(t.text)), target); err != nil {
+		return err
+	}
+
+	if t.focused && t.caretVisible {
+		caretX, _ := t.font.GetTextMetrics(t.displayText(t.caret))
+		_, lineHeight := t.font.GetTextMetrics("A")
+
+		target.PushTranslation(caretX, 0)
+		target.DrawLine(0, lineHeight, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+		target.Pop()
+	}
+
+	return nil
+}
+
+func (t *TextInput) getSize() (int, int) {
+	_, lineHeight := t.font.GetTextMetrics("A")
+	return t.width, lineHeight + textInputPadding*2
+}
@@ -0,0 +1,143 @@
+package d2gui
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+type testKeyCharsEvent struct {
+	testMouseMoveEvent
+	chars []rune
+}
+
+func (e *testKeyCharsEvent) Chars() []rune {
+	return e.chars
+}
+
+func typeText(input *TextInput, text string) {
+	input.onKeyChars(&testKeyCharsEvent{chars: []rune(text)})
+}
+
+func TestTextInputInsert(t *testing.T) {
+	input := newTextInput(&testFont{}, 100)
+
+	typeText(input, "ac")
+	input.onKeyDown(&testKeyEvent{key: d2enum.KeyLeft})
+	typeText(input, "b")
+
+	if got := input.GetText(); got != "abc" {
+		t.Errorf("text: wanted %q: got %q", "abc", got)
+	}
+
+	if got := input.GetCaret(); got != 2 {
+		t.Errorf("caret: wanted %d: got %d", 2, got)
+	}
+
+	input.onKeyDown(&testKeyEvent{key: d2enum.KeyDelete})
+
+	if got := input.GetText(); got != "ab" {
+		t.Errorf("text after delete: wanted %q: got %q", "ab", got)
+	}
+}
+
+func TestTextInputBackspaceAtStart(t *testing.T) {
+	input := newTextInput(&testFont{}, 100)
+
+	typeText(input, "abc")
+	input.onKeyDown(&testKeyEvent{key: d2enum.KeyHome})
+	input.onKeyDown(&testKeyEvent{key: d2enum.KeyBackspace})
+
+	if got := input.GetText(); got != "abc" {
+		t.Errorf("text: wanted %q: got %q", "abc", got)
+	}
+
+	if got := input.GetCaret(); got != 0 {
+		t.Errorf("caret: wanted %d: got %d", 0, got)
+	}
+
+	input.onKeyDown(&testKeyEvent{key: d2enum.KeyEnd})
+	input.onKeyDown(&testKeyEvent{key: d2enum.KeyBackspace})
+
+	if got := input.GetText(); got != "ab" {
+		t.Errorf("text after backspace at the end: wanted %q: got %q", "ab", got)
+	}
+}
+
+func TestTextInputMaxLength(t *testing.T) {
+	input := newTextInput(&testFont{}, 100)
+	input.SetMaxLength(3)
+
+	typeText(input, "abcd")
+
+	if got := input.GetText(); got != "abc" {
+		t.Errorf("text: wanted %q: got %q", "abc", got)
+	}
+
+	input.SetMaxLength(2)
+
+	if got := input.GetText(); got != "ab" {
+		t.Errorf("text after lowering the max length: wanted %q: got %q", "ab", got)
+	}
+}
+
+func TestTextInputSubmit(t *testing.T) {
+	input := newTextInput(&testFont{}, 100)
+	input.SetMasked(true)
+
+	var submitted []string
+
+	input.SetOnSubmit(func(text string) {
+		submitted = append(submitted, text)
+	})
+
+	typeText(input, "secret")
+
+	if got := input.displayText(len(input.text)); got != "******" {
+		t.Errorf("masked text: wanted %q: got %q", "******", got)
+	}
+
+	input.onKeyDown(&testKeyEvent{key: d2enum.KeyEnter})
+
+	if len(submitted) != 1 || submitted[0] != "secret" {
+		t.Errorf("submitted: wanted %v: got %v", []string{"secret"}, submitted)
+	}
+}
+
+func TestTextInputFocus(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	input := newTextInput(&testFont{}, 100)
+	layout.addEntry(input)
+
+	m := &manager{}
+	m.SetLayout(layout)
+
+	m.OnKeyChars(&testKeyCharsEvent{chars: []rune("a")})
+
+	if got := input.GetText(); got != "" {
+		t.Errorf("text without focus: wanted %q: got %q", "", got)
+	}
+
+	m.setFocus(input)
+	m.OnKeyChars(&testKeyCharsEvent{chars: []rune("a")})
+
+	if got := input.GetText(); got != "a" {
+		t.Errorf("text with focus: wanted %q: got %q", "a", got)
+	}
+}
+
+func TestTextInputCaretBlink(t *testing.T) {
+	input := newTextInput(&testFont{}, 100)
+
+	_ = input.advance(caretBlinkInterval)
+
+	if input.caretVisible {
+		t.Error("caret visible after one blink interval")
+	}
+
+	_ = input.advance(caretBlinkInterval)
+
+	if !input.caretVisible {
+		t.Error("caret hidden after two blink intervals")
+	}
+}
@@ -0,0 +1,143 @@
+package d2gui
+
+import (
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+const (
+	defaultTooltipDelay = 0.5
+
+	tooltipPadding      = 4
+	tooltipCursorOffset = 16
+)
+
+// Tooltip is a bordered text box shown near the cursor after the cursor has
+// hovered over a target widget for a while.
+type Tooltip struct {
+	widgetBase
+
+	font  d2interface.Font
+	text  string
+	delay float64
+
+	hovering  bool
+	hoverTime float64
+	shown     bool
+	cursorX   int
+	cursorY   int
+}
+
+func createTooltip(target widget, text string, fontStyle FontStyle) (*Tooltip, error) {
+	font, err := loadFont(fontStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTooltip(target, text, font), nil
+}
+
+func newTooltip(target widget, text string, font d2interface.Font) *Tooltip {
+	tooltip := &Tooltip{
+		font:  font,
+		text:  text,
+		delay: defaultTooltipDelay,
+	}
+
+	tooltip.self = tooltip
+	tooltip.SetVisible(true)
+	target.setTooltip(tooltip)
+
+	return tooltip
+}
+
+// SetTooltipText sets the text shown by the tooltip
+func (t *Tooltip) SetTooltipText(text string) {
+	t.text = text
+}
+
+// GetTooltipText returns the text shown by the tooltip
+func (t *Tooltip) GetTooltipText() string {
+	return t.text
+}
+
+// SetTooltipDelay sets how many seconds the cursor hovers over the target
+// before the tooltip is shown
+func (t *Tooltip) SetTooltipDelay(delay float64) {
+	t.delay = delay
+}
+
+// IsShown returns true if the tooltip is currently shown
+func (t *Tooltip) IsShown() bool {
+	return t.shown
+}
+
+func (t *Tooltip) targetEnter(event d2interface.MouseMoveEvent) {
+	t.hovering = true
+	t.hoverTime = 0
+	t.cursorX, t.cursorY = event.X(), event.Y()
+}
+
+func (t *Tooltip) targetOver(event d2interface.MouseMoveEvent) {
+	t.cursorX, t.cursorY = event.X(), event.Y()
+}
+
+func (t *Tooltip) targetLeave() {
+	t.hovering = false
+	t.shown = false
+}
+
+func (t *Tooltip) advance(elapsed float64) error {
+	if !t.hovering || t.shown {
+		return nil
+	}
+
+	t.hoverTime += elapsed
+	t.shown = t.hoverTime >= t.delay
+
+	return nil
+}
+
+// position returns the screen position of the tooltip box, clamped so the
+// box stays within a screen of the given size
+func (t *Tooltip) position(screenWidth, screenHeight int) (x, y int) {
+	width, height := t.boxSize()
+
+	x = t.cursorX + tooltipCursorOffset
+	y = t.cursorY - tooltipCursorOffset - height
+
+	x = d2common.MaxInt(0, d2common.MinInt(x, screenWidth-width))
+	y = d2common.MaxInt(0, d2common.MinInt(y, screenHeight-height))
+
+	return x, y
+}
+
+func (t *Tooltip) boxSize() (width, height int) {
+	textWidth, textHeight := t.font.GetTextMetrics(t.text)
+	return textWidth + tooltipPadding*2, textHeight + tooltipPadding*2
+}
+
+func (t *Tooltip) render(target d2interface.Surface) error {
+	if !t.shown {
+		return nil
+	}
+
+	screenWidth, screenHeight := target.GetSize()
+	x, y := t.position(screenWidth, screenHeight)
+	width, height := t.boxSize()
+
+	// The target is translated to this widget's screen position
+	target.PushTranslation(x-t.Sx, y-t.Sy)
+	defer target.Pop()
+
+	target.DrawRect(width, height, color.RGBA{A: 0xc0})
+
+	renderOutline(width, height, color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}, target)
+
+	target.PushTranslation(tooltipPadding, tooltipPadding)
+	defer target.Pop()
+
+	return t.font.RenderText(t.text, target)
+}
@@ -0,0 +1,107 @@
+package d2gui
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+type testFont struct{}
+
+func (f *testFont) SetColor(c color.Color) {}
+
+// GetTextMetrics returns 10 by 10 pixels per character, empty text has no height like the real fonts
+func (f *testFont) GetTextMetrics(text string) (width, height int) {
+	if text == "" {
+		return 0, 0
+	}
+
+	return len(text) * 10, 10
+}
+
+func (f *testFont) RenderText(text string, target d2interface.Surface) error {
+	return nil
+}
+
+type testMouseMoveEvent struct {
+	x, y int
+}
+
+func (e *testMouseMoveEvent) KeyMod() d2enum.KeyMod {
+	return 0
+}
+
+func (e *testMouseMoveEvent) ButtonMod() d2enum.MouseButtonMod {
+	return 0
+}
+
+func (e *testMouseMoveEvent) X() int {
+	return e.x
+}
+
+func (e *testMouseMoveEvent) Y() int {
+	return e.y
+}
+
+func TestTooltipHover(t *testing.T) {
+	target := createSpacerStatic(50, 50)
+	tooltip := newTooltip(target, "tip", &testFont{})
+	tooltip.SetTooltipDelay(1)
+
+	target.onMouseEnter(&testMouseMoveEvent{x: 10, y: 10})
+
+	_ = tooltip.advance(0.5)
+
+	if tooltip.IsShown() {
+		t.Error("tooltip shown before the delay elapsed")
+	}
+
+	_ = tooltip.advance(0.5)
+
+	if !tooltip.IsShown() {
+		t.Error("tooltip not shown after the delay elapsed")
+	}
+
+	target.onMouseLeave(&testMouseMoveEvent{x: 60, y: 60})
+
+	if tooltip.IsShown() {
+		t.Error("tooltip shown after the cursor left the target")
+	}
+
+	_ = tooltip.advance(1)
+
+	if tooltip.IsShown() {
+		t.Error("tooltip shown again without the cursor entering the target")
+	}
+}
+
+func TestTooltipClamp(t *testing.T) {
+	tooltip := newTooltip(createSpacerStatic(50, 50), "tip", &testFont{})
+	width, height := tooltip.boxSize()
+
+	tooltip.targetEnter(&testMouseMoveEvent{x: 795, y: 5})
+
+	x, y := tooltip.position(800, 600)
+
+	if x != 800-width {
+		t.Errorf("tooltip x: wanted %d: got %d", 800-width, x)
+	}
+
+	if y != 0 {
+		t.Errorf("tooltip y: wanted %d: got %d", 0, y)
+	}
+
+	tooltip.targetOver(&testMouseMoveEvent{x: 100, y: 300})
+
+	x, y = tooltip.position(800, 600)
+
+	if x != 100+tooltipCursorOffset {
+		t.Errorf("tooltip x: wanted %d: got %d", 100+tooltipCursorOffset, x)
+	}
+
+	if y != 300-tooltipCursorOffset-height {
+		t.Errorf("tooltip y: wanted %d: got %d", 300-tooltipCursorOffset-height, y)
+	}
+}
@@ -1,12 +1,23 @@
 package d2gui
 
 import (
+	"image"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 )
 
 type MouseHandler func(d2interface.MouseEvent)
 type MouseMoveHandler func(d2interface.MouseMoveEvent)
 
+// DropHandler is called when a dragged widget passes over or is dropped on a widget. The source is the dragged
+// widget.
+type DropHandler func(source interface{}, event d2interface.HandlerEvent)
+
+// MouseWheelHandler is called when the mouse wheel is turned over a widget
+type MouseWheelHandler func(d2interface.MouseWheelEvent)
+
 type widget interface {
 	render(target d2interface.Surface) error
 	advance(elapsed float64) error
@@ -18,6 +29,14 @@ type widget interface {
 	onMouseButtonDown(event d2interface.MouseEvent) bool
 	onMouseButtonUp(event d2interface.MouseEvent) bool
 	onMouseButtonClick(event d2interface.MouseEvent) bool
+	onMouseButtonDoubleClick(event d2interface.MouseEvent) bool
+	onMouseWheel(event d2interface.MouseWheelEvent) bool
+	onDragStart(event d2interface.MouseMoveEvent) bool
+	onDragOver(source widget, event d2interface.MouseMoveEvent) bool
+	onDrop(source widget, event d2interface.MouseEvent) bool
+	onKeyDown(event d2interface.KeyEvent) bool
+	onKeyChars(event d2interface.KeyCharsEvent) bool
+	onClickOutside(event d2interface.MouseEvent)
 
 	getPosition() (int, int)
 	setOffset(x, y int)
@@ -26,24 +45,70 @@ type widget interface {
 	getSize() (int, int)
 	getLayer() int
 	isVisible() bool
+	isEnabled() bool
 	isExpanding() bool
+	isDraggable() bool
+	isFocusable() bool
+	isFocused() bool
+	setFocused(focused bool)
+	getTabIndex() int
+	setTooltip(tooltip *Tooltip)
+	advanceFade(elapsed float64)
+	setInheritedOpacity(opacity float64)
+	renderOpacity() float64
+	getBlendMode() d2enum.BlendMode
+	setInheritedClip(clip *d2common.Rectangle)
+	localClip() (image.Rectangle, bool)
+	screenClip() *d2common.Rectangle
+	reanchor(screenWidth, screenHeight int)
+	anchoredScreenPos() (x, y int, ok bool)
+	popupContains(x, y int) bool
 }
 
 type widgetBase struct {
 	x         int
 	y         int
-	Sx		  int
+	Sx        int
 	Sy        int
 	layer     int
 	visible   bool
+	disabled  bool
 	expanding bool
+	draggable bool
+	focusable bool
+	focused   bool
+	tabIndex  int
+
+	// transparency is stored rather than opacity, so the zero value is opaque
+	transparency          float64
+	inheritedTransparency float64
+	fade                  *opacityFade
+
+	blendMode d2enum.BlendMode
+
+	// clipBounds is relative to the widget, inheritedClip is the parents' clip in screen coordinates
+	clipBounds    *d2common.Rectangle
+	inheritedClip *d2common.Rectangle
+
+	anchor *widgetAnchor
 
 	offsetX int
 	offsetY int
 
-	mouseEnterHandler MouseMoveHandler
-	mouseLeaveHandler MouseMoveHandler
-	mouseClickHandler MouseHandler
+	mouseEnterHandler  MouseMoveHandler
+	mouseLeaveHandler  MouseMoveHandler
+	mouseClickHandler  MouseHandler
+	rightClickHandler  MouseHandler
+	doubleClickHandler MouseHandler
+	mouseWheelHandler  MouseWheelHandler
+	dragStartHandler   MouseMoveHandler
+	dragOverHandler    DropHandler
+	dropHandler        DropHandler
+
+	tooltip *Tooltip
+
+	// self is the widget embedding this base, so the base can reach its overridden methods
+	self widget
 }
 
 func (w *widgetBase) SetPosition(x, y int) {
@@ -82,10 +147,56 @@ func (w *widgetBase) SetVisible(visible bool) {
 	w.visible = visible
 }
 
+// SetEnabled sets whether the widget responds to the mouse and keyboard. Disabled widgets are drawn dimmed.
+func (w *widgetBase) SetEnabled(enabled bool) {
+	w.disabled = !enabled
+}
+
+// IsEnabled returns true if the widget responds to the mouse and keyboard
+func (w *widgetBase) IsEnabled() bool {
+	return !w.disabled
+}
+
 func (w *widgetBase) SetExpanding(expanding bool) {
 	w.expanding = expanding
 }
 
+// SetDraggable sets whether the widget can be dragged onto other widgets
+func (w *widgetBase) SetDraggable(draggable bool) {
+	w.draggable = draggable
+}
+
+// SetFocusable sets whether the widget can receive keyboard focus
+func (w *widgetBase) SetFocusable(focusable bool) {
+	w.focusable = focusable
+}
+
+// SetTabIndex sets the position of the widget in the tab order. Widgets with the same tab index are ordered as they
+// appear in the layout.
+func (w *widgetBase) SetTabIndex(tabIndex int) {
+	w.tabIndex = tabIndex
+}
+
+// IsFocused returns true if the widget has keyboard focus
+func (w *widgetBase) IsFocused() bool {
+	return w.focused
+}
+
+// Focus gives the widget keyboard focus, taking it from the previously focused widget
+func (w *widgetBase) Focus() {
+	verifyWasInit()
+	singleton.setFocus(w.self)
+}
+
+// Blur removes keyboard focus from the widget
+func (w *widgetBase) Blur() {
+	verifyWasInit()
+
+	if singleton.focused == w.self {
+		singleton.setFocus(nil)
+	}
+}
+
 func (w *widgetBase) SetMouseEnterHandler(handler MouseMoveHandler) {
 	w.mouseEnterHandler = handler
 }
@@ -98,6 +209,56 @@ func (w *widgetBase) SetMouseClickHandler(handler MouseHandler) {
 	w.mouseClickHandler = handler
 }
 
+// SetRightMouseClickHandler sets the handler called when the widget is clicked with the right mouse button.
+// Without it, right clicks call the click handler.
+func (w *widgetBase) SetRightMouseClickHandler(handler MouseHandler) {
+	w.rightClickHandler = handler
+}
+
+// SetDoubleClickHandler sets the handler called when the widget is double-clicked. The second click of a
+// double-click does not call the click handler.
+func (w *widgetBase) SetDoubleClickHandler(handler MouseHandler) {
+	w.doubleClickHandler = handler
+}
+
+// SetMouseWheelHandler sets the handler called when the mouse wheel is turned over the widget
+func (w *widgetBase) SetMouseWheelHandler(handler MouseWheelHandler) {
+	w.mouseWheelHandler = handler
+}
+
+// SetDragStartHandler sets the handler called when the widget starts being dragged
+func (w *widgetBase) SetDragStartHandler(handler MouseMoveHandler) {
+	w.dragStartHandler = handler
+}
+
+// SetDragOverHandler sets the handler called when a dragged widget moves over the widget
+func (w *widgetBase) SetDragOverHandler(handler DropHandler) {
+	w.dragOverHandler = handler
+}
+
+// SetDropHandler sets the handler called when a dragged widget is dropped on the widget
+func (w *widgetBase) SetDropHandler(handler DropHandler) {
+	w.dropHandler = handler
+}
+
+func (w *widgetBase) setTooltip(tooltip *Tooltip) {
+	w.tooltip = tooltip
+}
+
+// SetBlendMode sets how the widget is combined with what is drawn beneath it, for example additive for glows
+func (w *widgetBase) SetBlendMode(mode d2enum.BlendMode) {
+	w.blendMode = mode
+}
+
+// GetBlendMode returns how the widget is combined with what is drawn beneath it
+func (w *widgetBase) GetBlendMode() d2enum.BlendMode {
+	return w.blendMode
+}
+
+func (w *widgetBase) getBlendMode() d2enum.BlendMode {
+	return w.blendMode
+}
+
 func (w *widgetBase) getPosition() (int, int) {
 	return w.x, w.y
 }
@@ -114,10 +275,34 @@ func (w *widgetBase) isVisible() bool {
 	return w.visible
 }
 
+func (w *widgetBase) isEnabled() bool {
+	return !w.disabled
+}
+
 func (w *widgetBase) isExpanding() bool {
 	return w.expanding
 }
 
+func (w *widgetBase) isDraggable() bool {
+	return w.draggable
+}
+
+func (w *widgetBase) isFocusable() bool {
+	return w.focusable
+}
+
+func (w *widgetBase) isFocused() bool {
+	return w.focused
+}
+
+func (w *widgetBase) setFocused(focused bool) {
+	w.focused = focused
+}
+
+func (w *widgetBase) getTabIndex() int {
+	return w.tabIndex
+}
+
 func (w *widgetBase) render(target d2interface.Surface) error {
 	return nil
 }
@@ -131,6 +316,10 @@ func (w *widgetBase) onMouseEnter(event d2interface.MouseMoveEvent) bool {
 		w.mouseEnterHandler(event)
 	}
 
+	if w.tooltip != nil {
+		w.tooltip.targetEnter(event)
+	}
+
 	return false
 }
 
@@ -139,23 +328,54 @@ func (w *widgetBase) onMouseLeave(event d2interface.MouseMoveEvent) bool {
 		w.mouseLeaveHandler(event)
 	}
 
+	if w.tooltip != nil {
+		w.tooltip.targetLeave()
+	}
+
 	return false
 }
 
 func (w *widgetBase) onMouseButtonClick(event d2interface.MouseEvent) bool {
-	if w.mouseClickHandler != nil {
+	if event.Button() == d2enum.MouseButtonRight && w.rightClickHandler != nil {
+		w.rightClickHandler(event)
+	} else if w.mouseClickHandler != nil {
 		w.mouseClickHandler(event)
 	}
 
 	return false
 }
 
+func (w *widgetBase) onMouseButtonDoubleClick(event d2interface.MouseEvent) bool {
+	if w.doubleClickHandler != nil {
+		w.doubleClickHandler(event)
+	}
+
+	return false
+}
+
 func (w *widgetBase) onMouseMove(event d2interface.MouseMoveEvent) bool {
 	return false
 }
 
+// onMouseOver returns true if the event is within the widget's screen rectangle
 func (w *widgetBase) onMouseOver(event d2interface.MouseMoveEvent) bool {
-	return false
+	if w.tooltip != nil {
+		w.tooltip.targetOver(event)
+	}
+
+	return w.contains(event.X(), event.Y())
+}
+
+// contains returns true if the screen position is within the widget's screen rectangle
+func (w *widgetBase) contains(x, y int) bool {
+	width, height := w.getSize()
+	if w.self != nil {
+		width, height = w.self.getSize()
+	}
+
+	rect := d2common.Rectangle{Left: w.Sx, Top: w.Sy, Width: width, Height: height}
+
+	return rect.IsInRect(x, y)
 }
 
 func (w *widgetBase) onMouseButtonDown(event d2interface.MouseEvent) bool {
@@ -165,3 +385,51 @@ func (w *widgetBase) onMouseButtonDown(event d2interface.MouseEvent) bool {
 func (w *widgetBase) onMouseButtonUp(event d2interface.MouseEvent) bool {
 	return false
 }
+
+func (w *widgetBase) onMouseWheel(event d2interface.MouseWheelEvent) bool {
+	if w.mouseWheelHandler != nil {
+		w.mouseWheelHandler(event)
+	}
+
+	return false
+}
+
+func (w *widgetBase) onDragStart(event d2interface.MouseMoveEvent) bool {
+	if w.dragStartHandler != nil {
+		w.dragStartHandler(event)
+	}
+
+	return false
+}
+
+func (w *widgetBase) onDragOver(source widget, event d2interface.MouseMoveEvent) bool {
+	if w.dragOverHandler != nil {
+		w.dragOverHandler(source, event)
+	}
+
+	return false
+}
+
+func (w *widgetBase) onDrop(source widget, event d2interface.MouseEvent) bool {
+	if w.dropHandler != nil {
+		w.dropHandler(source, event)
+	}
+
+	return false
+}
+
+func (w *widgetBase) onKeyDown(event d2interface.KeyEvent) bool {
+	return false
+}
+
+func (w *widgetBase) onKeyChars(event d2interface.KeyCharsEvent) bool {
+	return false
+}
+
+// onClickOutside is called when a mouse button is pressed outside the widget
+func (w *widgetBase) onClickOutside(event d2interface.MouseEvent) {}
+
+// popupContains returns true if the screen position is within a popup the widget draws outside its bounds
+func (w *widgetBase) popupContains(x, y int) bool {
+	return false
+}
@@ -0,0 +1,297 @@
+package d2gui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+func TestWidgetMouseOver(t *testing.T) {
+	spacer := createSpacerStatic(20, 10)
+	spacer.SetScreenPos(100, 50)
+
+	tests := []struct {
+		x, y int
+		hit  bool
+	}{
+		{100, 50, true},
+		{110, 55, true},
+		{119, 59, true},
+		{120, 55, false},
+		{110, 60, false},
+		{99, 55, false},
+		{110, 49, false},
+	}
+
+	for _, test := range tests {
+		hit := spacer.onMouseOver(&testMouseMoveEvent{x: test.x, y: test.y})
+		if hit != test.hit {
+			t.Errorf("mouse over at (%d, %d): wanted %v: got %v", test.x, test.y, test.hit, hit)
+		}
+	}
+}
+
+type testMouseWheelEvent struct {
+	testMouseMoveEvent
+	deltaY float64
+}
+
+func (e *testMouseWheelEvent) DeltaX() float64 {
+	return 0
+}
+
+func (e *testMouseWheelEvent) DeltaY() float64 {
+	return e.deltaY
+}
+
+func TestWidgetMouseWheel(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	top := layout.AddSpacerStatic(20, 10)
+	bottom := layout.AddSpacerStatic(20, 10)
+
+	m := &manager{}
+	m.SetLayout(layout)
+
+	var topEvents, bottomEvents int
+
+	top.SetMouseWheelHandler(func(event d2interface.MouseWheelEvent) {
+		topEvents++
+	})
+	bottom.SetMouseWheelHandler(func(event d2interface.MouseWheelEvent) {
+		bottomEvents++
+
+		if event.DeltaY() != 1 {
+			t.Errorf("wheel delta: wanted %v: got %v", 1, event.DeltaY())
+		}
+	})
+
+	m.OnMouseWheel(&testMouseWheelEvent{testMouseMoveEvent{x: 5, y: 15}, 1})
+
+	if topEvents != 0 || bottomEvents != 1 {
+		t.Errorf("wheel events (top, bottom): wanted (0, 1): got (%d, %d)", topEvents, bottomEvents)
+	}
+
+	m.OnMouseWheel(&testMouseWheelEvent{testMouseMoveEvent{x: 5, y: 30}, 1})
+
+	if topEvents != 0 || bottomEvents != 1 {
+		t.Errorf("wheel events outside widgets (top, bottom): wanted (0, 1): got (%d, %d)", topEvents, bottomEvents)
+	}
+}
+
+type testMouseEvent struct {
+	testMouseMoveEvent
+	button d2enum.MouseButton
+}
+
+func (e *testMouseEvent) Button() d2enum.MouseButton {
+	return e.button
+}
+
+func TestWidgetDoubleClick(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	spacer := layout.AddSpacerStatic(20, 10)
+
+	m := &manager{doubleClickWindow: defaultDoubleClickWindow, doubleClickRadius: defaultDoubleClickRadius}
+	m.SetLayout(layout)
+
+	var clicks, doubleClicks int
+
+	spacer.SetMouseClickHandler(func(event d2interface.MouseEvent) {
+		clicks++
+	})
+	spacer.SetDoubleClickHandler(func(event d2interface.MouseEvent) {
+		doubleClicks++
+	})
+
+	click := func(x, y int) {
+		event := &testMouseEvent{testMouseMoveEvent{x: x, y: y}, d2enum.MouseButtonLeft}
+		m.OnMouseButtonDown(event)
+		m.OnMouseButtonUp(event)
+	}
+
+	click(5, 5)
+	_ = m.advance(0.1)
+	click(6, 5)
+
+	if clicks != 1 || doubleClicks != 1 {
+		t.Errorf("rapid clicks (clicks, double-clicks): wanted (1, 1): got (%d, %d)", clicks, doubleClicks)
+	}
+
+	_ = m.advance(1)
+	click(5, 5)
+	_ = m.advance(0.5)
+	click(5, 5)
+
+	if clicks != 3 || doubleClicks != 1 {
+		t.Errorf("slow clicks (clicks, double-clicks): wanted (3, 1): got (%d, %d)", clicks, doubleClicks)
+	}
+
+	_ = m.advance(1)
+	click(5, 5)
+	_ = m.advance(0.1)
+	click(15, 5)
+
+	if clicks != 5 || doubleClicks != 1 {
+		t.Errorf("distant clicks (clicks, double-clicks): wanted (5, 1): got (%d, %d)", clicks, doubleClicks)
+	}
+}
+
+func TestWidgetRightMouseClick(t *testing.T) {
+	spacer := createSpacerStatic(20, 10)
+
+	var clicks, rightClicks int
+
+	spacer.SetMouseClickHandler(func(event d2interface.MouseEvent) {
+		clicks++
+	})
+
+	rightClick := &testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonRight}
+	leftClick := &testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft}
+
+	spacer.onMouseButtonClick(rightClick)
+
+	if clicks != 1 {
+		t.Errorf("right click without a right click handler: wanted %d clicks: got %d", 1, clicks)
+	}
+
+	spacer.SetRightMouseClickHandler(func(event d2interface.MouseEvent) {
+		rightClicks++
+	})
+
+	spacer.onMouseButtonClick(leftClick)
+	spacer.onMouseButtonClick(rightClick)
+
+	if clicks != 2 || rightClicks != 1 {
+		t.Errorf("clicks (left, right): wanted (2, 1): got (%d, %d)", clicks, rightClicks)
+	}
+}
+
+func TestWidgetDragAndDrop(t *testing.T) {
+	layout := createLayout(nil, PositionTypeHorizontal)
+	source := layout.AddSpacerStatic(20, 10)
+	target := layout.AddSpacerStatic(20, 10)
+
+	m := &manager{doubleClickWindow: defaultDoubleClickWindow, doubleClickRadius: defaultDoubleClickRadius}
+	m.SetLayout(layout)
+
+	var events []string
+
+	source.SetDraggable(true)
+	source.SetDragStartHandler(func(event d2interface.MouseMoveEvent) {
+		events = append(events, "start")
+	})
+	source.SetMouseClickHandler(func(event d2interface.MouseEvent) {
+		events = append(events, "click")
+	})
+	target.SetDragOverHandler(func(dragged interface{}, event d2interface.HandlerEvent) {
+		if dragged != source {
+			t.Error("drag over: unexpected source")
+		}
+
+		events = append(events, "over")
+	})
+	target.SetDropHandler(func(dragged interface{}, event d2interface.HandlerEvent) {
+		if dragged != source {
+			t.Error("drop: unexpected source")
+		}
+
+		events = append(events, "drop")
+	})
+
+	m.OnMouseButtonDown(&testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft})
+	m.OnMouseMove(&testMouseMoveEvent{x: 7, y: 5})
+
+	if len(events) != 0 {
+		t.Errorf("events before the drag threshold: wanted none: got %v", events)
+	}
+
+	m.OnMouseMove(&testMouseMoveEvent{x: 25, y: 5})
+	m.OnMouseButtonUp(&testMouseEvent{testMouseMoveEvent{x: 25, y: 5}, d2enum.MouseButtonLeft})
+
+	wanted := []string{"start", "over", "drop"}
+	if len(events) != len(wanted) {
+		t.Fatalf("drag events: wanted %v: got %v", wanted, events)
+	}
+
+	for i := range wanted {
+		if events[i] != wanted[i] {
+			t.Fatalf("drag events: wanted %v: got %v", wanted, events)
+		}
+	}
+
+	if m.drag != nil {
+		t.Error("drag not finished after the button was released")
+	}
+}
+
+func TestWidgetDisabled(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	spacer := layout.AddSpacerStatic(20, 10)
+
+	m := &manager{doubleClickWindow: defaultDoubleClickWindow, doubleClickRadius: defaultDoubleClickRadius}
+	m.SetLayout(layout)
+
+	var clicks, enters int
+
+	spacer.SetMouseClickHandler(func(event d2interface.MouseEvent) {
+		clicks++
+	})
+	spacer.SetMouseEnterHandler(func(event d2interface.MouseMoveEvent) {
+		enters++
+	})
+
+	click := func() {
+		event := &testMouseEvent{testMouseMoveEvent{x: 5, y: 5}, d2enum.MouseButtonLeft}
+		m.OnMouseButtonDown(event)
+		m.OnMouseButtonUp(event)
+		_ = m.advance(1)
+	}
+
+	spacer.SetEnabled(false)
+	m.OnMouseMove(&testMouseMoveEvent{x: 5, y: 5})
+	click()
+
+	if clicks != 0 || enters != 0 {
+		t.Errorf("disabled widget events (clicks, enters): wanted (0, 0): got (%d, %d)", clicks, enters)
+	}
+
+	if !spacer.isVisible() {
+		t.Error("disabling a widget hid it")
+	}
+
+	spacer.SetEnabled(true)
+	m.OnMouseMove(&testMouseMoveEvent{x: 6, y: 5})
+	click()
+
+	if clicks != 1 || enters != 1 {
+		t.Errorf("re-enabled widget events (clicks, enters): wanted (1, 1): got (%d, %d)", clicks, enters)
+	}
+}
+
+func TestWidgetBlendMode(t *testing.T) {
+	layout := createLayout(nil, PositionTypeVertical)
+	normal := layout.AddProgressBar(FillDirectionHorizontal, 10, 10)
+	glow := layout.AddProgressBar(FillDirectionHorizontal, 10, 10)
+
+	normal.SetProgress(0)
+	glow.SetProgress(0)
+	glow.SetBlendMode(d2enum.BlendModeAdditive)
+
+	surface := &testSurface{}
+	if err := layout.render(surface); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		fmt.Sprintf("draw %d", d2enum.BlendModeNormal),
+		fmt.Sprintf("set %d", d2enum.BlendModeAdditive),
+		fmt.Sprintf("draw %d", d2enum.BlendModeAdditive),
+		fmt.Sprintf("set %d", d2enum.BlendModeNormal),
+	}
+
+	if fmt.Sprint(surface.blendLog) != fmt.Sprint(want) {
+		t.Errorf("blend mode calls: wanted %v: got %v", want, surface.blendLog)
+	}
+}
@@ -0,0 +1,76 @@
+package d2hero
+
+import (
+	"errors"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+const (
+	// the per level and per attribute coefficients in charstats.txt are in fourths
+	statCoefficientPrecision = 4
+
+	attackRatingPerDex = 5
+	attackRatingBase   = -35
+	dexPerDefense      = 4
+)
+
+var errUnknownHeroClass = errors.New("no charstats record for hero class")
+
+// HeroAttributes are the attributes which players spend stat points on
+type HeroAttributes struct {
+	Strength  int
+	Dexterity int
+	Vitality  int
+	Energy    int
+}
+
+// HeroDerivedStats are the stats computed from a hero's class, attributes and level
+type HeroDerivedStats struct {
+	MaxHealth     int
+	MaxMana       int
+	MaxStamina    int
+	AttackRating  int
+	DefenseRating int
+}
+
+// RecalculateStats computes the derived stats of a hero of the given class,
+// using the class coefficients from charstats.txt
+func RecalculateStats(class d2enum.Hero, attributes HeroAttributes, level int) (*HeroDerivedStats, error) {
+	classStats, found := d2datadict.CharStats[class]
+	if !found {
+		return nil, errUnknownHeroClass
+	}
+
+	stats := CalculateStats(classStats, attributes, level)
+
+	return &stats, nil
+}
+
+// CalculateStats computes the derived stats of a hero with the given class
+// coefficients, attributes and level. Each attribute point above the class's
+// initial value, and each level above 1, adds the class's per point and per
+// level amounts.
+func CalculateStats(classStats *d2datadict.CharStatsRecord, attributes HeroAttributes, level int) HeroDerivedStats {
+	levelsGained := level - 1
+	vitGained := attributes.Vitality - classStats.InitVit
+	eneGained := attributes.Energy - classStats.InitEne
+
+	health := (classStats.InitVit+classStats.LifeAdd)*statCoefficientPrecision +
+		vitGained*classStats.LifePerVit + levelsGained*classStats.LifePerLevel
+
+	mana := classStats.InitEne*statCoefficientPrecision +
+		eneGained*classStats.ManaPerEne + levelsGained*classStats.ManaPerLevel
+
+	stamina := classStats.InitStamina*statCoefficientPrecision +
+		vitGained*classStats.StaminaPerVit + levelsGained*classStats.StaminaPerLevel
+
+	return HeroDerivedStats{
+		MaxHealth:     health / statCoefficientPrecision,
+		MaxMana:       mana / statCoefficientPrecision,
+		MaxStamina:    stamina / statCoefficientPrecision,
+		AttackRating:  attributes.Dexterity*attackRatingPerDex + attackRatingBase + classStats.ToHitFactor,
+		DefenseRating: attributes.Dexterity / dexPerDefense,
+	}
+}
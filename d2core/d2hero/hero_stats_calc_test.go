@@ -0,0 +1,77 @@
+package d2hero
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+func sorceressStats() *d2datadict.CharStatsRecord {
+	return &d2datadict.CharStatsRecord{
+		Class:           d2enum.HeroSorceress,
+		InitStr:         10,
+		InitDex:         25,
+		InitVit:         10,
+		InitEne:         35,
+		InitStamina:     74,
+		ToHitFactor:     -15,
+		LifePerLevel:    4,
+		ManaPerLevel:    8,
+		StaminaPerLevel: 4,
+		LifePerVit:      8,
+		ManaPerEne:      8,
+		StaminaPerVit:   4,
+		LifeAdd:         30,
+	}
+}
+
+func TestCalculateStatsInitial(t *testing.T) {
+	classStats := sorceressStats()
+
+	stats := CalculateStats(classStats, HeroAttributes{
+		Strength:  classStats.InitStr,
+		Dexterity: classStats.InitDex,
+		Vitality:  classStats.InitVit,
+		Energy:    classStats.InitEne,
+	}, 1)
+
+	want := HeroDerivedStats{MaxHealth: 40, MaxMana: 35, MaxStamina: 74, AttackRating: 75, DefenseRating: 6}
+	if stats != want {
+		t.Errorf("level 1 sorceress: wanted %+v: got %+v", want, stats)
+	}
+}
+
+func TestRecalculateStats(t *testing.T) {
+	d2datadict.CharStats = map[d2enum.Hero]*d2datadict.CharStatsRecord{
+		d2enum.HeroSorceress: sorceressStats(),
+	}
+
+	stats, err := RecalculateStats(d2enum.HeroSorceress, HeroAttributes{
+		Strength:  10,
+		Dexterity: 25,
+		Vitality:  20,
+		Energy:    45,
+	}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10 vitality at 2 life each, 9 levels at 1 life each
+	if stats.MaxHealth != 69 {
+		t.Errorf("max health: wanted 69: got %d", stats.MaxHealth)
+	}
+
+	// 10 energy at 2 mana each, 9 levels at 2 mana each
+	if stats.MaxMana != 73 {
+		t.Errorf("max mana: wanted 73: got %d", stats.MaxMana)
+	}
+
+	if stats.MaxStamina != 93 {
+		t.Errorf("max stamina: wanted 93: got %d", stats.MaxStamina)
+	}
+
+	if _, err := RecalculateStats(d2enum.HeroAmazon, HeroAttributes{}, 1); err == nil {
+		t.Error("wanted an error for a class without charstats")
+	}
+}
@@ -44,13 +44,21 @@ func CreateHeroStatsState(heroClass d2enum.Hero, classStats *d2datadict.CharStat
 		Dexterity:    classStats.InitDex,
 		Vitality:     classStats.InitVit,
 		Energy:       classStats.InitEne,
-
-		MaxHealth:  classStats.InitVit * classStats.LifePerVit,
-		MaxMana:    classStats.InitEne * classStats.ManaPerEne,
-		MaxStamina: classStats.InitStamina,
-		// TODO: chance to hit, defense rating
 	}
 
+	derived := CalculateStats(classStats, HeroAttributes{
+		Strength:  result.Strength,
+		Dexterity: result.Dexterity,
+		Vitality:  result.Vitality,
+		Energy:    result.Energy,
+	}, result.Level)
+
+	result.MaxHealth = derived.MaxHealth
+	result.MaxMana = derived.MaxMana
+	result.MaxStamina = derived.MaxStamina
+	result.AttackRating = derived.AttackRating
+	result.DefenseRating = derived.DefenseRating
+
 	result.Mana = result.MaxMana
 	result.Health = result.MaxHealth
 	result.Stamina = result.MaxStamina
@@ -22,6 +22,9 @@ var singleton *inputManager // TODO remove this singleton
 func Create() (d2interface.InputManager, error) {
 	singleton = &inputManager{
 		inputService: ebiten_input.InputService{},
+		keyBindings:  DefaultKeyBindings(),
+
+		gamepadDeadzone: DefaultGamepadDeadzone,
 	}
 
 	return singleton, nil
@@ -46,3 +49,13 @@ func BindHandler(handler d2interface.InputEventHandler) error {
 func UnbindHandler(handler d2interface.InputEventHandler) error {
 	return singleton.UnbindHandler(handler)
 }
+
+// Bindings returns the key bindings used to resolve key presses into input actions
+func Bindings() *KeyBindings {
+	return singleton.keyBindings
+}
+
+// SetGamepadDeadzone sets the distance from the center, from 0 to 1, within which gamepad sticks are ignored
+func SetGamepadDeadzone(deadzone float64) {
+	singleton.gamepadDeadzone = deadzone
+}
@@ -117,6 +117,24 @@ var (
 		d2enum.MouseButtonMiddle: ebiten.MouseButtonMiddle,
 		d2enum.MouseButtonRight:  ebiten.MouseButtonRight,
 	}
+	//nolint:gochecknoglobals This is a constant in all but by name, no constant map in go
+	gamepadButtonToEbiten = map[d2enum.GamepadButton]ebiten.GamepadButton{
+		d2enum.GamepadButtonA:             ebiten.GamepadButton0,
+		d2enum.GamepadButtonB:             ebiten.GamepadButton1,
+		d2enum.GamepadButtonX:             ebiten.GamepadButton2,
+		d2enum.GamepadButtonY:             ebiten.GamepadButton3,
+		d2enum.GamepadButtonLeftShoulder:  ebiten.GamepadButton4,
+		d2enum.GamepadButtonRightShoulder: ebiten.GamepadButton5,
+		d2enum.GamepadButtonBack:          ebiten.GamepadButton6,
+		d2enum.GamepadButtonStart:         ebiten.GamepadButton7,
+		d2enum.GamepadButtonLeftStick:     ebiten.GamepadButton8,
+		d2enum.GamepadButtonRightStick:    ebiten.GamepadButton9,
+	}
+	//nolint:gochecknoglobals This is a constant in all but by name, no constant map in go
+	gamepadStickToAxes = map[d2enum.GamepadStick][2]int{
+		d2enum.GamepadStickLeft:  {0, 1},
+		d2enum.GamepadStickRight: {2, 3},
+	}
 )
 
 // InputService provides an abstraction on ebiten to support handling input events
@@ -166,3 +184,34 @@ func (is InputService) IsMouseButtonJustReleased(button d2enum.MouseButton) bool
 func (is InputService) KeyPressDuration(key d2enum.Key) int {
 	return inpututil.KeyPressDuration(keyToEbiten[key])
 }
+
+// Wheel returns the mouse wheel offsets since the last update.
+func (is InputService) Wheel() (xoff, yoff float64) {
+	return ebiten.Wheel()
+}
+
+// GamepadStickPosition returns the position of a stick of the first connected gamepad, or 0, 0 if there is none.
+func (is InputService) GamepadStickPosition(stick d2enum.GamepadStick) (x, y float64) {
+	ids := ebiten.GamepadIDs()
+	if len(ids) == 0 {
+		return 0, 0
+	}
+
+	axes := gamepadStickToAxes[stick]
+	if ebiten.GamepadAxisNum(ids[0]) <= axes[1] {
+		return 0, 0
+	}
+
+	return ebiten.GamepadAxis(ids[0], axes[0]), ebiten.GamepadAxis(ids[0], axes[1])
+}
+
+// IsGamepadButtonJustPressed checks if the provided button of the first connected gamepad is just transitioned from
+// up to down.
+func (is InputService) IsGamepadButtonJustPressed(button d2enum.GamepadButton) bool {
+	ids := ebiten.GamepadIDs()
+	if len(ids) == 0 {
+		return false
+	}
+
+	return inpututil.IsGamepadButtonJustPressed(ids[0], gamepadButtonToEbiten[button])
+}
@@ -55,6 +55,40 @@ func (e *KeyEvent) Duration() int {
 	return e.duration
 }
 
+// InputActionEvent is triggered by a key or gamepad button bound to an input action
+type InputActionEvent struct {
+	HandlerEvent
+	action d2enum.InputAction
+}
+
+// Action returns the input action bound to the key or button
+func (e *InputActionEvent) Action() d2enum.InputAction {
+	return e.action
+}
+
+// GamepadStickEvent is triggered by a gamepad stick held outside of its deadzone
+type GamepadStickEvent struct {
+	HandlerEvent
+	stick      d2enum.GamepadStick
+	directionX float64
+	directionY float64
+}
+
+// Stick returns the stick which moved
+func (e *GamepadStickEvent) Stick() d2enum.GamepadStick {
+	return e.stick
+}
+
+// DirectionX returns the horizontal direction of the stick, positive values are right
+func (e *GamepadStickEvent) DirectionX() float64 {
+	return e.directionX
+}
+
+// DirectionY returns the vertical direction of the stick, positive values are down
+func (e *GamepadStickEvent) DirectionY() float64 {
+	return e.directionY
+}
+
 type MouseEvent struct {
 	HandlerEvent
 	mouseButton d2enum.MouseButton
@@ -80,6 +114,22 @@ func (e *MouseEvent) Button() d2enum.MouseButton {
 	return e.mouseButton
 }
 
+type MouseWheelEvent struct {
+	HandlerEvent
+	deltaX float64
+	deltaY float64
+}
+
+// DeltaX returns the horizontal wheel offset, positive values are to the right
+func (e *MouseWheelEvent) DeltaX() float64 {
+	return e.deltaX
+}
+
+// DeltaY returns the vertical wheel offset, positive values are up
+func (e *MouseWheelEvent) DeltaY() float64 {
+	return e.deltaY
+}
+
 type MouseMoveEvent struct {
 	HandlerEvent
 }
@@ -1,9 +1,11 @@
 package d2input
 
 import (
-	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"math"
 	"sort"
 
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 )
 
@@ -15,9 +17,15 @@ type inputManager struct {
 	buttonMod d2enum.MouseButtonMod
 	keyMod    d2enum.KeyMod
 
+	keyBindings     *KeyBindings
+	gamepadDeadzone float64
+
 	entries handlerEntryList
 }
 
+// DefaultGamepadDeadzone is the default distance from the center within which gamepad sticks are ignored
+const DefaultGamepadDeadzone = 0.25
+
 // Advance advances the inputManager
 func (im *inputManager) Advance(_, _ float64) error {
 	im.updateKeyMod()
@@ -46,6 +54,15 @@ func (im *inputManager) Advance(_, _ float64) error {
 	}
 
 	im.updateCursor(cursorX, cursorY, eventBase)
+	im.updateWheel(eventBase)
+
+	for button := d2enum.GamepadButtonMin; button <= d2enum.GamepadButtonMax; button++ {
+		im.updateJustPressedGamepadButton(button, eventBase)
+	}
+
+	for stick := d2enum.GamepadStickMin; stick <= d2enum.GamepadStickMax; stick++ {
+		im.updateGamepadStick(stick, eventBase)
+	}
 
 	return nil
 }
@@ -92,10 +109,31 @@ func (im *inputManager) updateJustPressedKey(k d2enum.Key, e HandlerEvent) {
 			return false
 		}
 
-		im.propagate(fn)
+		// keys consumed by a handler, e.g. typed into the terminal, do not trigger actions
+		if im.propagate(fn) || im.keyBindings == nil {
+			return
+		}
+
+		if action, ok := im.keyBindings.Action(k); ok {
+			im.propagateInputAction(action, e)
+		}
 	}
 }
 
+func (im *inputManager) propagateInputAction(action d2enum.InputAction, e HandlerEvent) {
+	event := InputActionEvent{HandlerEvent: e, action: action}
+
+	fn := func(handler d2interface.InputEventHandler) bool {
+		if l, ok := handler.(d2interface.InputActionHandler); ok {
+			return l.OnInputAction(&event)
+		}
+
+		return false
+	}
+
+	im.propagate(fn)
+}
+
 func (im *inputManager) updateJustReleasedKey(k d2enum.Key, e HandlerEvent) {
 	if im.inputService.IsKeyJustReleased(k) {
 		event := KeyEvent{HandlerEvent: e, key: k}
@@ -207,6 +245,65 @@ func (im *inputManager) updateCursor(cursorX, cursorY int, e HandlerEvent) {
 	}
 }
 
+func (im *inputManager) updateWheel(e HandlerEvent) {
+	if deltaX, deltaY := im.inputService.Wheel(); deltaX != 0 || deltaY != 0 {
+		event := MouseWheelEvent{e, deltaX, deltaY}
+
+		fn := func(handler d2interface.InputEventHandler) bool {
+			if l, ok := handler.(d2interface.MouseWheelHandler); ok {
+				return l.OnMouseWheel(&event)
+			}
+
+			return false
+		}
+		im.propagate(fn)
+	}
+}
+
+func (im *inputManager) updateJustPressedGamepadButton(b d2enum.GamepadButton, e HandlerEvent) {
+	if im.keyBindings == nil || !im.inputService.IsGamepadButtonJustPressed(b) {
+		return
+	}
+
+	if action, ok := im.keyBindings.ButtonAction(b); ok {
+		im.propagateInputAction(action, e)
+	}
+}
+
+func (im *inputManager) updateGamepadStick(stick d2enum.GamepadStick, e HandlerEvent) {
+	x, y := im.inputService.GamepadStickPosition(stick)
+
+	directionX, directionY, moved := StickDirection(x, y, im.gamepadDeadzone)
+	if !moved {
+		return
+	}
+
+	event := GamepadStickEvent{e, stick, directionX, directionY}
+
+	fn := func(handler d2interface.InputEventHandler) bool {
+		if l, ok := handler.(d2interface.GamepadStickHandler); ok {
+			return l.OnGamepadStick(&event)
+		}
+
+		return false
+	}
+	im.propagate(fn)
+}
+
+// StickDirection applies a radial deadzone to a stick position. Positions within deadzone of the center return
+// false, positions beyond it are rescaled so the direction grows from zero at the edge of the deadzone to unit length
+// at the edge of the stick's range.
+func StickDirection(x, y, deadzone float64) (directionX, directionY float64, moved bool) {
+	magnitude := math.Hypot(x, y)
+	if magnitude <= deadzone || deadzone >= 1 {
+		return 0, 0, false
+	}
+
+	scale := (math.Min(magnitude, 1) - deadzone) / (1 - deadzone) / magnitude
+
+	return x * scale, y * scale, true
+}
+
 // BindHandlerWithPriority adds an event handler with a specific call priority
 func (im *inputManager) BindHandlerWithPriority(
 	h d2interface.InputEventHandler,
@@ -248,7 +345,9 @@ func (im *inputManager) UnbindHandler(handler d2interface.InputEventHandler) err
 	return ErrNotReg
 }
 
-func (im *inputManager) propagate(callback func(d2interface.InputEventHandler) bool) {
+// propagate calls the callback for the handlers in priority order, stopping after the highest priority which handled
+// the event. It returns true if any handler handled the event.
+func (im *inputManager) propagate(callback func(d2interface.InputEventHandler) bool) bool {
 	var priority d2enum.Priority
 
 	var handled bool
@@ -264,6 +363,8 @@ func (im *inputManager) propagate(callback func(d2interface.InputEventHandler) b
 
 		priority = entry.priority
 	}
+
+	return handled
 }
 
 type handlerEntry struct {
@@ -0,0 +1,117 @@
+package d2input
+
+import (
+	"math"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// testInputService reports a single key or gamepad button as just pressed, and a fixed left stick position
+type testInputService struct {
+	justPressed       d2enum.Key
+	justPressedButton d2enum.GamepadButton
+	stickX, stickY    float64
+}
+
+func (s *testInputService) CursorPosition() (x, y int)                          { return 0, 0 }
+func (s *testInputService) InputChars() []rune                                  { return nil }
+func (s *testInputService) IsKeyPressed(key d2enum.Key) bool                    { return false }
+func (s *testInputService) IsKeyJustPressed(key d2enum.Key) bool                { return key == s.justPressed }
+func (s *testInputService) IsKeyJustReleased(key d2enum.Key) bool               { return false }
+func (s *testInputService) IsMouseButtonPressed(_ d2enum.MouseButton) bool      { return false }
+func (s *testInputService) IsMouseButtonJustPressed(_ d2enum.MouseButton) bool  { return false }
+func (s *testInputService) IsMouseButtonJustReleased(_ d2enum.MouseButton) bool { return false }
+func (s *testInputService) KeyPressDuration(key d2enum.Key) int                 { return 0 }
+func (s *testInputService) Wheel() (xoff, yoff float64)                         { return 0, 0 }
+
+func (s *testInputService) GamepadStickPosition(stick d2enum.GamepadStick) (x, y float64) {
+	if stick != d2enum.GamepadStickLeft {
+		return 0, 0
+	}
+
+	return s.stickX, s.stickY
+}
+
+func (s *testInputService) IsGamepadButtonJustPressed(button d2enum.GamepadButton) bool {
+	return button == s.justPressedButton
+}
+
+// testStickHandler records the stick events it receives
+type testStickHandler struct {
+	events []d2interface.GamepadStickEvent
+}
+
+func (h *testStickHandler) OnGamepadStick(event d2interface.GamepadStickEvent) bool {
+	h.events = append(h.events, event)
+	return true
+}
+
+func newTestInputManager(service *testInputService) *inputManager {
+	return &inputManager{
+		inputService:    service,
+		keyBindings:     DefaultKeyBindings(),
+		gamepadDeadzone: DefaultGamepadDeadzone,
+	}
+}
+
+func TestGamepadStickDeadzone(t *testing.T) {
+	service := &testInputService{justPressed: -1, justPressedButton: -1}
+	handler := &testStickHandler{}
+
+	im := newTestInputManager(service)
+	if err := im.bindHandler(handler, d2enum.PriorityDefault); err != nil {
+		t.Fatal(err)
+	}
+
+	service.stickX, service.stickY = 0.1, -0.15
+	_ = im.Advance(0, 0)
+
+	if len(handler.events) != 0 {
+		t.Fatalf("stick inside the deadzone: wanted no movement: got %d events", len(handler.events))
+	}
+
+	service.stickX, service.stickY = 0.6, 0.8
+	_ = im.Advance(0, 0)
+
+	if len(handler.events) != 1 {
+		t.Fatalf("stick outside the deadzone: wanted 1 event: got %d", len(handler.events))
+	}
+
+	event := handler.events[0]
+	gotX, gotY := event.DirectionX(), event.DirectionY()
+
+	// a fully deflected stick keeps its direction at unit length
+	if math.Abs(gotX-0.6) > 1e-9 || math.Abs(gotY-0.8) > 1e-9 {
+		t.Errorf("direction: wanted (0.6, 0.8): got (%v, %v)", gotX, gotY)
+	}
+}
+
+func TestStickDirectionRescale(t *testing.T) {
+	if _, _, moved := StickDirection(0, 0.25, 0.25); moved {
+		t.Error("stick on the deadzone edge: wanted no movement")
+	}
+
+	// halfway between the deadzone edge and full deflection
+	x, y, moved := StickDirection(-0.625, 0, 0.25)
+	if !moved || math.Abs(x+0.5) > 1e-9 || y != 0 {
+		t.Errorf("direction: wanted (-0.5, 0): got (%v, %v), moved %v", x, y, moved)
+	}
+}
+
+func TestGamepadButtonAction(t *testing.T) {
+	service := &testInputService{justPressed: -1, justPressedButton: d2enum.GamepadButtonY}
+	handler := &testActionHandler{}
+
+	im := newTestInputManager(service)
+	if err := im.bindHandler(handler, d2enum.PriorityDefault); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = im.Advance(0, 0)
+
+	if len(handler.actions) != 1 || handler.actions[0] != d2enum.InputActionToggleInventory {
+		t.Errorf("actions: wanted [%d]: got %v", d2enum.InputActionToggleInventory, handler.actions)
+	}
+}
@@ -0,0 +1,169 @@
+package d2input
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+)
+
+var (
+	// ErrKeyAlreadyBound is returned when binding a key which is already bound to another action
+	ErrKeyAlreadyBound = errors.New("key is already bound to another action")
+	// ErrUnknownInputAction is returned when loading a binding for an action which does not exist
+	ErrUnknownInputAction = errors.New("unknown input action")
+)
+
+// KeyBindings maps input actions to keys and gamepad buttons. Each action is bound to at most one key and one button,
+// and each key or button to at most one action.
+type KeyBindings struct {
+	actionKeys    map[d2enum.InputAction]d2enum.Key
+	keyActions    map[d2enum.Key]d2enum.InputAction
+	buttonActions map[d2enum.GamepadButton]d2enum.InputAction
+}
+
+// NewKeyBindings creates key bindings without any bound actions
+func NewKeyBindings() *KeyBindings {
+	return &KeyBindings{
+		actionKeys:    make(map[d2enum.InputAction]d2enum.Key),
+		keyActions:    make(map[d2enum.Key]d2enum.InputAction),
+		buttonActions: make(map[d2enum.GamepadButton]d2enum.InputAction),
+	}
+}
+
+// DefaultKeyBindings creates the key bindings used when nothing is configured
+func DefaultKeyBindings() *KeyBindings {
+	bindings := NewKeyBindings()
+
+	defaults := map[d2enum.InputAction]d2enum.Key{
+		d2enum.InputActionMoveUp:          d2enum.KeyUp,
+		d2enum.InputActionMoveDown:        d2enum.KeyDown,
+		d2enum.InputActionMoveLeft:        d2enum.KeyLeft,
+		d2enum.InputActionMoveRight:       d2enum.KeyRight,
+		d2enum.InputActionToggleInventory: d2enum.KeyI,
+		d2enum.InputActionToggleCharacter: d2enum.KeyC,
+		d2enum.InputActionToggleAutomap:   d2enum.KeyTab,
+		d2enum.InputActionToggleRun:       d2enum.KeyR,
+		d2enum.InputActionSkill1:          d2enum.KeyF1,
+		d2enum.InputActionSkill2:          d2enum.KeyF2,
+		d2enum.InputActionSkill3:          d2enum.KeyF3,
+		d2enum.InputActionSkill4:          d2enum.KeyF4,
+		d2enum.InputActionSkill5:          d2enum.KeyF5,
+		d2enum.InputActionSkill6:          d2enum.KeyF6,
+		d2enum.InputActionSkill7:          d2enum.KeyF7,
+		d2enum.InputActionSkill8:          d2enum.KeyF8,
+	}
+
+	for action, key := range defaults {
+		bindings.actionKeys[action] = key
+		bindings.keyActions[key] = action
+	}
+
+	bindings.buttonActions = map[d2enum.GamepadButton]d2enum.InputAction{
+		d2enum.GamepadButtonY:             d2enum.InputActionToggleInventory,
+		d2enum.GamepadButtonX:             d2enum.InputActionToggleCharacter,
+		d2enum.GamepadButtonBack:          d2enum.InputActionToggleAutomap,
+		d2enum.GamepadButtonB:             d2enum.InputActionToggleRun,
+		d2enum.GamepadButtonLeftShoulder:  d2enum.InputActionSkill1,
+		d2enum.GamepadButtonRightShoulder: d2enum.InputActionSkill2,
+	}
+
+	return bindings
+}
+
+// Rebind binds the action to the given key, freeing the key the action was bound to before. If the key is bound to
+// another action the bindings are left unchanged and ErrKeyAlreadyBound is returned.
+func (b *KeyBindings) Rebind(action d2enum.InputAction, key d2enum.Key) error {
+	if bound, ok := b.keyActions[key]; ok {
+		if bound == action {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %d is bound to %s", ErrKeyAlreadyBound, key, bound)
+	}
+
+	b.Unbind(action)
+
+	b.actionKeys[action] = key
+	b.keyActions[key] = action
+
+	return nil
+}
+
+// Unbind removes the key binding of the action
+func (b *KeyBindings) Unbind(action d2enum.InputAction) {
+	if key, ok := b.actionKeys[action]; ok {
+		delete(b.keyActions, key)
+		delete(b.actionKeys, action)
+	}
+}
+
+// Key returns the key bound to the action. The second return value is false if the action is not bound.
+func (b *KeyBindings) Key(action d2enum.InputAction) (d2enum.Key, bool) {
+	key, ok := b.actionKeys[action]
+	return key, ok
+}
+
+// Action returns the action bound to the key. The second return value is false if the key is not bound.
+func (b *KeyBindings) Action(key d2enum.Key) (d2enum.InputAction, bool) {
+	action, ok := b.keyActions[key]
+	return action, ok
+}
+
+// RebindButton binds the gamepad button to the action. The action stays bound to its key, and any other button bound
+// to the action is freed.
+func (b *KeyBindings) RebindButton(action d2enum.InputAction, button d2enum.GamepadButton) {
+	for bound, boundAction := range b.buttonActions {
+		if boundAction == action {
+			delete(b.buttonActions, bound)
+		}
+	}
+
+	b.buttonActions[button] = action
+}
+
+// ButtonAction returns the action bound to the gamepad button. The second return value is false if the button is not
+// bound.
+func (b *KeyBindings) ButtonAction(button d2enum.GamepadButton) (d2enum.InputAction, bool) {
+	action, ok := b.buttonActions[button]
+	return action, ok
+}
+
+// Load rebinds the actions to the keys in the given map of action names to keys, as saved by Save. Actions missing
+// from the map keep their current binding.
+func (b *KeyBindings) Load(bindings map[string]d2enum.Key) error {
+	actions := make(map[d2enum.InputAction]d2enum.Key, len(bindings))
+
+	for name, key := range bindings {
+		action, ok := d2enum.InputActionFromString(name)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownInputAction, name)
+		}
+
+		actions[action] = key
+	}
+
+	// unbind first so actions can swap keys
+	for action := range actions {
+		b.Unbind(action)
+	}
+
+	for action, key := range actions {
+		if err := b.Rebind(action, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Save returns the bindings as a map of action names to keys
+func (b *KeyBindings) Save() map[string]d2enum.Key {
+	bindings := make(map[string]d2enum.Key, len(b.actionKeys))
+
+	for action, key := range b.actionKeys {
+		bindings[action.String()] = key
+	}
+
+	return bindings
+}
@@ -0,0 +1,98 @@
+package d2input
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// testActionHandler records the input actions it receives
+type testActionHandler struct {
+	actions []d2enum.InputAction
+}
+
+func (h *testActionHandler) OnInputAction(event d2interface.InputActionEvent) bool {
+	h.actions = append(h.actions, event.Action())
+	return true
+}
+
+func TestKeyBindingsRebindRoutesKey(t *testing.T) {
+	service := &testInputService{justPressed: -1, justPressedButton: -1}
+	handler := &testActionHandler{}
+
+	im := newTestInputManager(service)
+	if err := im.bindHandler(handler, d2enum.PriorityDefault); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := im.keyBindings.Rebind(d2enum.InputActionToggleInventory, d2enum.KeyB); err != nil {
+		t.Fatal(err)
+	}
+
+	service.justPressed = d2enum.KeyB
+	_ = im.Advance(0, 0)
+
+	if len(handler.actions) != 1 || handler.actions[0] != d2enum.InputActionToggleInventory {
+		t.Errorf("actions for the new key: wanted [%d]: got %v", d2enum.InputActionToggleInventory, handler.actions)
+	}
+
+	handler.actions = nil
+	service.justPressed = d2enum.KeyI
+	_ = im.Advance(0, 0)
+
+	if len(handler.actions) != 0 {
+		t.Errorf("actions for the old key: wanted none: got %v", handler.actions)
+	}
+
+	if _, bound := im.keyBindings.Action(d2enum.KeyI); bound {
+		t.Error("old key: wanted unbound")
+	}
+}
+
+func TestKeyBindingsConflict(t *testing.T) {
+	bindings := DefaultKeyBindings()
+
+	err := bindings.Rebind(d2enum.InputActionToggleInventory, d2enum.KeyC)
+	if !errors.Is(err, ErrKeyAlreadyBound) {
+		t.Errorf("binding a taken key: wanted %v: got %v", ErrKeyAlreadyBound, err)
+	}
+
+	if key, _ := bindings.Key(d2enum.InputActionToggleInventory); key != d2enum.KeyI {
+		t.Errorf("refused rebind: wanted key %d: got %d", d2enum.KeyI, key)
+	}
+
+	if action, _ := bindings.Action(d2enum.KeyC); action != d2enum.InputActionToggleCharacter {
+		t.Errorf("refused rebind: wanted action %d: got %d", d2enum.InputActionToggleCharacter, action)
+	}
+}
+
+func TestKeyBindingsLoadSave(t *testing.T) {
+	bindings := DefaultKeyBindings()
+
+	// swapping two keys only works if both are loaded together
+	err := bindings.Load(map[string]d2enum.Key{
+		"ToggleInventory": d2enum.KeyC,
+		"ToggleCharacter": d2enum.KeyI,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewKeyBindings()
+	if err := loaded.Load(bindings.Save()); err != nil {
+		t.Fatal(err)
+	}
+
+	for action := d2enum.InputAction(0); action < d2enum.InputActionCount; action++ {
+		want, _ := bindings.Key(action)
+		if got, _ := loaded.Key(action); got != want {
+			t.Errorf("%s: wanted key %d: got %d", action, want, got)
+		}
+	}
+
+	if err := loaded.Load(map[string]d2enum.Key{"NoSuchAction": d2enum.KeyA}); !errors.Is(err, ErrUnknownInputAction) {
+		t.Errorf("unknown action: wanted %v: got %v", ErrUnknownInputAction, err)
+	}
+}
@@ -0,0 +1,150 @@
+package d2automap
+
+import (
+	"image/color"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+const (
+	// DefaultCellWidth is the default width, in pixels, of a tile drawn on the automap.
+	DefaultCellWidth = 8
+
+	// DefaultCellHeight is the default height, in pixels, of a tile drawn on the automap.
+	DefaultCellHeight = 4
+
+	dotSize  = 2
+	blipSize = 4
+)
+
+//nolint:gochecknoglobals // Currently global by design, treated as constants
+var (
+	wallColor     = color.RGBA{R: 200, G: 160, B: 100, A: 255}
+	walkableColor = color.RGBA{R: 90, G: 80, B: 60, A: 160}
+
+	// PlayerBlipColor is the color used for the player on the automap.
+	PlayerBlipColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	// NPCBlipColor is the color used for NPCs on the automap.
+	NPCBlipColor = color.RGBA{R: 220, G: 200, B: 40, A: 255}
+)
+
+// Blip is an entity marker drawn on the automap. X and Y are in tiles.
+type Blip struct {
+	X, Y  float64
+	Color color.Color
+}
+
+// Automap records which tiles of a map have been explored and draws them as a scaled isometric overlay.
+type Automap struct {
+	width, height int
+	explored      []bool
+	isWalkable    func(tileX, tileY int) bool
+	visible       bool
+	cellWidth     int
+	cellHeight    int
+}
+
+// NewAutomap creates an automap for a map of the given size, in tiles. isWalkable reports whether a tile can be
+// walked on; tiles which can not are drawn as walls.
+func NewAutomap(width, height int, isWalkable func(tileX, tileY int) bool) *Automap {
+	return &Automap{
+		width:      width,
+		height:     height,
+		explored:   make([]bool, width*height),
+		isWalkable: isWalkable,
+		cellWidth:  DefaultCellWidth,
+		cellHeight: DefaultCellHeight,
+	}
+}
+
+// Reset forgets every explored tile and resizes the automap, for when the map is regenerated.
+func (a *Automap) Reset(width, height int) {
+	a.width, a.height = width, height
+	a.explored = make([]bool, width*height)
+}
+
+// SetCellSize sets the size, in pixels, of a tile drawn on the automap.
+func (a *Automap) SetCellSize(width, height int) {
+	a.cellWidth, a.cellHeight = width, height
+}
+
+// Visit marks the given tile as explored. It has the signature of a map entity tile enter callback, so it can be
+// hooked directly into the player's movement. Tiles outside the map are ignored.
+func (a *Automap) Visit(tileX, tileY int) {
+	if !a.contains(tileX, tileY) {
+		return
+	}
+
+	a.explored[tileX+tileY*a.width] = true
+}
+
+// IsExplored returns true if the given tile has been visited.
+func (a *Automap) IsExplored(tileX, tileY int) bool {
+	return a.contains(tileX, tileY) && a.explored[tileX+tileY*a.width]
+}
+
+// Toggle shows the automap if it is hidden and hides it otherwise.
+func (a *Automap) Toggle() {
+	a.visible = !a.visible
+}
+
+// SetVisible shows or hides the automap.
+func (a *Automap) SetVisible(visible bool) {
+	a.visible = visible
+}
+
+// IsVisible returns true if the automap is shown.
+func (a *Automap) IsVisible() bool {
+	return a.visible
+}
+
+// Render draws the explored tiles and the given blips, centered on the target at the given tile coordinates. Nothing
+// is drawn while the automap is hidden.
+func (a *Automap) Render(target d2interface.Surface, centerX, centerY float64, blips []Blip) {
+	if !a.visible {
+		return
+	}
+
+	width, height := target.GetSize()
+	originX, originY := width/2, height/2
+
+	for tileY := 0; tileY < a.height; tileY++ {
+		for tileX := 0; tileX < a.width; tileX++ {
+			if !a.explored[tileX+tileY*a.width] {
+				continue
+			}
+
+			cellColor := color.Color(walkableColor)
+			if a.isWalkable != nil && !a.isWalkable(tileX, tileY) {
+				cellColor = wallColor
+			}
+
+			x, y := a.project(float64(tileX)-centerX, float64(tileY)-centerY)
+			a.drawDot(target, originX+x, originY+y, dotSize, cellColor)
+		}
+	}
+
+	for idx := range blips {
+		x, y := a.project(blips[idx].X-centerX, blips[idx].Y-centerY)
+		a.drawDot(target, originX+x, originY+y, blipSize, blips[idx].Color)
+	}
+}
+
+// project converts a tile offset into an isometric pixel offset.
+func (a *Automap) project(tileX, tileY float64) (x, y int) {
+	x = int((tileX - tileY) * float64(a.cellWidth) / 2)
+	y = int((tileX + tileY) * float64(a.cellHeight) / 2)
+
+	return x, y
+}
+
+func (a *Automap) drawDot(target d2interface.Surface, x, y, size int, dotColor color.Color) {
+	target.PushTranslation(x-size/2, y-size/2)
+	target.DrawRect(size, size, dotColor)
+	target.Pop()
+}
+
+func (a *Automap) contains(tileX, tileY int) bool {
+	return tileX >= 0 && tileY >= 0 && tileX < a.width && tileY < a.height
+}
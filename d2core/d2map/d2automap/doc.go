@@ -0,0 +1,3 @@
+// Package d2automap provides the automap, an overlay map of the
+// areas the player has explored.
+package d2automap
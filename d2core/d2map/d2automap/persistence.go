@@ -0,0 +1,94 @@
+package d2automap
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+)
+
+const (
+	// exploredHeaderSize is the size of the map width and height which precede the explored tile bits.
+	exploredHeaderSize = 8
+
+	exploredFileExtension = ".map"
+)
+
+var (
+	// ErrInvalidExploredData is returned when decoding exploration data which is truncated or malformed.
+	ErrInvalidExploredData = errors.New("invalid automap exploration data")
+
+	// ErrExploredSizeMismatch is returned when decoding exploration data saved for a map of a different size.
+	ErrExploredSizeMismatch = errors.New("automap exploration data does not match the map size")
+)
+
+// AreaKey returns the key under which the exploration of an area is saved.
+func AreaKey(act, levelTypeID int) string {
+	return fmt.Sprintf("act%d_%d", act, levelTypeID)
+}
+
+// MarshalExplored encodes the explored tiles as the map width and height followed by one bit per tile.
+func (a *Automap) MarshalExplored() []byte {
+	data := make([]byte, exploredHeaderSize+(len(a.explored)+7)/8)
+
+	binary.LittleEndian.PutUint32(data[0:], uint32(a.width))
+	binary.LittleEndian.PutUint32(data[4:], uint32(a.height))
+
+	bits := data[exploredHeaderSize:]
+
+	for idx, explored := range a.explored {
+		if explored {
+			bits[idx/8] |= 1 << (idx % 8)
+		}
+	}
+
+	return data
+}
+
+// UnmarshalExplored restores the explored tiles from data encoded by MarshalExplored. The data must have been saved
+// for a map of the same size as the automap, otherwise the explored tiles are left untouched.
+func (a *Automap) UnmarshalExplored(data []byte) error {
+	if len(data) < exploredHeaderSize {
+		return ErrInvalidExploredData
+	}
+
+	width := int(binary.LittleEndian.Uint32(data[0:]))
+	height := int(binary.LittleEndian.Uint32(data[4:]))
+
+	if width != a.width || height != a.height {
+		return ErrExploredSizeMismatch
+	}
+
+	bits := data[exploredHeaderSize:]
+	if len(bits) != (width*height+7)/8 {
+		return ErrInvalidExploredData
+	}
+
+	for idx := range a.explored {
+		a.explored[idx] = bits[idx/8]&(1<<(idx%8)) != 0
+	}
+
+	return nil
+}
+
+// SaveExplored writes the explored tiles to the given directory, under the given area key.
+func (a *Automap) SaveExplored(dir, areaKey string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path.Join(dir, areaKey+exploredFileExtension), a.MarshalExplored(), 0644)
+}
+
+// LoadExplored restores the explored tiles saved in the given directory under the given area key. If nothing was
+// saved for the area the returned error satisfies os.IsNotExist.
+func (a *Automap) LoadExplored(dir, areaKey string) error {
+	data, err := ioutil.ReadFile(path.Join(dir, areaKey+exploredFileExtension))
+	if err != nil {
+		return err
+	}
+
+	return a.UnmarshalExplored(data)
+}
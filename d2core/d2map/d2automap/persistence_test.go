@@ -0,0 +1,64 @@
+package d2automap
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestAutomapExploredRoundTrip(t *testing.T) {
+	const width, height = 13, 7
+
+	saved := NewAutomap(width, height, nil)
+	for _, tile := range [][2]int{{0, 0}, {12, 0}, {5, 3}, {6, 3}, {7, 4}, {12, 6}} {
+		saved.Visit(tile[0], tile[1])
+	}
+
+	dir, err := ioutil.TempDir("", "automap")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	areaKey := AreaKey(1, 2)
+
+	if err := saved.SaveExplored(dir, areaKey); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewAutomap(width, height, nil)
+	if err := loaded.LoadExplored(dir, areaKey); err != nil {
+		t.Fatal(err)
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if saved.IsExplored(x, y) != loaded.IsExplored(x, y) {
+				t.Errorf("tile (%d, %d) explored: wanted %v: got %v", x, y, saved.IsExplored(x, y), loaded.IsExplored(x, y))
+			}
+		}
+	}
+
+	if got, maxSize := len(saved.MarshalExplored()), exploredHeaderSize+(width*height+7)/8; got > maxSize {
+		t.Errorf("encoded size: wanted at most %d bytes: got %d", maxSize, got)
+	}
+}
+
+func TestAutomapExploredSizeMismatch(t *testing.T) {
+	saved := NewAutomap(4, 4, nil)
+	saved.Visit(1, 1)
+
+	loaded := NewAutomap(5, 4, nil)
+	if err := loaded.UnmarshalExplored(saved.MarshalExplored()); err != ErrExploredSizeMismatch {
+		t.Errorf("wanted %v: got %v", ErrExploredSizeMismatch, err)
+	}
+
+	if err := loaded.UnmarshalExplored([]byte{1, 2}); err != ErrInvalidExploredData {
+		t.Errorf("wanted %v: got %v", ErrInvalidExploredData, err)
+	}
+
+	if err := loaded.LoadExplored(os.TempDir(), "no_such_area"); !os.IsNotExist(err) {
+		t.Errorf("missing area: wanted a not exist error: got %v", err)
+	}
+}
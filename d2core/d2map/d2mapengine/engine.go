@@ -2,6 +2,7 @@ package d2mapengine
 
 import (
 	"log"
+	"math"
 	"strings"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
@@ -14,10 +15,18 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapstamp"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
 )
 
+// removableEntity is implemented by entities which can be marked for removal from the map, see RemoveEntity.
+type removableEntity interface {
+	MarkForRemoval()
+	ShouldRemove() bool
+	Cleanup()
+}
+
 // MapEngine loads the tiles which make up the isometric map and the entities
 type MapEngine struct {
 	seed          int64                      // The map seed
@@ -30,15 +39,28 @@ type MapEngine struct {
 	startSubTileX int                        // Starting X position
 	startSubTileY int                        // Starting Y position
 	dt1Files      []string                   // List of DS1 strings
+	pathCache     *d2astar.PathCache         // Recently found paths
+	walkVersion   uint64                     // Incremented whenever the walk mesh changes
+	allowDiagonal bool                       // Whether paths can move diagonally
 }
 
 // CreateMapEngine creates a new instance of the map engine and
 // returns a pointer to it.
 func CreateMapEngine() *MapEngine {
-	engine := &MapEngine{}
+	engine := &MapEngine{
+		pathCache:     d2astar.NewPathCache(d2astar.DefaultPathCacheSize),
+		allowDiagonal: true,
+	}
+
 	return engine
 }
 
+// SetPathCacheSize sets the number of recently found paths kept by the
+// map engine.
+func (m *MapEngine) SetPathCacheSize(size int) {
+	m.pathCache.SetSize(size)
+}
+
 // WalkMesh returns a pointer to a slice with the map's PathTiles.
 func (m *MapEngine) WalkMesh() *[]d2common.PathTile {
 	return &m.walkMesh
@@ -59,6 +81,7 @@ func (m *MapEngine) ResetMap(levelType d2enum.RegionIdType, width, height int) {
 	m.tiles = make([]d2ds1.TileRecord, width*height)
 	m.dt1TileData = make([]d2dt1.Tile, 0)
 	m.walkMesh = make([]d2common.PathTile, width*height*25)
+	m.walkVersion++
 	m.dt1Files = make([]string, 0)
 
 	for idx := range m.levelType.Files {
@@ -212,6 +235,34 @@ func (m *MapEngine) Entities() *[]d2interface.MapEntity {
 	return &m.entities
 }
 
+// GetEntityByID returns the entity with the given unique ID, or nil if there is no such entity on the map.
+func (m *MapEngine) GetEntityByID(id uint64) d2interface.MapEntity {
+	for idx := range m.entities {
+		entity, ok := m.entities[idx].(interface{ ID() uint64 })
+		if ok && entity.ID() == id {
+			return m.entities[idx]
+		}
+	}
+
+	return nil
+}
+
+// GetEntitiesInRadius returns the entities whose sub tile position is within the given distance, in sub tiles, of
+// the given sub tile location.
+func (m *MapEngine) GetEntitiesInRadius(x, y, radius float64) []d2interface.MapEntity {
+	var entities []d2interface.MapEntity
+
+	for idx := range m.entities {
+		entityX, entityY := m.entities[idx].GetPositionF()
+
+		if math.Hypot(entityX-x, entityY-y) <= radius {
+			entities = append(entities, m.entities[idx])
+		}
+	}
+
+	return entities
+}
+
 // Seed returns the map generation seed.
 func (m *MapEngine) Seed() int64 {
 	return m.seed
@@ -222,12 +273,50 @@ func (m *MapEngine) AddEntity(entity d2interface.MapEntity) {
 	m.entities = append(m.entities, entity)
 }
 
-// RemoveEntity is not currently implemented.
+// RemoveEntity removes an entity from the map. Entities which can be marked for removal are marked and removed when
+// the entities are next advanced, so it is safe to call while they are being advanced.
 func (m *MapEngine) RemoveEntity(entity d2interface.MapEntity) {
 	if entity == nil {
 		return
 	}
-	// m.entities.Remove(entity)
+
+	if removable, ok := entity.(removableEntity); ok {
+		removable.MarkForRemoval()
+		return
+	}
+
+	for idx := range m.entities {
+		if m.entities[idx] == entity {
+			m.entities = append(m.entities[:idx], m.entities[idx+1:]...)
+			return
+		}
+	}
+}
+
+// removeMarkedEntities removes the entities marked for removal from the map and cleans up after them.
+func (m *MapEngine) removeMarkedEntities() {
+	kept := m.entities[:0]
+
+	var removed []removableEntity
+
+	for _, entity := range m.entities {
+		if removable, ok := entity.(removableEntity); ok && removable.ShouldRemove() {
+			removed = append(removed, removable)
+			continue
+		}
+
+		kept = append(kept, entity)
+	}
+
+	for idx := len(kept); idx < len(m.entities); idx++ {
+		m.entities[idx] = nil
+	}
+
+	m.entities = kept
+
+	for _, entity := range removed {
+		entity.Cleanup()
+	}
 }
 
 // GetTiles returns a slice of all tiles matching the given style,
@@ -278,6 +367,8 @@ func (m *MapEngine) Advance(tickTime float64) {
 	for idx := range m.entities {
 		m.entities[idx].Advance(tickTime)
 	}
+
+	m.removeMarkedEntities()
 }
 
 // TileExists returns true if the tile at the given coordinates exists.
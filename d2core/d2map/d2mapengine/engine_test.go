@@ -0,0 +1,92 @@
+package d2mapengine
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+type testEntity struct {
+	id            uint64
+	advances      int
+	markedRemoval bool
+	cleanups      int
+}
+
+func (e *testEntity) Render(_ d2interface.Surface)     {}
+func (e *testEntity) Advance(_ float64)                { e.advances++ }
+func (e *testEntity) GetPosition() (float64, float64)  { return 0, 0 }
+func (e *testEntity) GetLayer() int                    { return 0 }
+func (e *testEntity) GetPositionF() (float64, float64) { return 0, 0 }
+func (e *testEntity) Name() string                     { return "" }
+func (e *testEntity) Selectable() bool                 { return false }
+func (e *testEntity) Highlight()                       {}
+func (e *testEntity) MarkForRemoval()                  { e.markedRemoval = true }
+func (e *testEntity) ShouldRemove() bool               { return e.markedRemoval }
+func (e *testEntity) Cleanup()                         { e.cleanups++ }
+func (e *testEntity) ID() uint64                       { return e.id }
+
+func TestMapEngineRemoveEntity(t *testing.T) {
+	engine := CreateMapEngine()
+
+	kept := &testEntity{}
+	removed := &testEntity{}
+
+	engine.AddEntity(kept)
+	engine.AddEntity(removed)
+
+	engine.RemoveEntity(removed)
+
+	if !removed.ShouldRemove() {
+		t.Fatal("removed entity should be marked for removal")
+	}
+
+	if got := len(*engine.Entities()); got != 2 {
+		t.Fatalf("entities before advancing: wanted %d: got %d", 2, got)
+	}
+
+	engine.Advance(0.1)
+	engine.Advance(0.1)
+
+	entities := *engine.Entities()
+	if len(entities) != 1 || entities[0] != kept {
+		t.Fatalf("entities after advancing: wanted only the kept entity: got %d entities", len(entities))
+	}
+
+	if removed.cleanups != 1 {
+		t.Errorf("removed entity cleanups: wanted %d: got %d", 1, removed.cleanups)
+	}
+
+	if kept.cleanups != 0 || kept.advances != 2 {
+		t.Errorf("kept entity: wanted %d cleanups and %d advances: got %d and %d", 0, 2, kept.cleanups, kept.advances)
+	}
+}
+
+func TestMapEngineGetEntityByID(t *testing.T) {
+	engine := CreateMapEngine()
+
+	first := &testEntity{id: 1}
+	second := &testEntity{id: 2}
+
+	engine.AddEntity(first)
+	engine.AddEntity(second)
+
+	if got := engine.GetEntityByID(2); got != second {
+		t.Errorf("entity with id %d: wanted %v: got %v", 2, second, got)
+	}
+
+	if got := engine.GetEntityByID(3); got != nil {
+		t.Errorf("entity with missing id %d: wanted nil: got %v", 3, got)
+	}
+
+	engine.RemoveEntity(second)
+	engine.Advance(0.1)
+
+	if got := engine.GetEntityByID(2); got != nil {
+		t.Errorf("removed entity with id %d: wanted nil: got %v", 2, got)
+	}
+
+	if got := engine.GetEntityByID(1); got != first {
+		t.Errorf("entity with id %d after a removal: wanted %v: got %v", 1, first, got)
+	}
+}
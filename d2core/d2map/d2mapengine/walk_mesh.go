@@ -1,6 +1,8 @@
 package d2mapengine
 
 import (
+	"context"
+	"errors"
 	"math"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
@@ -8,8 +10,14 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 )
 
+// ErrPathNodeNotFound is returned when a path end point is outside the walk mesh.
+var ErrPathNodeNotFound = errors.New("path end point is outside the walk mesh")
+
 // RegenerateWalkPaths based on current tile data.
 func (m *MapEngine) RegenerateWalkPaths() {
+	// Invalidates any cached paths
+	m.walkVersion++
+
 	for subTileY := 0; subTileY < m.size.Height*5; subTileY++ {
 		tileY := int(float64(subTileY) / 5.0)
 
@@ -54,77 +62,135 @@ func (m *MapEngine) RegenerateWalkPaths() {
 				X:        float64(subTileX) / 5.0,
 				Y:        float64(subTileY) / 5.0,
 			}
+		}
+	}
 
-			ySkew := m.size.Width * 5
-			if !isBlocked && subTileY > 0 && m.walkMesh[index-ySkew].Walkable {
-				m.walkMesh[index].Up = &m.walkMesh[index-ySkew]
-				m.walkMesh[index-ySkew].Down = &m.walkMesh[index]
-			}
+	d2common.LinkPathTiles(m.walkMesh, m.size.Width*5, m.allowDiagonal)
+}
 
-			if !isBlocked && subTileX > 0 && m.walkMesh[index-1].Walkable {
-				m.walkMesh[index].Left = &m.walkMesh[index-1]
-				m.walkMesh[index-1].Right = &m.walkMesh[index]
-			}
+// SetAllowDiagonal sets whether paths can move diagonally between sub-tiles. Diagonal moves never cut across the
+// corner of a blocked sub-tile. Takes effect when the walk paths are next regenerated.
+func (m *MapEngine) SetAllowDiagonal(allowDiagonal bool) {
+	m.allowDiagonal = allowDiagonal
+}
 
-			if !isBlocked && subTileX > 0 && subTileY > 0 && m.walkMesh[(index-ySkew)-1].Walkable {
-				m.walkMesh[index].UpLeft = &m.walkMesh[(index-ySkew)-1]
-				m.walkMesh[(index-ySkew)-1].DownRight = &m.walkMesh[index]
-			}
+// IsTileWalkable returns true if any sub-tile of the given tile can be walked on.
+func (m *MapEngine) IsTileWalkable(tileX, tileY int) bool {
+	if tileX < 0 || tileY < 0 || tileX >= m.size.Width || tileY >= m.size.Height {
+		return false
+	}
 
-			if !isBlocked && subTileY > 0 && subTileX < (m.size.Width*5)-1 && m.walkMesh[(index-ySkew)+1].Walkable {
-				m.walkMesh[index].UpRight = &m.walkMesh[(index-ySkew)+1]
-				m.walkMesh[(index-ySkew)+1].DownLeft = &m.walkMesh[index]
+	for subTileY := tileY * 5; subTileY < (tileY+1)*5; subTileY++ {
+		for subTileX := tileX * 5; subTileX < (tileX+1)*5; subTileX++ {
+			index := subTileX + (subTileY * m.size.Width * 5)
+			if index < len(m.walkMesh) && m.walkMesh[index].Walkable {
+				return true
 			}
 		}
 	}
+
+	return false
 }
 
-// PathFind finds a walkable path between two points.
+// defaultMaxPathCost is the maximum cost of paths found by PathFind.
+const defaultMaxPathCost = 80
+
+// PathFind finds a walkable path between two points. Recently found paths are
+// cached until the walk mesh changes.
 func (m *MapEngine) PathFind(startX, startY, endX, endY float64) (path []d2astar.Pather, distance float64, found bool) {
-	startTileX := int(math.Floor(startX))
-	startTileY := int(math.Floor(startY))
+	path, distance, found, _ = m.findPath(startX, startY, endX, endY,
+		d2astar.Options{MaxCost: defaultMaxPathCost}, m.pathCache)
 
-	if !m.TileExists(startTileX, startTileY) {
-		return
+	return path, distance, found
+}
+
+// PathFindWithOptions finds a walkable path between two points, using the given search options. For example, the
+// options can scale the cost of walking over particular tiles or limit the number of search iterations. If the
+// search was cut short by the iteration limit, truncated is true and path leads toward the end point.
+func (m *MapEngine) PathFindWithOptions(startX, startY, endX, endY float64,
+	opts d2astar.Options) (path []d2astar.Pather, distance float64, found, truncated bool) {
+	return m.findPath(startX, startY, endX, endY, opts, nil)
+}
+
+// FindPathAsync finds a walkable path between two points on a separate goroutine, using the given search options.
+// The result is delivered on the returned channel unless ctx is cancelled first, in which case nothing is delivered.
+// The walk mesh must not be regenerated while a search is in flight.
+func (m *MapEngine) FindPathAsync(ctx context.Context, startX, startY, endX, endY float64,
+	opts d2astar.Options) <-chan d2astar.PathResult {
+	results := make(chan d2astar.PathResult, 1)
+
+	startNode, endNode := m.pathNodes(startX, startY, endX, endY)
+	if startNode == nil || endNode == nil {
+		results <- d2astar.PathResult{Err: ErrPathNodeNotFound}
+		return results
 	}
 
-	startSubtileX := int((startX - float64(int(startX))) * 5)
-	startSubtileY := int((startY - float64(int(startY))) * 5)
-	startNodeIndex := ((startSubtileY + (startTileY * 5)) * m.size.Width * 5) + startSubtileX + ((startTileX) * 5)
+	search := d2astar.PathAsync(ctx, startNode, endNode, opts)
+
+	go func() {
+		select {
+		case result := <-search:
+			result.Path = gamePath(result.Path)
+			results <- result
+		case <-ctx.Done():
+		}
+	}()
+
+	return results
+}
 
-	if startNodeIndex < 0 || startNodeIndex >= len(m.walkMesh) {
+// findPath finds a walkable path between two points, using the cache if one is given.
+func (m *MapEngine) findPath(startX, startY, endX, endY float64, opts d2astar.Options,
+	cache *d2astar.PathCache) (path []d2astar.Pather, distance float64, found, truncated bool) {
+	startNode, endNode := m.pathNodes(startX, startY, endX, endY)
+	if startNode == nil || endNode == nil {
 		return
 	}
 
-	startNode := &m.walkMesh[startNodeIndex]
+	if cache != nil {
+		path, distance, found, truncated = cache.Path(startNode, endNode, m.walkVersion, opts)
+	} else {
+		path, distance, found, truncated = d2astar.PathWithOptions(startNode, endNode, opts)
+	}
+
+	return gamePath(path), distance, found, truncated
+}
+
+// pathNodes returns the walk mesh nodes at the start and end points, or nil if a point is outside the map.
+func (m *MapEngine) pathNodes(startX, startY, endX, endY float64) (startNode, endNode *d2common.PathTile) {
+	return m.pathNodeAt(startX, startY), m.pathNodeAt(endX, endY)
+}
 
-	endTileX := int(math.Floor(endX))
-	endTileY := int(math.Floor(endY))
+// pathNodeAt returns the walk mesh node at the given point, or nil if the point is outside the map.
+func (m *MapEngine) pathNodeAt(x, y float64) *d2common.PathTile {
+	tileX := int(math.Floor(x))
+	tileY := int(math.Floor(y))
 
-	if !m.TileExists(endTileX, endTileY) {
-		return
+	if !m.TileExists(tileX, tileY) {
+		return nil
 	}
 
-	endSubtileX := int((endX - float64(int(endX))) * 5)
-	endSubtileY := int((endY - float64(int(endY))) * 5)
+	subtileX := int((x - float64(int(x))) * 5)
+	subtileY := int((y - float64(int(y))) * 5)
+	nodeIndex := ((subtileY + (tileY * 5)) * m.size.Width * 5) + subtileX + (tileX * 5)
 
-	endNodeIndex := ((endSubtileY + (endTileY * 5)) * m.size.Width * 5) + endSubtileX + ((endTileX) * 5)
-	if endNodeIndex < 0 || endNodeIndex >= len(m.walkMesh) {
-		return
+	if nodeIndex < 0 || nodeIndex >= len(m.walkMesh) {
+		return nil
 	}
 
-	endNode := &m.walkMesh[endNodeIndex]
+	return &m.walkMesh[nodeIndex]
+}
 
-	path, distance, found = d2astar.Path(startNode, endNode, 80)
-	if path != nil {
-		// Reverse the path to fit what the game expects.
-		for i := len(path)/2 - 1; i >= 0; i-- {
-			opp := len(path) - 1 - i
-			path[i], path[opp] = path[opp], path[i]
-		}
+// gamePath converts a path found by d2astar to what the game expects, reversing it and dropping the start node.
+func gamePath(path []d2astar.Pather) []d2astar.Pather {
+	if path == nil {
+		return nil
+	}
 
-		path = path[1:]
+	for i := len(path)/2 - 1; i >= 0; i-- {
+		opp := len(path) - 1 - i
+		path[i], path[opp] = path[opp], path[i]
 	}
 
-	return
+	return path[1:]
 }
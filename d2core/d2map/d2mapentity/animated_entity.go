@@ -7,7 +7,6 @@ import (
 // AnimatedEntity represents an animation that can be projected onto the map.
 type AnimatedEntity struct {
 	mapEntity
-	direction   int
 	action      int
 	repetitions int
 
@@ -35,16 +34,9 @@ func (ae *AnimatedEntity) Render(target d2interface.Surface) {
 	ae.animation.Render(target)
 }
 
-// GetDirection returns the current facing direction of this entity.
-func (ae *AnimatedEntity) GetDirection() int {
-	return ae.direction
-}
-
 // rotate sets direction and changes animation
 func (ae *AnimatedEntity) rotate(direction int) {
-	ae.direction = direction
-
-	ae.animation.SetDirection(ae.direction)
+	ae.animation.SetDirection(direction)
 }
 
 // Advance is called once per frame and processes a
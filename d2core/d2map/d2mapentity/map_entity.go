@@ -1,7 +1,9 @@
 package d2mapentity
 
 import (
+	"errors"
 	"math"
+	"sync/atomic"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
@@ -10,45 +12,211 @@ import (
 // mapEntity represents an entity on the map that can be animated
 // TODO: Has a coordinate (issue #456)
 type mapEntity struct {
+	id                 uint64
 	LocationX          float64
 	LocationY          float64
 	TileX, TileY       int     // Coordinates of the tile the unit is within
+	footprintW         int     // Number of tiles the unit occupies along the x axis
+	footprintH         int     // Number of tiles the unit occupies along the y axis
 	subcellX, subcellY float64 // Subcell coordinates within the current tile
 	offsetX, offsetY   int
 	TargetX            float64
 	TargetY            float64
 	Speed              float64
+	walkSpeed          float64
+	runSpeed           float64
+	speedModifiers     map[string]float64 // Speed multipliers of active effects, by effect ID
+	isRunning          bool
 	path               []d2astar.Pather
 	drawLayer          int
+	direction          int
+	highlighted        bool
+	desiredDirection   int
+	facing             float64 // Fractional facing direction while turning
+	turnRate           float64 // Directions per second, 0 turns instantly
 
 	done        func()
 	directioner func(direction int)
+
+	arrivalThreshold float64
+
+	nameKey      string
+	nameResolver func(key string) string
+
+	collisionChecker func(tileX, tileY int) bool
+	onBlocked        func()
+	onTileEnter      func(tileX, tileY int)
+
+	knockback *knockback
+
+	followTarget   *mapEntity
+	followStandoff float64
+
+	patrol          [][2]int
+	patrolIndex     int
+	patrolDirection int
+	patrolLoop      bool
+
+	networkStates     []networkState
+	networkClock      float64 // Server time, in seconds, the entity is currently rendered at
+	locallyControlled bool
+
+	predictedMoves     []predictedMove
+	predictionSequence uint64
+	onPredictedMove    func(sequence uint64, dx, dy float64)
+
+	removalPending bool
+	onRemove       func()
+
+	onCancel func()
+}
+
+// mapEntityProvider is implemented by every entity embedding a mapEntity.
+type mapEntityProvider interface {
+	getMapEntity() *mapEntity
+}
+
+// knockback is a forced displacement which overrides the regular movement of an entity.
+type knockback struct {
+	dx, dy   float64
+	duration float64
+	elapsed  float64
+	wasIdle  bool
+}
+
+// progress returns the fraction of the displacement covered so far. The entity decelerates over the duration.
+func (k *knockback) progress() float64 {
+	if k.duration <= 0 || k.elapsed >= k.duration {
+		return 1
+	}
+
+	remaining := 1 - (k.elapsed / k.duration)
+
+	return 1 - (remaining * remaining)
+}
+
+// networkState is an authoritative entity position received from the server.
+type networkState struct {
+	x, y float64
+	tick float64
+}
+
+// MovementSnapshot is a copy of the movement state of an entity, taken at one point in time.
+type MovementSnapshot struct {
+	LocationX, LocationY float64 // Location, in sub tiles
+	TargetX, TargetY     float64 // Location the entity is moving to, in sub tiles
+	TileX, TileY         int     // Tile the entity is within
+	Speed                float64 // Movement speed, before speed modifiers are applied
+	EffectiveSpeed       float64 // Movement speed the entity moves at
+	Direction            int
+	PathLength           int // Number of path nodes left after the current target
+	AtTarget             bool
 }
 
+// EntityPosition is the saved position of an entity. The path being walked and its done() callback aren't part of
+// it, a restored entity is at rest.
+type EntityPosition struct {
+	LocationX float64 `json:"locationX"`
+	LocationY float64 `json:"locationY"`
+	TileX     int     `json:"tileX"`
+	TileY     int     `json:"tileY"`
+	Direction int     `json:"direction"`
+}
+
+// predictedMove is a movement made by a locally controlled entity which the server has not acknowledged yet.
+type predictedMove struct {
+	sequence uint64
+	dx, dy   float64
+}
+
+const (
+	// baseSpeed is the default movement speed of an entity, used for both walking and running.
+	baseSpeed = 6.0
+
+	// defaultArrivalThreshold is the default distance from the target at which an entity is considered to have
+	// arrived.
+	defaultArrivalThreshold = 0.0001
+
+	// drawLayerStride separates draw layers in DrawOrderKey, it must be larger than any map location.
+	drawLayerStride = 1 << 20
+
+	// pathNodeThreshold is the minimum distance from a path node at which the node is considered reached.
+	pathNodeThreshold = 0.01
+
+	// networkRenderDelay is how far, in seconds, remote entities are rendered behind the latest server state, so
+	// there is usually a newer state to interpolate toward.
+	networkRenderDelay = 0.1
+
+	// maxNetworkStates is the number of server states buffered per entity, older states are dropped first.
+	maxNetworkStates = 32
+
+	// maxSubstepTime is the longest time, in seconds, an entity moves for in one go. It is about a frame at the
+	// original game's 25 frames per second.
+	maxSubstepTime = 0.04
+
+	// nearestWalkableRadius is the furthest, in tiles, NearestWalkable searches for a walkable tile.
+	nearestWalkableRadius = 8
+
+	// minSpeedMultiplier is the lowest combined speed modifier, so an entity which is slowed repeatedly still moves.
+	minSpeedMultiplier = 0.1
+
+	// maxPredictedMoves is the number of unacknowledged moves kept for reconciliation, older moves are dropped first.
+	maxPredictedMoves = 128
+)
+
+// lastEntityID is the most recently assigned entity ID.
+var lastEntityID uint64 //nolint:gochecknoglobals // IDs must be unique across all entities in a session
+
+// ErrInvalidArrivalThreshold is returned when setting an arrival threshold which is not positive.
+var ErrInvalidArrivalThreshold = errors.New("arrival threshold must be positive")
+
 // createMapEntity creates an instance of mapEntity
 func createMapEntity(x, y int) mapEntity {
 	locX, locY := float64(x), float64(y)
 
 	return mapEntity{
-		LocationX: locX,
-		LocationY: locY,
-		TargetX:   locX,
-		TargetY:   locY,
-		TileX:     x / 5,
-		TileY:     y / 5,
-		subcellX:  1 + math.Mod(locX, 5),
-		subcellY:  1 + math.Mod(locY, 5),
-		Speed:     6,
-		drawLayer: 0,
-		path:      []d2astar.Pather{},
+		id:         atomic.AddUint64(&lastEntityID, 1),
+		LocationX:  locX,
+		LocationY:  locY,
+		TargetX:    locX,
+		TargetY:    locY,
+		TileX:      x / 5,
+		TileY:      y / 5,
+		footprintW: 1,
+		footprintH: 1,
+		subcellX:   1 + math.Mod(locX, 5),
+		subcellY:   1 + math.Mod(locY, 5),
+		Speed:      baseSpeed,
+		walkSpeed:  baseSpeed,
+		runSpeed:   baseSpeed,
+		drawLayer:  0,
+		path:       []d2astar.Pather{},
+
+		arrivalThreshold: defaultArrivalThreshold,
 	}
 }
 
+// ID returns the unique identifier of the entity. It never changes during the lifetime of the entity.
+func (m *mapEntity) ID() uint64 {
+	return m.id
+}
+
+// getMapEntity returns the underlying mapEntity.
+func (m *mapEntity) getMapEntity() *mapEntity {
+	return m
+}
+
 // GetLayer returns the draw layer for this entity.
 func (m *mapEntity) GetLayer() int {
 	return m.drawLayer
 }
 
+// DrawOrderKey returns a value which can be used to sort entities into drawing order. Entities are sorted by draw
+// layer first, then by their Y location so that entities further down the map are drawn on top.
+func (m *mapEntity) DrawOrderKey() float64 {
+	return float64(m.drawLayer)*drawLayerStride + m.LocationY
+}
+
 // SetPath sets the entity movement path. done() is called when the entity reaches it's path destination. For example,
 // when the player entity reaches the point a player clicked.
 func (m *mapEntity) SetPath(path []d2astar.Pather, done func()) {
@@ -56,45 +224,212 @@ func (m *mapEntity) SetPath(path []d2astar.Pather, done func()) {
 	m.done = done
 }
 
-// ClearPath clears the entity movement path.
+// ClearPath clears the entity movement path and any patrol.
 func (m *mapEntity) ClearPath() {
 	m.path = nil
+	m.patrol = nil
+}
+
+// SetPatrol makes the entity walk between the given points, in sub-tiles, forever. When the last point is reached
+// the entity jumps back to the first point if loop is true, otherwise it walks the points in reverse. A patrol takes
+// precedence over any path set with SetPath until ClearPath is called, and never calls done().
+func (m *mapEntity) SetPatrol(points [][2]int, loop bool) {
+	if len(points) == 0 {
+		m.patrol = nil
+		return
+	}
+
+	m.patrol = points
+	m.patrolIndex = 0
+	m.patrolDirection = 1
+	m.patrolLoop = loop
+
+	m.SetTarget(float64(points[0][0]), float64(points[0][1]), nil)
+}
+
+// IsPatrolling returns true if the entity is walking a patrol.
+func (m *mapEntity) IsPatrolling() bool {
+	return len(m.patrol) > 1
 }
 
-// SetSpeed sets the entity movement speed.
+// nextPatrolPoint targets the next point of the patrol.
+func (m *mapEntity) nextPatrolPoint() {
+	next := m.patrolIndex + m.patrolDirection
+
+	if next < 0 || next >= len(m.patrol) {
+		if m.patrolLoop {
+			next = 0
+		} else {
+			m.patrolDirection = -m.patrolDirection
+			next = m.patrolIndex + m.patrolDirection
+		}
+	}
+
+	m.patrolIndex = next
+	point := m.patrol[m.patrolIndex]
+
+	m.SetTarget(float64(point[0]), float64(point[1]), nil)
+}
+
+// SetSpeed sets the entity movement speed, before speed modifiers are applied.
 func (m *mapEntity) SetSpeed(speed float64) {
 	m.Speed = speed
 }
 
-// GetSpeed returns the entity movement speed.
+// GetSpeed returns the entity movement speed, before speed modifiers are applied.
 func (m *mapEntity) GetSpeed() float64 {
 	return m.Speed
 }
 
-func (m *mapEntity) getStepLength(tickTime float64) (float64, float64) {
-	length := tickTime * m.Speed
+// AddSpeedModifier applies a movement speed multiplier, such as 0.5 for a cold effect slowing the entity or 1.5 for
+// a haste skill. Modifiers stack by multiplying, the id identifies the modifier to remove it, adding a modifier with
+// the id of an active one replaces it.
+func (m *mapEntity) AddSpeedModifier(id string, multiplier float64) {
+	if m.speedModifiers == nil {
+		m.speedModifiers = make(map[string]float64)
+	}
+
+	m.speedModifiers[id] = multiplier
+}
+
+// RemoveSpeedModifier removes the movement speed multiplier with the given id.
+func (m *mapEntity) RemoveSpeedModifier(id string) {
+	delete(m.speedModifiers, id)
+}
+
+// GetEffectiveSpeed returns the movement speed with every speed modifier applied, which is the speed the entity
+// moves at. The combined multiplier is clamped so the entity never moves slower than a tenth of its speed.
+func (m *mapEntity) GetEffectiveSpeed() float64 {
+	multiplier := 1.0
+
+	for _, modifier := range m.speedModifiers {
+		multiplier *= modifier
+	}
+
+	return m.Speed * math.Max(multiplier, minSpeedMultiplier)
+}
+
+// SetWalkSpeed sets the movement speed used while the entity is walking.
+func (m *mapEntity) SetWalkSpeed(speed float64) {
+	m.walkSpeed = speed
+	m.updateGaitSpeed()
+}
+
+// SetRunSpeed sets the movement speed used while the entity is running.
+func (m *mapEntity) SetRunSpeed(speed float64) {
+	m.runSpeed = speed
+	m.updateGaitSpeed()
+}
+
+// SetRunning switches the entity between walking and running. If the entity is moving, the directioner is called
+// again so the walk or run animation can be selected.
+func (m *mapEntity) SetRunning(isRunning bool) {
+	if m.isRunning == isRunning {
+		return
+	}
 
-	angle := 359 - d2common.GetAngleBetween(
-		m.LocationX,
-		m.LocationY,
-		m.TargetX,
-		m.TargetY,
-	)
-	radians := (math.Pi / 180.0) * float64(angle)
-	oneStepX := length * math.Cos(radians)
-	oneStepY := length * math.Sin(radians)
+	m.isRunning = isRunning
+	m.updateGaitSpeed()
+
+	if !m.IsAtTarget() {
+		m.SetDirection(m.directionTo(m.TargetX, m.TargetY))
+	}
+}
+
+// IsRunning returns true if the entity is running rather than walking.
+func (m *mapEntity) IsRunning() bool {
+	return m.isRunning
+}
+
+// updateGaitSpeed sets the movement speed to the speed of the active gait.
+func (m *mapEntity) updateGaitSpeed() {
+	if m.isRunning {
+		m.Speed = m.runSpeed
+	} else {
+		m.Speed = m.walkSpeed
+	}
+}
+
+// getStepLength returns the x and y components of a step of the given length
+// toward the target. The components are derived from the normalized direction
+// vector so the distance covered is always the given length, regardless of the
+// direction of travel.
+func (m *mapEntity) getStepLength(length float64) (float64, float64) {
+	deltaX := m.TargetX - m.LocationX
+	deltaY := m.TargetY - m.LocationY
+
+	distance := math.Hypot(deltaX, deltaY)
+	if distance == 0 {
+		return 0, 0
+	}
+
+	oneStepX := length * (deltaX / distance)
+	oneStepY := length * (deltaY / distance)
 
 	return oneStepX, oneStepY
 }
 
-// IsAtTarget returns true if the entity is within a 0.0002 square of it's target and has a path.
+// IsAtTarget returns true if the entity is within the arrival threshold of it's target and has no path.
 func (m *mapEntity) IsAtTarget() bool {
-	return math.Abs(m.LocationX-m.TargetX) < 0.0001 && math.Abs(m.LocationY-m.TargetY) < 0.0001 && !m.HasPathFinding()
+	return math.Abs(m.LocationX-m.TargetX) < m.arrivalThreshold &&
+		math.Abs(m.LocationY-m.TargetY) < m.arrivalThreshold &&
+		!m.HasPathFinding()
+}
+
+// SetArrivalThreshold sets the distance from the target at which the entity is considered to have arrived. Slow,
+// large entities can use a looser threshold to avoid oscillating around their destination.
+func (m *mapEntity) SetArrivalThreshold(epsilon float64) error {
+	if epsilon <= 0 {
+		return ErrInvalidArrivalThreshold
+	}
+
+	m.arrivalThreshold = epsilon
+
+	return nil
 }
 
 // Step moves the entity along it's path by one tick. If the path is complete it calls entity.done() then returns.
+// Long ticks are split into substeps of at most maxSubstepTime, so the entity moves the same way whatever the frame
+// rate of the game loop.
 func (m *mapEntity) Step(tickTime float64) {
-	if m.IsAtTarget() {
+	if m.removalPending {
+		return
+	}
+
+	if m.locallyControlled {
+		fromX, fromY := m.LocationX, m.LocationY
+		defer m.recordPredictedMove(fromX, fromY)
+	}
+
+	for tickTime > maxSubstepTime && !m.removalPending {
+		m.substep(maxSubstepTime)
+		tickTime -= maxSubstepTime
+	}
+
+	if !m.removalPending {
+		m.substep(tickTime)
+	}
+}
+
+// substep moves the entity along it's path for a time no longer than maxSubstepTime.
+func (m *mapEntity) substep(tickTime float64) {
+	m.stepRotation(tickTime)
+
+	if len(m.networkStates) > 0 {
+		m.stepNetwork(tickTime)
+		return
+	}
+
+	if m.knockback != nil {
+		m.stepKnockback(tickTime)
+		return
+	}
+
+	if m.followTarget != nil {
+		m.updateFollow()
+	}
+
+	if m.IsAtTarget() && !m.IsPatrolling() {
 		if m.done != nil {
 			m.done()
 			m.done = nil
@@ -103,48 +438,529 @@ func (m *mapEntity) Step(tickTime float64) {
 		return
 	}
 
-	stepX, stepY := m.getStepLength(tickTime)
+	// Bounds the number of waypoints a patrol can advance through in a single tick
+	patrolAdvances := 0
+
+	// The distance left to travel this tick. When a path node is reached part way through the tick, the step
+	// vector is recomputed toward the next node so the leftover distance is travelled in the new direction.
+	remaining := tickTime * m.GetEffectiveSpeed()
+
+	nodeThreshold := math.Max(pathNodeThreshold, m.arrivalThreshold)
 
 	for {
-		if d2common.AlmostEqual(m.LocationX-m.TargetX, 0, 0.0001) {
+		if d2common.AlmostEqual(m.LocationX, m.TargetX, nodeThreshold) &&
+			d2common.AlmostEqual(m.LocationY, m.TargetY, nodeThreshold) {
+			if m.IsPatrolling() && patrolAdvances < len(m.patrol) {
+				m.nextPatrolPoint()
+				patrolAdvances++
+
+				continue
+			}
+
+			if len(m.path) == 0 {
+				m.setLocation(m.TargetX, m.TargetY)
+				break
+			}
+
+			m.SetTarget(m.path[0].(*d2common.PathTile).X*5, m.path[0].(*d2common.PathTile).Y*5, m.done)
+
+			if len(m.path) > 1 {
+				m.path = m.path[1:]
+			} else {
+				m.path = []d2astar.Pather{}
+			}
+
+			continue
+		}
+
+		if remaining <= 0 {
+			break
+		}
+
+		stepX, stepY := m.getStepLength(remaining)
+
+		if d2common.AlmostEqual(m.LocationX-m.TargetX, 0, m.arrivalThreshold) {
 			stepX = 0
 		}
 
-		if d2common.AlmostEqual(m.LocationY-m.TargetY, 0, 0.0001) {
+		if d2common.AlmostEqual(m.LocationY-m.TargetY, 0, m.arrivalThreshold) {
 			stepY = 0
 		}
 
-		m.LocationX, stepX = d2common.AdjustWithRemainder(m.LocationX, stepX, m.TargetX)
-		m.LocationY, stepY = d2common.AdjustWithRemainder(m.LocationY, stepY, m.TargetY)
+		newX, remainderX := d2common.AdjustWithRemainder(m.LocationX, stepX, m.TargetX)
+		newY, remainderY := d2common.AdjustWithRemainder(m.LocationY, stepY, m.TargetY)
 
-		m.subcellX = 1 + math.Mod(m.LocationX, 5)
-		m.subcellY = 1 + math.Mod(m.LocationY, 5)
-		m.TileX = int(m.LocationX / 5)
-		m.TileY = int(m.LocationY / 5)
+		if m.isBlocked(newX, newY) {
+			m.stopBlocked()
+			return
+		}
 
-		if d2common.AlmostEqual(m.LocationX, m.TargetX, 0.01) && d2common.AlmostEqual(m.LocationY, m.TargetY, 0.01) {
-			if len(m.path) > 0 {
-				m.SetTarget(m.path[0].(*d2common.PathTile).X*5, m.path[0].(*d2common.PathTile).Y*5, m.done)
+		m.setLocation(newX, newY)
 
-				if len(m.path) > 1 {
-					m.path = m.path[1:]
-				} else {
-					m.path = []d2astar.Pather{}
-				}
-			} else {
-				m.LocationX = m.TargetX
-				m.LocationY = m.TargetY
-				m.subcellX = 1 + math.Mod(m.LocationX, 5)
-				m.subcellY = 1 + math.Mod(m.LocationY, 5)
-				m.TileX = int(m.LocationX / 5)
-				m.TileY = int(m.LocationY / 5)
-			}
+		remaining = math.Hypot(remainderX, remainderY)
+	}
+}
+
+// setLocation moves the entity to the given location and updates the tile and subcell coordinates. The tile enter
+// callback is fired if the entity moved into a different tile.
+func (m *mapEntity) setLocation(x, y float64) {
+	previousTileX, previousTileY := m.TileX, m.TileY
+
+	m.LocationX = x
+	m.LocationY = y
+	m.subcellX = 1 + math.Mod(m.LocationX, 5)
+	m.subcellY = 1 + math.Mod(m.LocationY, 5)
+	m.TileX = int(m.LocationX / 5)
+	m.TileY = int(m.LocationY / 5)
+
+	if m.onTileEnter != nil && (m.TileX != previousTileX || m.TileY != previousTileY) {
+		m.onTileEnter(m.TileX, m.TileY)
+	}
+}
+
+// SetOnTileEnter sets the function called whenever the entity moves into a new tile.
+func (m *mapEntity) SetOnTileEnter(onTileEnter func(tileX, tileY int)) {
+	m.onTileEnter = onTileEnter
+}
+
+// SetFollowTarget makes the entity follow the given entity, staying within standoff sub-tiles of it. Any current
+// path is discarded. A nil leader stops following.
+func (m *mapEntity) SetFollowTarget(leader mapEntityProvider, standoff float64) {
+	if leader == nil {
+		m.ClearFollowTarget()
+		return
+	}
+
+	m.followTarget = leader.getMapEntity()
+	m.followStandoff = standoff
+	m.ClearPath()
+}
+
+// ClearFollowTarget stops the entity from following another entity. The entity comes to rest where it is.
+func (m *mapEntity) ClearFollowTarget() {
+	if m.followTarget == nil {
+		return
+	}
+
+	m.followTarget = nil
+	m.holdPosition()
+}
+
+// updateFollow moves the target to within standoff of the followed entity, or holds position if it is close enough.
+// A followed entity which is being removed from the map is dropped, and the entity holds position.
+func (m *mapEntity) updateFollow() {
+	leader := m.followTarget
+
+	if leader.ShouldRemove() {
+		m.ClearFollowTarget()
+		return
+	}
+
+	deltaX := leader.LocationX - m.LocationX
+	deltaY := leader.LocationY - m.LocationY
+	distance := math.Hypot(deltaX, deltaY)
+
+	if distance <= m.followStandoff {
+		m.holdPosition()
+		return
+	}
+
+	ratio := (distance - m.followStandoff) / distance
+	targetX := m.LocationX + (deltaX * ratio)
+	targetY := m.LocationY + (deltaY * ratio)
+
+	if d2common.AlmostEqual(targetX, m.TargetX, pathNodeThreshold) &&
+		d2common.AlmostEqual(targetY, m.TargetY, pathNodeThreshold) {
+		return
+	}
+
+	m.SetTarget(targetX, targetY, m.done)
+}
+
+// StopMovement interrupts the movement of the entity, such as when the player gives a new order mid-walk or the
+// entity is stunned. The entity stops where it is, its path and patrol are discarded, and the cancel callback is
+// called instead of done(), which is never called for the interrupted movement. An entity which has already arrived
+// isn't interrupted.
+func (m *mapEntity) StopMovement() {
+	if m.IsAtTarget() && !m.IsPatrolling() {
+		return
+	}
+
+	m.done = nil
+	m.TargetX, m.TargetY = m.LocationX, m.LocationY
+	m.ClearPath()
+	m.SetDirection(m.direction)
+
+	if m.onCancel != nil {
+		m.onCancel()
+	}
+}
+
+// SetOnCancel sets the function called when the movement of the entity is interrupted by StopMovement.
+func (m *mapEntity) SetOnCancel(onCancel func()) {
+	m.onCancel = onCancel
+}
+
+// holdPosition stops the entity where it currently is, refreshing its animation if it was moving.
+func (m *mapEntity) holdPosition() {
+	if m.IsAtTarget() {
+		return
+	}
+
+	m.TargetX, m.TargetY = m.LocationX, m.LocationY
+	m.ClearPath()
+	m.SetDirection(m.direction)
+}
+
+// Teleport instantly moves the entity to the given location. Any path, pending done() callback and knockback are
+// discarded and the entity is left at rest on the new location.
+func (m *mapEntity) Teleport(x, y int) {
+	m.setLocation(float64(x), float64(y))
+	m.TargetX, m.TargetY = m.LocationX, m.LocationY
+	m.ClearPath()
+	m.done = nil
+	m.knockback = nil
+	m.networkStates = nil
+	m.predictedMoves = nil
+}
+
+// MarkForRemoval schedules the entity to be removed from the map, for example when a monster dies or a missile
+// expires. The entity stops moving at once and is removed the next time the map engine sweeps its entities.
+func (m *mapEntity) MarkForRemoval() {
+	m.removalPending = true
+}
+
+// ShouldRemove returns true if the entity is marked for removal.
+func (m *mapEntity) ShouldRemove() bool {
+	return m.removalPending
+}
+
+// SetOnRemove sets the function called to clean up after the entity, such as stopping its sounds, when it is removed
+// from the map.
+func (m *mapEntity) SetOnRemove(onRemove func()) {
+	m.onRemove = onRemove
+}
+
+// Cleanup calls the cleanup function set with SetOnRemove. It is called by the map engine when the entity is removed,
+// the cleanup function only ever runs once.
+func (m *mapEntity) Cleanup() {
+	if m.onRemove == nil {
+		return
+	}
+
+	onRemove := m.onRemove
+	m.onRemove = nil
+	onRemove()
+}
+
+// Snapshot returns a copy of the movement state of the entity, so consumers such as debug overlays and the network
+// layer get a consistent view in one call rather than reading the fields one at a time.
+func (m *mapEntity) Snapshot() MovementSnapshot {
+	return MovementSnapshot{
+		LocationX:      m.LocationX,
+		LocationY:      m.LocationY,
+		TargetX:        m.TargetX,
+		TargetY:        m.TargetY,
+		TileX:          m.TileX,
+		TileY:          m.TileY,
+		Speed:          m.Speed,
+		EffectiveSpeed: m.GetEffectiveSpeed(),
+		Direction:      m.direction,
+		PathLength:     len(m.path),
+		AtTarget:       m.IsAtTarget(),
+	}
+}
+
+// SavePosition returns the position and facing of the entity, to be stored in a save file. Movement in progress is
+// transient and isn't saved.
+func (m *mapEntity) SavePosition() EntityPosition {
+	return EntityPosition{
+		LocationX: m.LocationX,
+		LocationY: m.LocationY,
+		TileX:     m.TileX,
+		TileY:     m.TileY,
+		Direction: m.direction,
+	}
+}
+
+// RestorePosition teleports the entity to a saved position and turns it to the saved direction at once, regardless
+// of its turn rate. Locations are rounded to the nearest sub tile.
+func (m *mapEntity) RestorePosition(position EntityPosition) {
+	m.Teleport(int(math.Round(position.LocationX)), int(math.Round(position.LocationY)))
+
+	turnRate := m.turnRate
+	m.turnRate = 0
+	m.SetDirection(position.Direction)
+	m.turnRate = turnRate
+}
+
+// SetLocallyControlled sets whether the entity is moved by this client, like the local player. Locally controlled
+// entities ignore positions pushed with PushNetworkState.
+func (m *mapEntity) SetLocallyControlled(locallyControlled bool) {
+	m.locallyControlled = locallyControlled
+
+	if locallyControlled {
+		m.networkStates = nil
+	}
+}
+
+// IsLocallyControlled returns true if the entity is moved by this client.
+func (m *mapEntity) IsLocallyControlled() bool {
+	return m.locallyControlled
+}
+
+// PushNetworkState queues an authoritative position, in sub-tiles, sent by the server at the given server time in
+// seconds. Step interpolates the entity between the queued positions, networkRenderDelay seconds behind the newest
+// one, instead of snapping to each position as it arrives. States older than the newest queued state are ignored.
+func (m *mapEntity) PushNetworkState(x, y, tick float64) {
+	if m.locallyControlled {
+		return
+	}
+
+	if len(m.networkStates) == 0 {
+		// Start from where the entity is now, reaching the new position after the render delay
+		m.networkClock = tick - networkRenderDelay
+		m.networkStates = append(m.networkStates, networkState{x: m.LocationX, y: m.LocationY, tick: m.networkClock})
+	} else if tick <= m.networkStates[len(m.networkStates)-1].tick {
+		return
+	}
+
+	m.networkStates = append(m.networkStates, networkState{x: x, y: y, tick: tick})
+
+	if len(m.networkStates) > maxNetworkStates {
+		m.networkStates = m.networkStates[len(m.networkStates)-maxNetworkStates:]
+	}
+}
+
+// SetOnPredictedMove sets the function called with every move a locally controlled entity makes, so the networking
+// layer can send it to the server. The server acknowledges moves by their sequence number in ReconcileNetworkState.
+func (m *mapEntity) SetOnPredictedMove(onPredictedMove func(sequence uint64, dx, dy float64)) {
+	m.onPredictedMove = onPredictedMove
+}
+
+// recordPredictedMove stores the move made since the entity was at the given location, if it moved at all.
+func (m *mapEntity) recordPredictedMove(fromX, fromY float64) {
+	dx, dy := m.LocationX-fromX, m.LocationY-fromY
+	if dx == 0 && dy == 0 {
+		return
+	}
+
+	m.predictionSequence++
+	m.predictedMoves = append(m.predictedMoves, predictedMove{sequence: m.predictionSequence, dx: dx, dy: dy})
+
+	if len(m.predictedMoves) > maxPredictedMoves {
+		m.predictedMoves = m.predictedMoves[len(m.predictedMoves)-maxPredictedMoves:]
+	}
+
+	if m.onPredictedMove != nil {
+		m.onPredictedMove(m.predictionSequence, dx, dy)
+	}
+}
+
+// ReconcileNetworkState corrects a locally controlled entity with its authoritative position, in sub-tiles, after
+// the server applied every move up to and including sequence. The moves the server has not applied yet are replayed
+// from that position, skipping any which are now blocked.
+func (m *mapEntity) ReconcileNetworkState(sequence uint64, x, y float64) {
+	acknowledged := 0
+	for acknowledged < len(m.predictedMoves) && m.predictedMoves[acknowledged].sequence <= sequence {
+		acknowledged++
+	}
+
+	m.predictedMoves = m.predictedMoves[acknowledged:]
+
+	m.setLocation(x, y)
+
+	for _, move := range m.predictedMoves {
+		nextX, nextY := m.LocationX+move.dx, m.LocationY+move.dy
+
+		if !m.isBlocked(nextX, nextY) {
+			m.setLocation(nextX, nextY)
 		}
+	}
+}
 
-		if stepX == 0 && stepY == 0 {
-			break
+// stepNetwork moves the entity by one tick along the buffered server states. Once the last state is reached the
+// buffer is emptied and the entity comes to rest there.
+func (m *mapEntity) stepNetwork(tickTime float64) {
+	m.networkClock += tickTime
+
+	for len(m.networkStates) > 1 && m.networkStates[1].tick <= m.networkClock {
+		m.networkStates = m.networkStates[1:]
+	}
+
+	if len(m.networkStates) == 1 {
+		last := m.networkStates[0]
+		m.networkStates = nil
+
+		m.setLocation(last.x, last.y)
+		m.TargetX, m.TargetY = m.LocationX, m.LocationY
+
+		return
+	}
+
+	from, to := m.networkStates[0], m.networkStates[1]
+	progress := math.Max(0, (m.networkClock-from.tick)/(to.tick-from.tick))
+
+	m.setLocation(from.x+(to.x-from.x)*progress, from.y+(to.y-from.y)*progress)
+	m.TargetX, m.TargetY = to.x, to.y
+}
+
+// ApplyKnockback pushes the entity along the given displacement, decelerating over duration seconds. While the
+// knockback is active it overrides regular movement; afterwards the entity resumes its previous path. The facing
+// direction is not changed.
+func (m *mapEntity) ApplyKnockback(dx, dy, duration float64) {
+	wasIdle := m.IsAtTarget()
+	if m.knockback != nil {
+		wasIdle = m.knockback.wasIdle
+	}
+
+	m.knockback = &knockback{
+		dx:       dx,
+		dy:       dy,
+		duration: duration,
+		wasIdle:  wasIdle,
+	}
+}
+
+// IsKnockedBack returns true if the entity is currently being knocked back.
+func (m *mapEntity) IsKnockedBack() bool {
+	return m.knockback != nil
+}
+
+// stepKnockback moves the entity along the active knockback by one tick. The knockback ends early if the entity
+// would enter a blocked tile.
+func (m *mapEntity) stepKnockback(tickTime float64) {
+	kb := m.knockback
+
+	previous := kb.progress()
+	kb.elapsed += tickTime
+	current := kb.progress()
+
+	newX := m.LocationX + kb.dx*(current-previous)
+	newY := m.LocationY + kb.dy*(current-previous)
+
+	if m.isBlocked(newX, newY) {
+		m.endKnockback()
+		return
+	}
+
+	m.setLocation(newX, newY)
+
+	if kb.elapsed >= kb.duration {
+		m.endKnockback()
+	}
+}
+
+// endKnockback stops the active knockback. An entity which was idle stays where it landed instead of walking back.
+func (m *mapEntity) endKnockback() {
+	if m.knockback.wasIdle {
+		m.TargetX, m.TargetY = m.LocationX, m.LocationY
+	}
+
+	m.knockback = nil
+}
+
+// SetCollisionChecker sets the function used to determine whether a tile is blocked. Step consults it before the
+// entity moves into a new tile. When nil, no collision checks are performed.
+func (m *mapEntity) SetCollisionChecker(isBlocked func(tileX, tileY int) bool) {
+	m.collisionChecker = isBlocked
+}
+
+// SetOnBlocked sets the function called when the entity is stopped by a blocked tile.
+func (m *mapEntity) SetOnBlocked(onBlocked func()) {
+	m.onBlocked = onBlocked
+}
+
+// isBlocked returns true if moving to the given location would enter a tile that the collision checker reports as
+// blocked. Tiles already occupied by the entity are never blocked.
+func (m *mapEntity) isBlocked(x, y float64) bool {
+	if m.collisionChecker == nil {
+		return false
+	}
+
+	tileX, tileY := int(x/5), int(y/5)
+	if tileX == m.TileX && tileY == m.TileY {
+		return false
+	}
+
+	for _, tile := range m.footprintAt(tileX, tileY) {
+		if m.occupiesTile(tile.X, tile.Y) {
+			continue
+		}
+
+		if m.collisionChecker(tile.X, tile.Y) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetFootprint sets the size, in tiles, of the area the entity occupies. The entity's tile is the top left corner
+// of its footprint.
+func (m *mapEntity) SetFootprint(width, height int) {
+	m.footprintW = d2common.MaxInt(width, 1)
+	m.footprintH = d2common.MaxInt(height, 1)
+}
+
+// GetFootprint returns the size, in tiles, of the area the entity occupies.
+func (m *mapEntity) GetFootprint() (width, height int) {
+	return m.footprintW, m.footprintH
+}
+
+// OccupiedTiles returns the coordinates of every tile within the entity's footprint.
+func (m *mapEntity) OccupiedTiles() []d2common.Point {
+	return m.footprintAt(m.TileX, m.TileY)
+}
+
+// occupiesTile returns true if the given tile is within the entity's footprint.
+func (m *mapEntity) occupiesTile(tileX, tileY int) bool {
+	return tileX >= m.TileX && tileX < m.TileX+m.footprintW && tileY >= m.TileY && tileY < m.TileY+m.footprintH
+}
+
+// footprintAt returns the tiles the entity would occupy if its tile was at the given coordinates.
+func (m *mapEntity) footprintAt(tileX, tileY int) []d2common.Point {
+	tiles := make([]d2common.Point, 0, m.footprintW*m.footprintH)
+
+	for y := 0; y < m.footprintH; y++ {
+		for x := 0; x < m.footprintW; x++ {
+			tiles = append(tiles, d2common.Point{X: tileX + x, Y: tileY + y})
 		}
 	}
+
+	return tiles
+}
+
+// stopBlocked halts the entity at its current location, discards the rest of its path and fires the onBlocked hook.
+// done() is never called, as the destination wasn't reached.
+func (m *mapEntity) stopBlocked() {
+	m.done = nil
+	m.TargetX, m.TargetY = m.LocationX, m.LocationY
+	m.ClearPath()
+
+	if m.onBlocked != nil {
+		m.onBlocked()
+	}
+}
+
+// GetRemainingPathDistance returns the distance the entity still has to travel, following the current target and
+// every remaining path node.
+func (m *mapEntity) GetRemainingPathDistance() float64 {
+	if m.IsAtTarget() {
+		return 0
+	}
+
+	distance := math.Hypot(m.TargetX-m.LocationX, m.TargetY-m.LocationY)
+	lastX, lastY := m.TargetX, m.TargetY
+
+	for idx := range m.path {
+		tile := m.path[idx].(*d2common.PathTile)
+		nodeX, nodeY := tile.X*5, tile.Y*5
+		distance += math.Hypot(nodeX-lastX, nodeY-lastY)
+		lastX, lastY = nodeX, nodeY
+	}
+
+	return distance
 }
 
 // HasPathFinding returns false if the length of the entity movement path is 0.
@@ -157,30 +973,127 @@ func (m *mapEntity) SetTarget(tx, ty float64, done func()) {
 	m.TargetX, m.TargetY = tx, ty
 	m.done = done
 
+	m.SetDirection(m.directionTo(tx, ty))
+}
+
+// SetDirection sets the facing direction of the entity, independent of the direction it is moving in. If the entity
+// has a turn rate, it turns toward the direction over the following steps instead.
+func (m *mapEntity) SetDirection(direction int) {
+	m.desiredDirection = direction
+
+	if m.turnRate > 0 {
+		return
+	}
+
+	m.facing = float64(direction)
+	m.direction = direction
+
 	if m.directioner != nil {
-		angle := 359 - d2common.GetAngleBetween(
-			m.LocationX,
-			m.LocationY,
-			tx,
-			ty,
-		)
-		m.directioner(angleToDirection(float64(angle)))
+		m.directioner(direction)
 	}
 }
 
-func angleToDirection(angle float64) int {
-	degreesPerDirection := 360.0 / 64.0
-	offset := 45.0 - (degreesPerDirection / 2)
+// SetTurnRate sets how many of the 64 directions the entity can turn per second. A turn rate of 0 makes the entity
+// turn instantly.
+func (m *mapEntity) SetTurnRate(directionsPerSecond float64) {
+	wasTurning := m.turnRate > 0
+	m.turnRate = directionsPerSecond
 
-	newDirection := int((angle - offset) / degreesPerDirection)
+	if m.turnRate <= 0 {
+		if wasTurning {
+			// finish any turn in progress
+			m.SetDirection(m.desiredDirection)
+		}
 
-	if newDirection >= 64 {
-		newDirection = newDirection - 64
-	} else if newDirection < 0 {
-		newDirection = 64 + newDirection
+		return
 	}
 
-	return newDirection
+	if !wasTurning {
+		m.facing = float64(m.direction)
+		m.desiredDirection = m.direction
+	}
+}
+
+// stepRotation turns the entity toward its desired direction by at most the turn rate, taking the shorter way
+// around the circle of directions.
+func (m *mapEntity) stepRotation(tickTime float64) {
+	if m.turnRate <= 0 || float64(m.desiredDirection) == m.facing {
+		return
+	}
+
+	const numDirections = 64
+
+	delta := math.Mod(float64(m.desiredDirection)-m.facing, numDirections)
+	if delta > numDirections/2 {
+		delta -= numDirections
+	} else if delta <= -numDirections/2 {
+		delta += numDirections
+	}
+
+	maxTurn := m.turnRate * tickTime
+
+	if math.Abs(delta) <= maxTurn {
+		m.facing = float64(m.desiredDirection)
+	} else {
+		m.facing = math.Mod(m.facing+math.Copysign(maxTurn, delta)+numDirections, numDirections)
+	}
+
+	direction := int(math.Round(m.facing)) % numDirections
+	if direction == m.direction {
+		return
+	}
+
+	m.direction = direction
+
+	if m.directioner != nil {
+		m.directioner(direction)
+	}
+}
+
+// GetDirection returns the current facing direction of the entity.
+func (m *mapEntity) GetDirection() int {
+	return m.direction
+}
+
+// SetTargetChecked sets the target coordinates like SetTarget, but only if the target tile is not blocked according
+// to the collision checker. Every tile of the entity's footprint must be free. It returns false, leaving the current
+// target untouched, when the target is blocked.
+func (m *mapEntity) SetTargetChecked(tx, ty float64, done func()) bool {
+	if m.collisionChecker != nil {
+		for _, tile := range m.footprintAt(int(tx/5), int(ty/5)) {
+			if m.collisionChecker(tile.X, tile.Y) {
+				return false
+			}
+		}
+	}
+
+	m.SetTarget(tx, ty, done)
+
+	return true
+}
+
+// NearestWalkable returns the tile closest to the given tile which the entity can stand on according to the
+// collision checker, such as the floor next to a wall the player clicked on. ok is false if there is no such tile
+// within a few tiles.
+func (m *mapEntity) NearestWalkable(tileX, tileY int) (nearestX, nearestY int, ok bool) {
+	return d2common.NearestWalkable(tileX, tileY, nearestWalkableRadius, func(x, y int) bool {
+		if m.collisionChecker == nil {
+			return true
+		}
+
+		for _, tile := range m.footprintAt(x, y) {
+			if m.collisionChecker(tile.X, tile.Y) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// directionTo returns the direction the entity faces when looking at the given location.
+func (m *mapEntity) directionTo(x, y float64) int {
+	return d2common.DirectionBetween(m.LocationX, m.LocationY, x, y)
 }
 
 // GetPosition returns the entity's current tile position.
@@ -193,13 +1106,35 @@ func (m *mapEntity) GetPositionF() (float64, float64) {
 	return float64(m.TileX) + (float64(m.subcellX) / 5.0), float64(m.TileY) + (float64(m.subcellY) / 5.0)
 }
 
-// Name returns the NPC's in-game name (e.g. "Deckard Cain") or an empty string if it does not have a name
+// SetNameKey sets the key used to look up the entity's in-game name, and the function which resolves it to a
+// localized name (e.g. a string table lookup).
+func (m *mapEntity) SetNameKey(key string, resolver func(key string) string) {
+	m.nameKey = key
+	m.nameResolver = resolver
+}
+
+// Name returns the entity's in-game name (e.g. "Deckard Cain") or an empty string if it does not have a name
 func (m *mapEntity) Name() string {
-	return ""
+	if m.nameKey == "" || m.nameResolver == nil {
+		return ""
+	}
+
+	return m.nameResolver(m.nameKey)
 }
 
-// Highlight is not currently implemented.
+// Highlight sets the entity highlighted flag to true, so the entity is drawn with a selection outline.
 func (m *mapEntity) Highlight() {
+	m.highlighted = true
+}
+
+// ClearHighlight sets the entity highlighted flag to false.
+func (m *mapEntity) ClearHighlight() {
+	m.highlighted = false
+}
+
+// IsHighlighted returns true if the entity is highlighted.
+func (m *mapEntity) IsHighlighted() bool {
+	return m.highlighted
 }
 
 // Selectable returns true if the object can be highlighted/selected.
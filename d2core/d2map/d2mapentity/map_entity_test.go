@@ -0,0 +1,1039 @@
+package d2mapentity
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2automap"
+)
+
+const testEpsilon = 0.0001
+
+func TestMapEntityStepDiagonalSpeed(t *testing.T) {
+	const (
+		ticks    = 10
+		tickTime = 0.1
+	)
+
+	straight := createMapEntity(0, 0)
+	straight.SetTarget(100, 0, nil)
+
+	diagonal := createMapEntity(0, 0)
+	diagonal.SetTarget(100, 100, nil)
+
+	for i := 0; i < ticks; i++ {
+		straight.Step(tickTime)
+		diagonal.Step(tickTime)
+	}
+
+	want := ticks * tickTime * straight.Speed
+	gotStraight := math.Hypot(straight.LocationX, straight.LocationY)
+	gotDiagonal := math.Hypot(diagonal.LocationX, diagonal.LocationY)
+
+	if math.Abs(gotStraight-want) > testEpsilon {
+		t.Errorf("straight travel distance: wanted %.4f: got %.4f", want, gotStraight)
+	}
+
+	if math.Abs(gotDiagonal-want) > testEpsilon {
+		t.Errorf("diagonal travel distance: wanted %.4f: got %.4f", want, gotDiagonal)
+	}
+}
+
+func TestMapEntityStepBlocked(t *testing.T) {
+	entity := createMapEntity(2, 2)
+
+	blockedCount := 0
+	doneCount := 0
+
+	entity.SetCollisionChecker(func(tileX, tileY int) bool {
+		return tileX == 2
+	})
+	entity.SetOnBlocked(func() {
+		blockedCount++
+	})
+	entity.SetTarget(22, 2, func() {
+		doneCount++
+	})
+
+	for i := 0; i < 100; i++ {
+		entity.Step(0.1)
+	}
+
+	if entity.TileX != 1 {
+		t.Errorf("entity tile x: wanted %d: got %d", 1, entity.TileX)
+	}
+
+	if blockedCount != 1 {
+		t.Errorf("onBlocked calls: wanted %d: got %d", 1, blockedCount)
+	}
+
+	if !entity.IsAtTarget() {
+		t.Error("entity should be at rest after being blocked")
+	}
+
+	if doneCount != 0 {
+		t.Errorf("done calls after being blocked: wanted %d: got %d", 0, doneCount)
+	}
+}
+
+func TestMapEntityWalkRunSpeed(t *testing.T) {
+	entity := createMapEntity(0, 0)
+
+	if entity.GetSpeed() != baseSpeed {
+		t.Errorf("default speed: wanted %.2f: got %.2f", baseSpeed, entity.GetSpeed())
+	}
+
+	entity.SetWalkSpeed(4)
+	entity.SetRunSpeed(9)
+
+	if entity.GetSpeed() != 4 {
+		t.Errorf("walk speed: wanted %.2f: got %.2f", 4.0, entity.GetSpeed())
+	}
+
+	entity.SetRunning(true)
+
+	if !entity.IsRunning() || entity.GetSpeed() != 9 {
+		t.Errorf("run speed: wanted %.2f: got %.2f", 9.0, entity.GetSpeed())
+	}
+}
+
+func TestMapEntitySpeedModifiers(t *testing.T) {
+	entity := createMapEntity(0, 0)
+	entity.SetSpeed(10)
+
+	entity.AddSpeedModifier("cold", 0.5)
+	entity.AddSpeedModifier("vigor", 1.5)
+
+	if got := entity.GetEffectiveSpeed(); math.Abs(got-7.5) > testEpsilon {
+		t.Errorf("slowed and hasted speed: wanted %.2f: got %.2f", 7.5, got)
+	}
+
+	entity.SetTarget(100, 0, nil)
+	entity.Step(1)
+
+	if math.Abs(entity.LocationX-7.5) > testEpsilon {
+		t.Errorf("distance moved: wanted %.2f: got %.2f", 7.5, entity.LocationX)
+	}
+
+	entity.RemoveSpeedModifier("cold")
+
+	if got := entity.GetEffectiveSpeed(); math.Abs(got-15) > testEpsilon {
+		t.Errorf("speed after the slow ends: wanted %.2f: got %.2f", 15.0, got)
+	}
+
+	entity.RemoveSpeedModifier("vigor")
+
+	if got := entity.GetEffectiveSpeed(); got != entity.GetSpeed() {
+		t.Errorf("speed without modifiers: wanted %.2f: got %.2f", entity.GetSpeed(), got)
+	}
+
+	entity.AddSpeedModifier("frozen", 0)
+
+	if got := entity.GetEffectiveSpeed(); math.Abs(got-10*minSpeedMultiplier) > testEpsilon {
+		t.Errorf("clamped speed: wanted %.2f: got %.2f", 10*minSpeedMultiplier, got)
+	}
+}
+
+func TestMapEntityRemainingPathDistance(t *testing.T) {
+	entity := createMapEntity(0, 0)
+
+	if got := entity.GetRemainingPathDistance(); got != 0 {
+		t.Errorf("empty path distance: wanted %.2f: got %.2f", 0.0, got)
+	}
+
+	entity.SetPath([]d2astar.Pather{
+		&d2common.PathTile{X: 1, Y: 0},
+		&d2common.PathTile{X: 1, Y: 1},
+		&d2common.PathTile{X: 2, Y: 1},
+	}, nil)
+
+	want := 15.0
+	if got := entity.GetRemainingPathDistance(); math.Abs(got-want) > testEpsilon {
+		t.Errorf("path distance: wanted %.2f: got %.2f", want, got)
+	}
+}
+
+func TestMapEntityTeleport(t *testing.T) {
+	entity := createMapEntity(0, 0)
+	entity.SetTarget(50, 50, nil)
+	entity.Step(0.1)
+
+	entity.Teleport(17, 23)
+
+	if !entity.IsAtTarget() {
+		t.Error("entity should be at target after teleport")
+	}
+
+	reference := createMapEntity(17, 23)
+
+	x, y := entity.GetPosition()
+	wantX, wantY := reference.GetPosition()
+
+	if x != wantX || y != wantY {
+		t.Errorf("position: wanted (%.2f, %.2f): got (%.2f, %.2f)", wantX, wantY, x, y)
+	}
+
+	x, y = entity.GetPositionF()
+	wantX, wantY = reference.GetPositionF()
+
+	if x != wantX || y != wantY {
+		t.Errorf("sub tile position: wanted (%.2f, %.2f): got (%.2f, %.2f)", wantX, wantY, x, y)
+	}
+}
+
+func TestMapEntityStepCorner(t *testing.T) {
+	entity := createMapEntity(0, 0)
+	entity.SetPath([]d2astar.Pather{
+		&d2common.PathTile{X: 2, Y: 0},
+		&d2common.PathTile{X: 2, Y: 2},
+	}, nil)
+
+	for i := 0; i < 100 && !entity.IsAtTarget(); i++ {
+		entity.Step(0.3)
+
+		onFirstLeg := math.Abs(entity.LocationY) < testEpsilon
+		onSecondLeg := math.Abs(entity.LocationX-10) < testEpsilon
+
+		if !onFirstLeg && !onSecondLeg {
+			t.Fatalf("entity strayed off the path at (%.4f, %.4f)", entity.LocationX, entity.LocationY)
+		}
+	}
+
+	if entity.LocationX != 10 || entity.LocationY != 10 {
+		t.Errorf("final location: wanted (%.2f, %.2f): got (%.2f, %.2f)", 10.0, 10.0, entity.LocationX, entity.LocationY)
+	}
+}
+
+func TestMapEntityStepLongTick(t *testing.T) {
+	entity := createMapEntity(0, 0)
+	entity.SetPath([]d2astar.Pather{
+		&d2common.PathTile{X: 2, Y: 0},
+		&d2common.PathTile{X: 2, Y: 2},
+	}, nil)
+
+	var entered []d2common.Point
+
+	entity.SetOnTileEnter(func(tileX, tileY int) {
+		x, y := entity.LocationX, entity.LocationY
+		onFirstLeg := math.Abs(y) < testEpsilon
+		onSecondLeg := math.Abs(x-10) < testEpsilon
+
+		if !onFirstLeg && !onSecondLeg {
+			t.Errorf("entity strayed off the path at (%.4f, %.4f)", x, y)
+		}
+
+		entered = append(entered, d2common.Point{X: tileX, Y: tileY})
+	})
+
+	// a single tick long enough to walk the whole path several times over
+	entity.Step(10)
+
+	want := []d2common.Point{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}}
+
+	if len(entered) != len(want) {
+		t.Fatalf("tiles entered: wanted %v: got %v", want, entered)
+	}
+
+	for idx := range want {
+		if entered[idx] != want[idx] {
+			t.Errorf("tile entered %d: wanted %v: got %v", idx, want[idx], entered[idx])
+		}
+	}
+
+	if entity.LocationX != 10 || entity.LocationY != 10 {
+		t.Errorf("final location: wanted (%.2f, %.2f): got (%.2f, %.2f)", 10.0, 10.0, entity.LocationX, entity.LocationY)
+	}
+}
+
+func TestMapEntityTileEnter(t *testing.T) {
+	entity := createMapEntity(2, 2)
+
+	entered := make([][2]int, 0)
+
+	entity.SetOnTileEnter(func(tileX, tileY int) {
+		entered = append(entered, [2]int{tileX, tileY})
+	})
+	entity.SetTarget(17, 2, nil)
+
+	for i := 0; i < 100 && !entity.IsAtTarget(); i++ {
+		entity.Step(0.05)
+	}
+
+	want := [][2]int{{1, 0}, {2, 0}, {3, 0}}
+
+	if len(entered) != len(want) {
+		t.Fatalf("tile enter calls: wanted %v: got %v", want, entered)
+	}
+
+	for idx := range want {
+		if entered[idx] != want[idx] {
+			t.Errorf("tile enter call %d: wanted %v: got %v", idx, want[idx], entered[idx])
+		}
+	}
+}
+
+func TestMapEntityWalkExploresAutomap(t *testing.T) {
+	automap := d2automap.NewAutomap(4, 4, nil)
+
+	entity := createMapEntity(0, 0)
+	automap.Visit(entity.TileX, entity.TileY)
+	entity.SetOnTileEnter(automap.Visit)
+	entity.SetPath([]d2astar.Pather{
+		&d2common.PathTile{X: 2, Y: 0},
+		&d2common.PathTile{X: 2, Y: 2},
+	}, nil)
+
+	for i := 0; i < 100 && !entity.IsAtTarget(); i++ {
+		entity.Step(0.1)
+	}
+
+	walked := [][2]int{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}}
+	for _, tile := range walked {
+		if !automap.IsExplored(tile[0], tile[1]) {
+			t.Errorf("tile %v: wanted explored", tile)
+		}
+	}
+
+	unvisited := [][2]int{{0, 1}, {1, 1}, {3, 0}, {3, 3}}
+	for _, tile := range unvisited {
+		if automap.IsExplored(tile[0], tile[1]) {
+			t.Errorf("tile %v: wanted unexplored", tile)
+		}
+	}
+}
+
+func TestMapEntityTurnRate(t *testing.T) {
+	entity := createMapEntity(0, 0)
+	entity.SetTurnRate(64)
+	entity.SetDirection(32)
+
+	ticks := 0
+	for ; ticks < 100 && entity.GetDirection() != 32; ticks++ {
+		entity.Step(0.125)
+	}
+
+	if ticks != 4 {
+		t.Errorf("ticks to turn from 0 to 32: wanted %d: got %d", 4, ticks)
+	}
+
+	entity.SetTurnRate(0)
+	entity.SetDirection(60)
+	entity.SetTurnRate(64)
+	entity.SetDirection(4)
+
+	want := []int{0, 4}
+
+	for idx := range want {
+		entity.Step(0.0625)
+
+		if got := entity.GetDirection(); got != want[idx] {
+			t.Errorf("direction after tick %d: wanted %d: got %d", idx+1, want[idx], got)
+		}
+	}
+}
+
+func TestMapEntityFollow(t *testing.T) {
+	const standoff = 5.0
+
+	leader := createMapEntity(0, 0)
+	follower := createMapEntity(0, 0)
+
+	follower.SetFollowTarget(&leader, standoff)
+	leader.SetTarget(50, 0, nil)
+
+	for i := 0; i < 100; i++ {
+		leader.Step(0.1)
+		follower.Step(0.1)
+	}
+
+	distance := math.Hypot(leader.LocationX-follower.LocationX, leader.LocationY-follower.LocationY)
+
+	if distance > standoff+testEpsilon {
+		t.Errorf("follower distance: wanted at most %.2f: got %.2f", standoff, distance)
+	}
+
+	if !follower.IsAtTarget() {
+		t.Error("follower should hold position once within standoff")
+	}
+
+	follower.SetFollowTarget(nil, standoff)
+	leader.SetTarget(0, 0, nil)
+
+	for i := 0; i < 100; i++ {
+		leader.Step(0.1)
+		follower.Step(0.1)
+	}
+
+	if follower.LocationX != 50-standoff {
+		t.Errorf("follower should stop following: wanted x %.2f: got %.2f", 50-standoff, follower.LocationX)
+	}
+}
+
+func TestMapEntityFollowRemovedLeader(t *testing.T) {
+	leader := createMapEntity(0, 0)
+	follower := createMapEntity(0, 0)
+
+	follower.SetFollowTarget(&leader, 5)
+	leader.SetTarget(50, 0, nil)
+
+	for i := 0; i < 10; i++ {
+		leader.Step(0.1)
+	}
+
+	leader.MarkForRemoval()
+
+	x, y := follower.LocationX, follower.LocationY
+
+	for i := 0; i < 10; i++ {
+		follower.Step(0.1)
+	}
+
+	if follower.followTarget != nil {
+		t.Error("follower should drop a leader which is being removed")
+	}
+
+	if !follower.IsAtTarget() || follower.LocationX != x || follower.LocationY != y {
+		t.Errorf("follower should hold position at (%.2f, %.2f): got (%.2f, %.2f)", x, y, follower.LocationX,
+			follower.LocationY)
+	}
+}
+
+func TestMapEntityPatrol(t *testing.T) {
+	points := [][2]int{{0, 0}, {10, 0}, {10, 10}}
+
+	entity := createMapEntity(0, 0)
+	entity.SetPatrol(points, true)
+
+	const tickTime = 0.05
+
+	isNear := func(point [2]int) bool {
+		distance := math.Hypot(entity.LocationX-float64(point[0]), entity.LocationY-float64(point[1]))
+		return distance <= tickTime*entity.Speed
+	}
+
+	visitedLast := false
+	revisitedFirst := false
+
+	for i := 0; i < 1000 && !revisitedFirst; i++ {
+		entity.Step(tickTime)
+
+		if isNear(points[2]) {
+			visitedLast = true
+		}
+
+		if visitedLast && isNear(points[0]) {
+			revisitedFirst = true
+		}
+	}
+
+	if !revisitedFirst {
+		t.Error("entity should revisit the first waypoint after completing a loop")
+	}
+
+	entity.ClearPath()
+
+	if entity.IsPatrolling() {
+		t.Error("ClearPath should stop the patrol")
+	}
+}
+
+func TestMapEntityPatrolPingPong(t *testing.T) {
+	points := [][2]int{{0, 0}, {10, 0}, {10, 10}}
+
+	entity := createMapEntity(0, 0)
+	entity.SetPatrol(points, false)
+
+	// the entity walks to the last point, turns back to the first and turns again
+	want := [][2]int{{10, 0}, {10, 10}, {10, 0}, {0, 0}, {10, 0}, {10, 10}}
+
+	var targets [][2]int
+
+	lastTarget := [2]int{int(entity.TargetX), int(entity.TargetY)}
+
+	for i := 0; i < 1000 && len(targets) < len(want); i++ {
+		entity.Step(0.05)
+
+		if target := [2]int{int(entity.TargetX), int(entity.TargetY)}; target != lastTarget {
+			targets = append(targets, target)
+			lastTarget = target
+		}
+	}
+
+	if len(targets) != len(want) {
+		t.Fatalf("patrol targets: wanted %v: got %v", want, targets)
+	}
+
+	for idx := range want {
+		if targets[idx] != want[idx] {
+			t.Errorf("patrol target %d: wanted %v: got %v", idx, want[idx], targets[idx])
+		}
+	}
+
+	if !entity.IsPatrolling() {
+		t.Error("a ping-pong patrol should never end")
+	}
+}
+
+func TestMapEntityArrivalThreshold(t *testing.T) {
+	entity := createMapEntity(0, 0)
+
+	if err := entity.SetArrivalThreshold(0); err == nil {
+		t.Error("zero arrival threshold should be rejected")
+	}
+
+	if err := entity.SetArrivalThreshold(-1); err == nil {
+		t.Error("negative arrival threshold should be rejected")
+	}
+
+	ticksToSettle := func(threshold float64) int {
+		fast := createMapEntity(0, 0)
+		fast.SetSpeed(10)
+
+		if err := fast.SetArrivalThreshold(threshold); err != nil {
+			t.Fatal(err)
+		}
+
+		fast.SetTarget(10.3, 0, nil)
+
+		ticks := 0
+		for ; ticks < 100 && !fast.IsAtTarget(); ticks++ {
+			fast.Step(1)
+		}
+
+		settledX := fast.LocationX
+
+		for i := 0; i < 10; i++ {
+			fast.Step(1)
+		}
+
+		if fast.LocationX != settledX {
+			t.Errorf("entity moved after settling: wanted x %.4f: got %.4f", settledX, fast.LocationX)
+		}
+
+		return ticks
+	}
+
+	tight := ticksToSettle(defaultArrivalThreshold)
+	loose := ticksToSettle(0.5)
+
+	if loose >= tight {
+		t.Errorf("loose threshold should settle in fewer ticks: tight %d: loose %d", tight, loose)
+	}
+}
+
+func TestMapEntityName(t *testing.T) {
+	entity := createMapEntity(0, 0)
+
+	if name := entity.Name(); name != "" {
+		t.Errorf("unnamed entity: wanted %q: got %q", "", name)
+	}
+
+	names := map[string]string{"Cain": "Deckard Cain"}
+	entity.SetNameKey("Cain", func(key string) string {
+		return names[key]
+	})
+
+	if name := entity.Name(); name != "Deckard Cain" {
+		t.Errorf("named entity: wanted %q: got %q", "Deckard Cain", name)
+	}
+}
+
+func TestMapEntityHighlight(t *testing.T) {
+	entity := createMapEntity(0, 0)
+
+	if entity.IsHighlighted() {
+		t.Error("entity should not be highlighted by default")
+	}
+
+	entity.Highlight()
+
+	if !entity.IsHighlighted() {
+		t.Error("entity should be highlighted after Highlight")
+	}
+
+	entity.ClearHighlight()
+
+	if entity.IsHighlighted() {
+		t.Error("entity should not be highlighted after ClearHighlight")
+	}
+}
+
+func TestMapEntityDrawOrderKey(t *testing.T) {
+	front := createMapEntity(10, 20)
+	back := createMapEntity(30, 15)
+
+	if back.DrawOrderKey() >= front.DrawOrderKey() {
+		t.Errorf("entity with lower y should sort first: got %.2f and %.2f", back.DrawOrderKey(), front.DrawOrderKey())
+	}
+
+	back.drawLayer = 1
+
+	if back.DrawOrderKey() <= front.DrawOrderKey() {
+		t.Errorf("entity on higher layer should sort last: got %.2f and %.2f", back.DrawOrderKey(), front.DrawOrderKey())
+	}
+}
+
+func TestMapEntityFootprint(t *testing.T) {
+	entity := createMapEntity(12, 17)
+
+	if tiles := entity.OccupiedTiles(); len(tiles) != 1 {
+		t.Errorf("default footprint tiles: wanted %d: got %d", 1, len(tiles))
+	}
+
+	entity.SetFootprint(2, 2)
+
+	want := []d2common.Point{{X: 2, Y: 3}, {X: 3, Y: 3}, {X: 2, Y: 4}, {X: 3, Y: 4}}
+	got := entity.OccupiedTiles()
+
+	if len(got) != len(want) {
+		t.Fatalf("footprint tiles: wanted %v: got %v", want, got)
+	}
+
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Errorf("footprint tile %d: wanted %v: got %v", idx, want[idx], got[idx])
+		}
+	}
+
+	if x, y := entity.GetPosition(); x != 2 || y != 3 {
+		t.Errorf("anchor position: wanted (%d, %d): got (%.0f, %.0f)", 2, 3, x, y)
+	}
+}
+
+func TestMapEntityID(t *testing.T) {
+	first := createMapEntity(0, 0)
+	second := createMapEntity(0, 0)
+
+	if first.ID() == second.ID() {
+		t.Errorf("entity ids should be distinct: got %d and %d", first.ID(), second.ID())
+	}
+
+	id := first.ID()
+	first.Teleport(10, 10)
+
+	if first.ID() != id {
+		t.Errorf("entity id should not change: wanted %d: got %d", id, first.ID())
+	}
+}
+
+func TestMapEntityNetworkInterpolation(t *testing.T) {
+	entity := createMapEntity(0, 0)
+	entity.PushNetworkState(0, 0, 1)
+	entity.PushNetworkState(10, 0, 2)
+
+	for _, step := range []struct {
+		tickTime float64
+		wantX    float64
+	}{
+		{networkRenderDelay, 0},
+		{0.5, 5},
+		{0.25, 7.5},
+		{1, 10},
+	} {
+		entity.Step(step.tickTime)
+
+		if math.Abs(entity.LocationX-step.wantX) > testEpsilon || entity.LocationY != 0 {
+			t.Errorf("location at server time %.2f: wanted (%.2f, 0.00): got (%.2f, %.2f)",
+				entity.networkClock, step.wantX, entity.LocationX, entity.LocationY)
+		}
+	}
+
+	if !entity.IsAtTarget() {
+		t.Error("entity should be at rest after the last network state")
+	}
+
+	local := createMapEntity(0, 0)
+	local.SetLocallyControlled(true)
+	local.PushNetworkState(10, 0, 1)
+	local.Step(1)
+
+	if local.LocationX != 0 {
+		t.Errorf("locally controlled location: wanted 0.00: got %.2f", local.LocationX)
+	}
+}
+
+func TestMapEntityPredictionReconcile(t *testing.T) {
+	entity := createMapEntity(0, 0)
+	entity.SetLocallyControlled(true)
+	entity.SetTarget(100, 0, nil)
+
+	var sent []uint64
+
+	entity.SetOnPredictedMove(func(sequence uint64, dx, dy float64) {
+		sent = append(sent, sequence)
+	})
+
+	for i := 0; i < 3; i++ {
+		entity.Step(0.25)
+	}
+
+	if len(sent) != 3 || sent[2] != 3 {
+		t.Fatalf("predicted moves sent: wanted [1 2 3]: got %v", sent)
+	}
+
+	step := 0.25 * entity.Speed
+
+	// The server applied the first move but the entity ended up one sub-tile lower than predicted
+	entity.ReconcileNetworkState(1, step, 1)
+
+	if math.Abs(entity.LocationX-3*step) > testEpsilon || math.Abs(entity.LocationY-1) > testEpsilon {
+		t.Errorf("reconciled location: wanted (%.2f, 1.00): got (%.2f, %.2f)", 3*step, entity.LocationX, entity.LocationY)
+	}
+
+	if len(entity.predictedMoves) != 2 {
+		t.Errorf("unacknowledged moves: wanted 2: got %d", len(entity.predictedMoves))
+	}
+
+	entity.ReconcileNetworkState(3, 3*step, 1)
+
+	if math.Abs(entity.LocationX-3*step) > testEpsilon || len(entity.predictedMoves) != 0 {
+		t.Errorf("fully acknowledged: wanted x %.2f and no moves: got x %.2f and %d moves",
+			3*step, entity.LocationX, len(entity.predictedMoves))
+	}
+}
+
+func TestMapEntitySaveRestorePosition(t *testing.T) {
+	entity := createMapEntity(0, 0)
+	entity.SetTurnRate(16)
+	entity.SetPath([]d2astar.Pather{
+		&d2common.PathTile{X: 3, Y: 0},
+		&d2common.PathTile{X: 3, Y: 2},
+	}, func() {})
+
+	for i := 0; i < 100 && !entity.IsAtTarget(); i++ {
+		entity.Step(0.1)
+	}
+
+	entity.SetTurnRate(0)
+	entity.SetDirection(24)
+
+	data, err := json.Marshal(entity.SavePosition())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var position EntityPosition
+	if err := json.Unmarshal(data, &position); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := createMapEntity(50, 50)
+	restored.SetTurnRate(16)
+	restored.SetPath([]d2astar.Pather{&d2common.PathTile{X: 20, Y: 20}}, func() {
+		t.Error("restoring a position kept the path done callback")
+	})
+	restored.RestorePosition(position)
+
+	x, y := restored.GetPositionF()
+	wantX, wantY := entity.GetPositionF()
+
+	if x != wantX || y != wantY {
+		t.Errorf("restored position: wanted (%.2f, %.2f): got (%.2f, %.2f)", wantX, wantY, x, y)
+	}
+
+	if got := restored.GetDirection(); got != entity.GetDirection() {
+		t.Errorf("restored direction: wanted %d: got %d", entity.GetDirection(), got)
+	}
+
+	restored.Step(0.1)
+
+	if !restored.IsAtTarget() {
+		t.Error("restored entity should be at rest")
+	}
+}
+
+func TestMapEntityRemoval(t *testing.T) {
+	entity := createMapEntity(0, 0)
+	entity.SetTarget(50, 0, nil)
+
+	removals := 0
+	entity.SetOnRemove(func() {
+		removals++
+	})
+
+	if entity.ShouldRemove() {
+		t.Fatal("entity should not be marked for removal")
+	}
+
+	entity.MarkForRemoval()
+
+	if !entity.ShouldRemove() {
+		t.Fatal("entity should be marked for removal")
+	}
+
+	entity.Step(1)
+
+	if entity.LocationX != 0 || entity.LocationY != 0 {
+		t.Errorf("entity marked for removal moved to (%.2f, %.2f)", entity.LocationX, entity.LocationY)
+	}
+
+	entity.Cleanup()
+	entity.Cleanup()
+
+	if removals != 1 {
+		t.Errorf("cleanup calls: wanted %d: got %d", 1, removals)
+	}
+}
+
+func TestMapEntityNearestWalkable(t *testing.T) {
+	entity := createMapEntity(0, 0)
+
+	// a wall along y = 4
+	entity.SetCollisionChecker(func(tileX, tileY int) bool {
+		return tileY == 4
+	})
+
+	x, y, ok := entity.NearestWalkable(3, 4)
+	if !ok || x != 3 || (y != 3 && y != 5) {
+		t.Errorf("nearest to a wall tile: wanted an adjacent tile: got (%d, %d, %v)", x, y, ok)
+	}
+
+	// the whole footprint must fit, so the closest tile for an entity three tiles tall is right below the wall
+	entity.SetFootprint(1, 3)
+
+	x, y, ok = entity.NearestWalkable(3, 4)
+	if !ok || x != 3 || y != 5 {
+		t.Errorf("nearest to a wall tile for a large entity: wanted (%d, %d): got (%d, %d, %v)", 3, 5, x, y, ok)
+	}
+}
+
+func TestMapEntityStopMovement(t *testing.T) {
+	entity := createMapEntity(0, 0)
+
+	done, cancelled := 0, 0
+
+	entity.SetOnCancel(func() {
+		cancelled++
+	})
+	entity.SetPath([]d2astar.Pather{
+		&d2common.PathTile{X: 4, Y: 0},
+		&d2common.PathTile{X: 4, Y: 4},
+	}, func() {
+		done++
+	})
+
+	entity.Step(0.5)
+	entity.StopMovement()
+
+	x, y := entity.LocationX, entity.LocationY
+
+	for i := 0; i < 100; i++ {
+		entity.Step(0.1)
+	}
+
+	if done != 0 || cancelled != 1 {
+		t.Errorf("interrupted path: wanted %d done and %d cancel calls: got %d and %d", 0, 1, done, cancelled)
+	}
+
+	if entity.LocationX != x || entity.LocationY != y {
+		t.Errorf("entity moved after being stopped, from (%.2f, %.2f) to (%.2f, %.2f)", x, y, entity.LocationX,
+			entity.LocationY)
+	}
+
+	// stopping an entity at rest cancels nothing
+	entity.StopMovement()
+
+	if cancelled != 1 {
+		t.Errorf("stopping an entity at rest: wanted %d cancel calls: got %d", 1, cancelled)
+	}
+}
+
+func TestMapEntitySnapshot(t *testing.T) {
+	entity := createMapEntity(10, 15)
+	entity.SetSpeed(8)
+	entity.AddSpeedModifier("cold", 0.5)
+	entity.SetPath([]d2astar.Pather{&d2common.PathTile{X: 9, Y: 9}}, nil)
+	entity.SetTarget(30, 15, nil)
+
+	want := MovementSnapshot{
+		LocationX:      10,
+		LocationY:      15,
+		TargetX:        30,
+		TargetY:        15,
+		TileX:          2,
+		TileY:          3,
+		Speed:          8,
+		EffectiveSpeed: 4,
+		Direction:      entity.GetDirection(),
+		PathLength:     1,
+		AtTarget:       false,
+	}
+
+	if got := entity.Snapshot(); got != want {
+		t.Errorf("snapshot after setting the target: wanted %+v: got %+v", want, got)
+	}
+}
+
+func TestMapEntityKnockbackEasing(t *testing.T) {
+	entity := createMapEntity(50, 50)
+	entity.ApplyKnockback(10, 0, 1)
+
+	// The entity decelerates, covering three quarters of the distance in the first half of the knockback
+	for _, want := range []float64{57.5, 60, 60} {
+		entity.Step(0.5)
+
+		if math.Abs(entity.LocationX-want) > testEpsilon || entity.LocationY != 50 {
+			t.Errorf("knockback location: wanted (%.2f, %.2f): got (%.2f, %.2f)", want, 50.0, entity.LocationX,
+				entity.LocationY)
+		}
+	}
+
+	if entity.IsKnockedBack() {
+		t.Error("knockback should end after its duration")
+	}
+
+	if !entity.IsAtTarget() {
+		t.Error("an idle entity should stay where it landed")
+	}
+}
+
+func TestMapEntityKnockbackResumesPath(t *testing.T) {
+	entity := createMapEntity(0, 0)
+
+	doneCount := 0
+
+	entity.SetPath([]d2astar.Pather{
+		&d2common.PathTile{X: 2, Y: 0},
+		&d2common.PathTile{X: 2, Y: 2},
+	}, func() {
+		doneCount++
+	})
+	entity.Step(0.1)
+
+	entity.ApplyKnockback(0, 5, 0.5)
+	entity.Step(0.5)
+
+	if entity.IsKnockedBack() {
+		t.Error("knockback should end after its duration")
+	}
+
+	if math.Abs(entity.LocationY-5) > testEpsilon {
+		t.Errorf("knockback location y: wanted %.2f: got %.2f", 5.0, entity.LocationY)
+	}
+
+	if doneCount != 0 {
+		t.Errorf("done calls after the knockback: wanted %d: got %d", 0, doneCount)
+	}
+
+	for i := 0; i < 100 && !entity.IsAtTarget(); i++ {
+		entity.Step(0.1)
+	}
+
+	entity.Step(0.1)
+
+	if entity.LocationX != 10 || entity.LocationY != 10 {
+		t.Errorf("final location: wanted (%.2f, %.2f): got (%.2f, %.2f)", 10.0, 10.0, entity.LocationX, entity.LocationY)
+	}
+
+	if doneCount != 1 {
+		t.Errorf("done calls after resuming the path: wanted %d: got %d", 1, doneCount)
+	}
+}
+
+func TestMapEntityKnockbackBlocked(t *testing.T) {
+	entity := createMapEntity(7, 7)
+	entity.SetCollisionChecker(func(tileX, tileY int) bool {
+		return tileX == 2
+	})
+	entity.ApplyKnockback(10, 0, 1)
+
+	entity.Step(0.2)
+
+	if entity.IsKnockedBack() {
+		t.Error("knockback into a blocked tile should be cancelled")
+	}
+
+	if entity.TileX != 1 || entity.LocationX >= 10 {
+		t.Errorf("knockback into a blocked tile: wanted to stay in tile x %d: got location x %.2f", 1, entity.LocationX)
+	}
+
+	x := entity.LocationX
+
+	entity.Step(1)
+
+	if entity.LocationX != x {
+		t.Errorf("entity moved after its knockback was cancelled, from x %.2f to %.2f", x, entity.LocationX)
+	}
+}
+
+func TestMapEntityKnockbackKeepsFacing(t *testing.T) {
+	entity := createMapEntity(50, 50)
+	entity.SetDirection(5)
+	entity.ApplyKnockback(-10, 10, 0.5)
+
+	for i := 0; i < 10; i++ {
+		entity.Step(0.1)
+
+		if got := entity.GetDirection(); got != 5 {
+			t.Fatalf("direction during knockback: wanted %d: got %d", 5, got)
+		}
+	}
+}
+
+func TestMapEntitySetTargetChecked(t *testing.T) {
+	entity := createMapEntity(2, 2)
+	entity.SetCollisionChecker(func(tileX, tileY int) bool {
+		return tileX == 4
+	})
+
+	pathDone, targetDone := 0, 0
+
+	path := []d2astar.Pather{&d2common.PathTile{X: 2, Y: 0}}
+	entity.SetPath(path, func() {
+		pathDone++
+	})
+
+	if entity.SetTargetChecked(22, 2, func() {
+		targetDone++
+	}) {
+		t.Error("a target in a blocked tile should be rejected")
+	}
+
+	if entity.TargetX != 2 || entity.TargetY != 2 {
+		t.Errorf("target after rejection: wanted (%.2f, %.2f): got (%.2f, %.2f)", 2.0, 2.0, entity.TargetX,
+			entity.TargetY)
+	}
+
+	if len(entity.path) != 1 || entity.path[0] != path[0] {
+		t.Errorf("path after rejection: wanted %v: got %v", path, entity.path)
+	}
+
+	for i := 0; i < 100 && pathDone == 0; i++ {
+		entity.Step(0.1)
+	}
+
+	if entity.LocationX != 10 || entity.LocationY != 0 {
+		t.Errorf("location after the path: wanted (%.2f, %.2f): got (%.2f, %.2f)", 10.0, 0.0, entity.LocationX,
+			entity.LocationY)
+	}
+
+	if pathDone != 1 || targetDone != 0 {
+		t.Errorf("done calls after rejection: wanted %d for the path and %d for the target: got %d and %d", 1, 0,
+			pathDone, targetDone)
+	}
+
+	if !entity.SetTargetChecked(7, 7, func() {
+		targetDone++
+	}) {
+		t.Error("a target in a free tile should be accepted")
+	}
+
+	if entity.TargetX != 7 || entity.TargetY != 7 {
+		t.Errorf("target after acceptance: wanted (%.2f, %.2f): got (%.2f, %.2f)", 7.0, 7.0, entity.TargetX,
+			entity.TargetY)
+	}
+
+	for i := 0; i < 100 && !entity.IsAtTarget(); i++ {
+		entity.Step(0.1)
+	}
+
+	entity.Step(0.1)
+
+	if entity.LocationX != 7 || entity.LocationY != 7 || targetDone != 1 {
+		t.Errorf("accepted target: wanted to arrive at (%.2f, %.2f) with %d done call: got (%.2f, %.2f) with %d",
+			7.0, 7.0, 1, entity.LocationX, entity.LocationY, targetDone)
+	}
+}
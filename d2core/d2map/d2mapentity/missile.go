@@ -15,6 +15,14 @@ import (
 type Missile struct {
 	*AnimatedEntity
 	record *d2datadict.MissileRecord
+
+	// MaxRange is the distance, in sub tiles, the missile travels before it expires. 0 means it never expires.
+	MaxRange float64
+
+	launchX, launchY float64
+	finished         bool
+	onImpact         func(tileX, tileY int)
+	onExpire         func()
 }
 
 // CreateMissile creates a new Missile and initializes it's animation.
@@ -35,32 +43,109 @@ func CreateMissile(x, y int, record *d2datadict.MissileRecord) (*Missile, error)
 	//animation.SetPlaySpeed(float64(record.Animation.AnimationSpeed))
 	animation.SetPlayLoop(record.Animation.LoopAnimation)
 	animation.PlayForward()
-	entity := CreateAnimatedEntity(x, y, animation)
 
+	return newMissile(CreateAnimatedEntity(x, y, animation), record), nil
+}
+
+// newMissile creates a missile moving the given entity, with the speed and range of the missile record.
+func newMissile(entity *AnimatedEntity, record *d2datadict.MissileRecord) *Missile {
 	result := &Missile{
 		AnimatedEntity: entity,
 		record:         record,
+		MaxRange:       float64(record.Range),
 	}
 	result.Speed = float64(record.Velocity)
+	result.SetOnBlocked(result.impact)
+
+	return result
+}
+
+// SetOnImpact sets the function called with the tile the missile is on when it reaches its target or is stopped by
+// a blocked tile.
+func (m *Missile) SetOnImpact(onImpact func(tileX, tileY int)) {
+	m.onImpact = onImpact
+}
 
-	return result, nil
+// SetOnExpire sets the function called when the missile travels further than its maximum range.
+func (m *Missile) SetOnExpire(onExpire func()) {
+	m.onExpire = onExpire
 }
 
-// SetRadians adjusts the entity target based on it's range, rotating it's
-// current destination by the value of angle in radians.
-func (m *Missile) SetRadians(angle float64, done func()) {
+// Launch fires the missile in a straight line toward the given location, in sub tiles. The missile faces the
+// location once, at launch, and flies until it impacts or expires.
+func (m *Missile) Launch(x, y float64) {
+	m.launchX, m.launchY = m.LocationX, m.LocationY
+	m.finished = false
+
+	m.SetTarget(x, y, m.impact)
+}
+
+// SetRadians launches the missile in the direction given by angle in radians, toward the end of its range.
+func (m *Missile) SetRadians(angle float64) {
 	r := float64(m.record.Range)
 
 	x := m.LocationX + (r * math.Cos(angle))
 	y := m.LocationY + (r * math.Sin(angle))
 
-	m.SetTarget(x, y, done)
+	m.Launch(x, y)
+}
+
+// IsFinished returns true once the missile has impacted or expired.
+func (m *Missile) IsFinished() bool {
+	return m.finished
 }
 
 // Advance is called once per frame and processes a
 // single game tick.
 func (m *Missile) Advance(tickTime float64) {
-	// TODO: collision detection
-	m.Step(tickTime)
+	m.fly(tickTime)
 	m.AnimatedEntity.Advance(tickTime)
 }
+
+// fly moves the missile along its trajectory, expiring it once it is further from its launch location than its
+// maximum range. The range is checked after every substep, so a long tick can't carry the missile past it.
+func (m *Missile) fly(tickTime float64) {
+	for !m.finished && tickTime > 0 {
+		substepTime := math.Min(tickTime, maxSubstepTime)
+		tickTime -= substepTime
+
+		m.Step(substepTime)
+		m.checkRange()
+	}
+}
+
+// checkRange expires the missile if it is further from its launch location than its maximum range
+func (m *Missile) checkRange() {
+	if m.finished || m.MaxRange <= 0 {
+		return
+	}
+
+	// a missile targeted at the end of its range impacts rather than expires
+	if math.Hypot(m.LocationX-m.launchX, m.LocationY-m.launchY)-m.MaxRange > pathNodeThreshold {
+		m.finish()
+
+		if m.onExpire != nil {
+			m.onExpire()
+		}
+	}
+}
+
+// impact stops the missile where it is and calls the impact callback, it is the done() of the missile trajectory.
+func (m *Missile) impact() {
+	if m.finished {
+		return
+	}
+
+	m.finish()
+
+	if m.onImpact != nil {
+		m.onImpact(m.TileX, m.TileY)
+	}
+}
+
+// finish stops the missile, it won't move again.
+func (m *Missile) finish() {
+	m.finished = true
+	m.TargetX, m.TargetY = m.LocationX, m.LocationY
+	m.done = nil
+}
@@ -0,0 +1,78 @@
+package d2mapentity
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+)
+
+func TestMissileImpact(t *testing.T) {
+	missile := newMissile(&AnimatedEntity{mapEntity: createMapEntity(0, 0)}, &d2datadict.MissileRecord{
+		Velocity: 20,
+		Range:    100,
+	})
+
+	impacts := 0
+	impactX, impactY := -1, -1
+
+	missile.SetOnImpact(func(tileX, tileY int) {
+		impacts++
+		impactX, impactY = tileX, tileY
+	})
+	missile.SetOnExpire(func() {
+		t.Error("missile expired before reaching its target")
+	})
+
+	missile.Launch(30, 20)
+	direction := missile.GetDirection()
+
+	for i := 0; i < 100; i++ {
+		missile.fly(0.1)
+
+		if got := missile.GetDirection(); got != direction {
+			t.Fatalf("missile direction changed in flight: wanted %d: got %d", direction, got)
+		}
+	}
+
+	if impacts != 1 {
+		t.Fatalf("missile impacts: wanted %d: got %d", 1, impacts)
+	}
+
+	if impactX != 6 || impactY != 4 {
+		t.Errorf("impact tile: wanted (%d, %d): got (%d, %d)", 6, 4, impactX, impactY)
+	}
+
+	if !missile.IsFinished() {
+		t.Error("missile should be finished after impact")
+	}
+}
+
+func TestMissileMaxRange(t *testing.T) {
+	missile := newMissile(&AnimatedEntity{mapEntity: createMapEntity(0, 0)}, &d2datadict.MissileRecord{
+		Velocity: 20,
+		Range:    10,
+	})
+
+	expired := 0
+
+	missile.SetOnImpact(func(_, _ int) {
+		t.Error("missile impacted beyond its range")
+	})
+	missile.SetOnExpire(func() {
+		expired++
+	})
+
+	missile.Launch(100, 0)
+
+	for i := 0; i < 100; i++ {
+		missile.fly(0.1)
+	}
+
+	if expired != 1 {
+		t.Errorf("missile expirations: wanted %d: got %d", 1, expired)
+	}
+
+	if missile.LocationX > 10+missile.Speed*0.1 {
+		t.Errorf("missile kept flying after expiring, at %.2f", missile.LocationX)
+	}
+}
@@ -25,7 +25,6 @@ type NPC struct {
 	repetitions   int
 	monstatRecord *d2datadict.MonStatsRecord
 	monstatEx     *d2datadict.MonStats2Record
-	name          string
 }
 
 // CreateNPC creates a new NPC and returns a pointer to it.
@@ -53,10 +52,11 @@ func CreateNPC(x, y int, monstat *d2datadict.MonStatsRecord, direction int) *NPC
 	result.SetSpeed(float64(monstat.SpeedBase))
 	result.mapEntity.directioner = result.rotate
 
+	result.direction = direction
 	result.composite.SetDirection(direction)
 
 	if result.monstatRecord != nil && result.monstatRecord.IsInteractable {
-		result.name = d2common.TranslateString(result.monstatRecord.NameStringTableKey)
+		result.SetNameKey(result.monstatRecord.NameStringTableKey, d2common.TranslateString)
 	}
 
 	return result
@@ -77,7 +77,14 @@ func (v *NPC) Render(target d2interface.Surface) {
 		v.offsetY+int(((v.subcellX+v.subcellY)*8)-5),
 	)
 	defer target.Pop()
+
+	if v.IsHighlighted() {
+		target.PushBrightness(2)
+		defer target.Pop()
+	}
+
 	v.composite.Render(target)
+	v.ClearHighlight()
 }
 
 // Path returns the current part of the entity's path.
@@ -175,14 +182,9 @@ func (v *NPC) rotate(direction int) {
 // Selectable returns true if the object can be highlighted/selected.
 func (m *NPC) Selectable() bool {
 	// is there something handy that determines selectable npc's?
-	if m.name != "" {
+	if m.Name() != "" {
 		return true
 	}
 
 	return false
 }
-
-// Name returns the NPC's in-game name (e.g. "Deckard Cain") or an empty string if it does not have a name.
-func (m *NPC) Name() string {
-	return m.name
-}
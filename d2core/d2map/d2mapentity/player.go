@@ -25,7 +25,6 @@ type Player struct {
 	isInTown      bool
 	animationMode string
 	isRunToggled  bool
-	isRunning     bool
 	isCasting     bool
 }
 
@@ -66,9 +65,10 @@ func CreatePlayer(id, name string, x, y int, direction int, heroType d2enum.Hero
 		//nameLabel:    d2ui.CreateLabel(d2resource.FontFormal11, d2resource.PaletteStatic),
 		isRunToggled: true,
 		isInTown:     true,
-		isRunning:    true,
 	}
-	result.SetSpeed(baseRunSpeed)
+	result.SetWalkSpeed(baseWalkSpeed)
+	result.SetRunSpeed(baseRunSpeed)
+	result.SetRunning(true)
 	result.mapEntity.directioner = result.rotate
 	//result.nameLabel.Alignment = d2ui.LabelAlignCenter
 	//result.nameLabel.SetText(name)
@@ -78,6 +78,7 @@ func CreatePlayer(id, name string, x, y int, direction int, heroType d2enum.Hero
 		panic(err)
 	}
 
+	result.direction = direction
 	composite.SetDirection(direction)
 	composite.Equip(layerEquipment)
 
@@ -100,21 +101,10 @@ func (p *Player) IsRunToggled() bool {
 	return p.isRunToggled
 }
 
-// IsRunning returns true if the player is currently
-func (p *Player) IsRunning() bool {
-	return p.isRunning
-}
-
 // SetIsRunning alters the player speed and sets a flag indicating
 // that the player is running.
 func (p *Player) SetIsRunning(isRunning bool) {
-	p.isRunning = isRunning
-
-	if isRunning {
-		p.SetSpeed(baseRunSpeed)
-	} else {
-		p.SetSpeed(baseWalkSpeed)
-	}
+	p.SetRunning(isRunning)
 }
 
 // IsInTown returns true if the player is currently in town.
@@ -149,7 +139,14 @@ func (v *Player) Render(target d2interface.Surface) {
 		v.offsetY+int(((v.subcellX+v.subcellY)*8)-5),
 	)
 	defer target.Pop()
+
+	if v.IsHighlighted() {
+		target.PushBrightness(2)
+		defer target.Pop()
+	}
+
 	v.composite.Render(target)
+	v.ClearHighlight()
 	// v.nameLabel.X = v.offsetX
 	// v.nameLabel.Y = v.offsetY - 100
 	// v.nameLabel.Render(target)
@@ -0,0 +1,109 @@
+package d2maprenderer
+
+import (
+	"math"
+)
+
+// TileVisibility is how much of a tile the player can currently see.
+type TileVisibility int
+
+const (
+	// TileHidden tiles have never been seen and are not drawn.
+	TileHidden TileVisibility = iota
+
+	// TileExplored tiles have been seen before but are out of sight, they are drawn dimmed.
+	TileExplored
+
+	// TileVisible tiles are within sight and drawn normally.
+	TileVisible
+)
+
+const (
+	// DefaultRevealRadius is the default distance, in tiles, the player can see.
+	DefaultRevealRadius = 10.0
+
+	// exploredBrightness is the brightness of tiles which are explored but not visible.
+	exploredBrightness = 0.4
+)
+
+// FogOfWar tracks which tiles of the map the player can see, and which have been seen before.
+type FogOfWar struct {
+	width, height int
+	radius        float64
+	explored      []bool
+	visible       []bool
+}
+
+// NewFogOfWar creates a fog of war for a map of the given size, in tiles. Every tile starts hidden.
+func NewFogOfWar(width, height int) *FogOfWar {
+	fog := &FogOfWar{radius: DefaultRevealRadius}
+	fog.Reset(width, height)
+
+	return fog
+}
+
+// Reset hides every tile and resizes the fog of war, for when the map is regenerated.
+func (f *FogOfWar) Reset(width, height int) {
+	f.width, f.height = width, height
+	f.explored = make([]bool, width*height)
+	f.visible = make([]bool, width*height)
+}
+
+// SetRevealRadius sets the distance, in tiles, the player can see.
+func (f *FogOfWar) SetRevealRadius(radius float64) {
+	f.radius = radius
+}
+
+// RevealRadius returns the distance, in tiles, the player can see.
+func (f *FogOfWar) RevealRadius() float64 {
+	return f.radius
+}
+
+// Reveal updates the visible tiles for a player at the given location, in tiles. Tiles within the reveal radius
+// become visible and explored, tiles which were visible before but are now out of range remain explored.
+func (f *FogOfWar) Reveal(x, y float64) {
+	for idx := range f.visible {
+		f.visible[idx] = false
+	}
+
+	startX := int(math.Max(0, math.Floor(x-f.radius)))
+	startY := int(math.Max(0, math.Floor(y-f.radius)))
+	endX := int(math.Min(float64(f.width-1), math.Ceil(x+f.radius)))
+	endY := int(math.Min(float64(f.height-1), math.Ceil(y+f.radius)))
+
+	for tileY := startY; tileY <= endY; tileY++ {
+		for tileX := startX; tileX <= endX; tileX++ {
+			// measure to the tile center
+			if math.Hypot(float64(tileX)+0.5-x, float64(tileY)+0.5-y) > f.radius {
+				continue
+			}
+
+			idx := tileX + tileY*f.width
+			f.visible[idx] = true
+			f.explored[idx] = true
+		}
+	}
+}
+
+// IsTileRevealed returns true if the given tile is currently visible.
+func (f *FogOfWar) IsTileRevealed(tileX, tileY int) bool {
+	return f.Visibility(tileX, tileY) == TileVisible
+}
+
+// Visibility returns how much of the given tile the player can see. Tiles outside the map are hidden.
+func (f *FogOfWar) Visibility(tileX, tileY int) TileVisibility {
+	if tileX < 0 || tileY < 0 || tileX >= f.width || tileY >= f.height {
+		return TileHidden
+	}
+
+	idx := tileX + tileY*f.width
+
+	switch {
+	case f.visible[idx]:
+		return TileVisible
+	case f.explored[idx]:
+		return TileExplored
+	default:
+		return TileHidden
+	}
+}
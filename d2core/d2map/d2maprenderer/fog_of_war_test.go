@@ -0,0 +1,48 @@
+package d2maprenderer
+
+import (
+	"testing"
+)
+
+func TestFogOfWarReveal(t *testing.T) {
+	fog := NewFogOfWar(40, 40)
+	fog.SetRevealRadius(5)
+
+	if fog.IsTileRevealed(10, 10) {
+		t.Error("tile (10, 10) before reveal: wanted hidden")
+	}
+
+	fog.Reveal(10.5, 10.5)
+
+	for _, tile := range [][2]int{{10, 10}, {14, 10}, {10, 6}, {13, 13}} {
+		if !fog.IsTileRevealed(tile[0], tile[1]) {
+			t.Errorf("tile %v within radius: wanted revealed", tile)
+		}
+	}
+
+	for _, tile := range [][2]int{{16, 10}, {14, 14}, {30, 30}, {-1, 10}} {
+		if fog.IsTileRevealed(tile[0], tile[1]) {
+			t.Errorf("tile %v outside radius: wanted hidden", tile)
+		}
+	}
+}
+
+func TestFogOfWarExplored(t *testing.T) {
+	fog := NewFogOfWar(40, 40)
+	fog.SetRevealRadius(3)
+
+	fog.Reveal(5.5, 5.5)
+	fog.Reveal(30.5, 30.5)
+
+	if got := fog.Visibility(5, 5); got != TileExplored {
+		t.Errorf("previously seen tile: wanted %d: got %d", TileExplored, got)
+	}
+
+	if got := fog.Visibility(30, 30); got != TileVisible {
+		t.Errorf("tile under the player: wanted %d: got %d", TileVisible, got)
+	}
+
+	if got := fog.Visibility(18, 18); got != TileHidden {
+		t.Errorf("unseen tile: wanted %d: got %d", TileHidden, got)
+	}
+}
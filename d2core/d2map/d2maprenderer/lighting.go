@@ -0,0 +1,183 @@
+package d2maprenderer
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2pl2"
+)
+
+const (
+	// DefaultAmbientLight is the default ambient light level, daytime outdoor areas are fully lit.
+	DefaultAmbientLight = 1.0
+
+	// minTileBrightness is the brightness tiles in complete darkness are drawn with.
+	minTileBrightness = 0.01
+)
+
+// whiteLight is the color of ambient light.
+var whiteLight = color.RGBA{R: 255, G: 255, B: 255, A: 255} //nolint:gochecknoglobals // color.RGBA can't be a const
+
+// LightSource is a light on the map, such as a torch or the light carried by the player.
+type LightSource struct {
+	X, Y   float64 // Location, in tiles
+	Radius float64 // Distance, in tiles, at which the light fades out
+	Color  color.RGBA
+}
+
+// Lighting computes the light level of every tile of the map from the ambient light and the light sources.
+type Lighting struct {
+	width, height int
+	ambient       float64
+	lights        []LightSource
+	levels        []int
+	colors        []color.RGBA
+	levelScales   [][3]float64 // Channel scales of the PL2 light level variations, nil scales by brightness
+}
+
+// NewLighting creates the lighting for a map of the given size, in tiles, lit by the default ambient light.
+func NewLighting(width, height int) *Lighting {
+	lighting := &Lighting{ambient: DefaultAmbientLight}
+	lighting.Reset(width, height)
+
+	return lighting
+}
+
+// Reset removes every light source and resizes the lighting, for when the map is regenerated.
+func (l *Lighting) Reset(width, height int) {
+	l.width, l.height = width, height
+	l.lights = nil
+	l.levels = make([]int, width*height)
+	l.colors = make([]color.RGBA, width*height)
+	l.Update()
+}
+
+// SetAmbient sets the light level of tiles away from any light source, from 0 (dark, like caves) to 1 (fully lit,
+// like daytime outdoor areas). Takes effect when the lighting is next updated.
+func (l *Lighting) SetAmbient(ambient float64) {
+	l.ambient = math.Max(0, math.Min(ambient, 1))
+}
+
+// Ambient returns the light level of tiles away from any light source.
+func (l *Lighting) Ambient() float64 {
+	return l.ambient
+}
+
+// SetPaletteTransform sets the PL2 palette transform of the act, whose light level variations select how tiles are
+// shaded at each light level. Without one, tiles are shaded by the brightness of their light level.
+func (l *Lighting) SetPaletteTransform(pl2 *d2pl2.PL2) {
+	if pl2 == nil {
+		l.levelScales = nil
+		return
+	}
+
+	l.levelScales = make([][3]float64, d2pl2.LightLevelCount)
+
+	for level := range l.levelScales {
+		red, green, blue := pl2.LightLevelScale(level)
+		l.levelScales[level] = [3]float64{red, green, blue}
+	}
+}
+
+// AddLight adds a light source. Takes effect when the lighting is next updated.
+func (l *Lighting) AddLight(light LightSource) {
+	l.lights = append(l.lights, light)
+}
+
+// ClearLights removes every light source. Takes effect when the lighting is next updated.
+func (l *Lighting) ClearLights() {
+	l.lights = nil
+}
+
+// Update computes the light level of every tile. Each light source adds to the ambient light, fading out linearly
+// toward its radius. Tile colors are the light source colors blended by how much each contributes to the tile.
+func (l *Lighting) Update() {
+	for idx := range l.levels {
+		tileX, tileY := idx%l.width, idx/l.width
+		// measure to the tile center
+		centerX, centerY := float64(tileX)+0.5, float64(tileY)+0.5
+
+		brightness := l.ambient
+		red, green, blue := l.ambient, l.ambient, l.ambient
+
+		for _, light := range l.lights {
+			if light.Radius <= 0 {
+				continue
+			}
+
+			intensity := 1 - (math.Hypot(centerX-light.X, centerY-light.Y) / light.Radius)
+			if intensity <= 0 {
+				continue
+			}
+
+			brightness += intensity
+			red += intensity * float64(light.Color.R) / math.MaxUint8
+			green += intensity * float64(light.Color.G) / math.MaxUint8
+			blue += intensity * float64(light.Color.B) / math.MaxUint8
+		}
+
+		l.levels[idx] = d2pl2.LightLevel(brightness)
+		l.colors[idx] = whiteLight
+
+		if brightness > 0 {
+			l.colors[idx] = color.RGBA{
+				R: uint8(math.MaxUint8 * red / brightness),
+				G: uint8(math.MaxUint8 * green / brightness),
+				B: uint8(math.MaxUint8 * blue / brightness),
+				A: math.MaxUint8,
+			}
+		}
+	}
+}
+
+// LightLevel returns the PL2 light level variation the given tile is drawn with, where level 0 is fully lit. Tiles
+// outside the map are lit by the ambient light.
+func (l *Lighting) LightLevel(tileX, tileY int) int {
+	if tileX < 0 || tileY < 0 || tileX >= l.width || tileY >= l.height {
+		return d2pl2.LightLevel(l.ambient)
+	}
+
+	return l.levels[tileX+tileY*l.width]
+}
+
+// Brightness returns the brightness of the light level the given tile is drawn with, from 0 (dark) to 1 (fully lit).
+func (l *Lighting) Brightness(tileX, tileY int) float64 {
+	return d2pl2.LightLevelBrightness(l.LightLevel(tileX, tileY))
+}
+
+// Color returns the color of the light falling on the given tile, white if it is only lit by the ambient light.
+func (l *Lighting) Color(tileX, tileY int) color.RGBA {
+	if tileX < 0 || tileY < 0 || tileX >= l.width || tileY >= l.height {
+		return whiteLight
+	}
+
+	return l.colors[tileX+tileY*l.width]
+}
+
+// Tint returns the color the given tile is shaded with, the color of the light falling on it scaled by its light level
+// variation of the palette transform.
+func (l *Lighting) Tint(tileX, tileY int) color.RGBA {
+	level := l.LightLevel(tileX, tileY)
+	brightness := d2pl2.LightLevelBrightness(level)
+	scales := [3]float64{brightness, brightness, brightness}
+
+	if l.levelScales != nil {
+		scales = l.levelScales[level]
+	}
+
+	lightColor := l.Color(tileX, tileY)
+
+	return color.RGBA{
+		R: scaleChannel(lightColor.R, scales[0]),
+		G: scaleChannel(lightColor.G, scales[1]),
+		B: scaleChannel(lightColor.B, scales[2]),
+		A: math.MaxUint8,
+	}
+}
+
+// scaleChannel scales a color channel, the darkest tiles are drawn barely visible instead of black
+func scaleChannel(channel uint8, scale float64) uint8 {
+	scale = math.Max(minTileBrightness, math.Min(scale, 1))
+
+	return uint8(math.Round(float64(channel) * scale))
+}
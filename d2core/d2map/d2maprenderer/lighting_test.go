@@ -0,0 +1,100 @@
+package d2maprenderer
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2pl2"
+)
+
+func TestLightingFalloff(t *testing.T) {
+	lighting := NewLighting(40, 40)
+	lighting.SetAmbient(0)
+	lighting.AddLight(LightSource{X: 10.5, Y: 10.5, Radius: 8, Color: color.RGBA{R: 255, G: 255, B: 255, A: 255}})
+	lighting.Update()
+
+	near := lighting.Brightness(10, 10)
+	middle := lighting.Brightness(14, 10)
+	far := lighting.Brightness(30, 30)
+
+	if near <= middle || middle <= far {
+		t.Errorf("brightness near, part way and far from the light: wanted decreasing: got %.2f, %.2f, %.2f",
+			near, middle, far)
+	}
+
+	if lighting.LightLevel(10, 10) >= lighting.LightLevel(14, 10) {
+		t.Errorf("light level near the light: wanted lower (brighter) than %d: got %d",
+			lighting.LightLevel(14, 10), lighting.LightLevel(10, 10))
+	}
+
+	if far != 0 {
+		t.Errorf("brightness outside the light radius without ambient light: wanted 0: got %.2f", far)
+	}
+}
+
+func TestLightingAmbient(t *testing.T) {
+	lighting := NewLighting(10, 10)
+
+	if got := lighting.Brightness(5, 5); got != 1 {
+		t.Errorf("default ambient brightness: wanted 1: got %.2f", got)
+	}
+
+	lighting.SetAmbient(0.5)
+	lighting.AddLight(LightSource{X: 2.5, Y: 2.5, Radius: 4, Color: color.RGBA{R: 255, A: 255}})
+	lighting.Update()
+
+	if got := lighting.Brightness(9, 9); got < 0.45 || got > 0.55 {
+		t.Errorf("ambient brightness away from lights: wanted about 0.5: got %.2f", got)
+	}
+
+	if got := lighting.Color(2, 2); got.R <= got.G || got.R <= got.B {
+		t.Errorf("color next to a red light: wanted red: got %v", got)
+	}
+
+	if got := lighting.Color(9, 9); got != whiteLight {
+		t.Errorf("color away from lights: wanted %v: got %v", whiteLight, got)
+	}
+}
+
+func TestLightingPaletteTransform(t *testing.T) {
+	lighting := NewLighting(4, 4)
+	lighting.SetAmbient(0.5)
+	lighting.Update()
+
+	level := lighting.LightLevel(1, 1)
+
+	var pl2 d2pl2.PL2
+
+	pl2.BasePalette.Colors[1] = d2pl2.PL2Color{R: 200, G: 200, B: 200}
+	pl2.BasePalette.Colors[2] = d2pl2.PL2Color{R: 100, G: 100, B: 100}
+
+	for variation := range pl2.LightLevelVariations {
+		for index := range pl2.LightLevelVariations[variation].Indices {
+			pl2.LightLevelVariations[variation].Indices[index] = uint8(index)
+		}
+	}
+
+	// the tile's light level draws the bright color as the dark one
+	pl2.LightLevelVariations[level].Indices[1] = 2
+
+	brightness := uint8(math.Round(255 * d2pl2.LightLevelBrightness(level)))
+	wantBrightness := color.RGBA{R: brightness, G: brightness, B: brightness, A: 255}
+
+	if got := lighting.Tint(1, 1); got != wantBrightness {
+		t.Errorf("tint without a palette transform: wanted %v: got %v", wantBrightness, got)
+	}
+
+	lighting.SetPaletteTransform(&pl2)
+
+	wantTransform := color.RGBA{R: 170, G: 170, B: 170, A: 255}
+	if got := lighting.Tint(1, 1); got != wantTransform {
+		t.Errorf("tint of light level %d variation: wanted %v: got %v", level, wantTransform, got)
+	}
+
+	lighting.SetPaletteTransform(nil)
+
+	if got := lighting.Tint(1, 1); got != wantBrightness {
+		t.Errorf("tint after removing the palette transform: wanted %v: got %v", wantBrightness, got)
+	}
+}
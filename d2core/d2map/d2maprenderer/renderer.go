@@ -8,6 +8,7 @@ import (
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2pl2"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
@@ -24,6 +25,9 @@ type MapRenderer struct {
 	debugVisLevel int                    // Debug visibility index (0=none, 1=tiles, 2=sub-tiles)
 	lastFrameTime float64                // The last time the map was rendered
 	currentFrame  int                    // Current render frame (for animations)
+	fogOfWar      *FogOfWar              // Hides unexplored tiles, nil shows the whole map
+	lighting      *Lighting              // Light levels of tiles, nil draws every tile fully lit
+	lightLevels   *d2pl2.PL2             // The palette transform holding the light levels of this map's palette
 }
 
 // CreateMapRenderer creates a new MapRenderer, sets the required fields and returns a pointer to it.
@@ -91,6 +95,53 @@ func (mr *MapRenderer) Render(target d2interface.Surface) {
 	mr.renderPass4(target, startX, startY, endX, endY)
 }
 
+// SetFogOfWar sets the fog of war used to hide and dim tiles. A nil fog of war shows the whole map.
+func (mr *MapRenderer) SetFogOfWar(fog *FogOfWar) {
+	mr.fogOfWar = fog
+}
+
+// tileVisibility returns the visibility of the given tile according to the fog of war.
+func (mr *MapRenderer) tileVisibility(tileX, tileY int) TileVisibility {
+	if mr.fogOfWar == nil {
+		return TileVisible
+	}
+
+	return mr.fogOfWar.Visibility(tileX, tileY)
+}
+
+// SetLighting sets the lighting used to shade tiles and entities. A nil lighting draws everything fully lit.
+func (mr *MapRenderer) SetLighting(lighting *Lighting) {
+	mr.lighting = lighting
+
+	if lighting != nil {
+		lighting.SetPaletteTransform(mr.lightLevels)
+	}
+}
+
+// pushTileLight tints the target for the PL2 light level variation of the given tile, and dims explored tiles which
+// are out of sight. It returns the number of states pushed, which the caller must pop once the tile is drawn, and
+// false if the tile is hidden and should not be drawn.
+func (mr *MapRenderer) pushTileLight(target d2interface.Surface, tileX, tileY int) (pushed int, visible bool) {
+	visibility := mr.tileVisibility(tileX, tileY)
+	if visibility == TileHidden {
+		return 0, false
+	}
+
+	if mr.lighting != nil {
+		if tint := mr.lighting.Tint(tileX, tileY); tint != whiteLight {
+			target.PushColor(tint)
+			pushed++
+		}
+	}
+
+	if visibility == TileExplored {
+		target.PushBrightness(exploredBrightness)
+		pushed++
+	}
+
+	return pushed, true
+}
+
 // MoveCameraTo sets the position of the camera to the given x and y coordinates.
 func (mr *MapRenderer) MoveCameraTo(x, y float64) {
 	mr.camera.MoveTo(x, y)
@@ -121,10 +172,16 @@ func (mr *MapRenderer) WorldToOrtho(x, y float64) (float64, float64) {
 func (mr *MapRenderer) renderPass1(target d2interface.Surface, startX, startY, endX, endY int) {
 	for tileY := startY; tileY < endY; tileY++ {
 		for tileX := startX; tileX < endX; tileX++ {
+			pushed, visible := mr.pushTileLight(target, tileX, tileY)
+			if !visible {
+				continue
+			}
+
 			tile := mr.mapEngine.TileAt(tileX, tileY)
 			mr.viewport.PushTranslationWorld(float64(tileX), float64(tileY))
 			mr.renderTilePass1(tile, target)
 			mr.viewport.PopTranslation()
+			target.PopN(pushed)
 		}
 	}
 }
@@ -133,6 +190,11 @@ func (mr *MapRenderer) renderPass1(target d2interface.Surface, startX, startY, e
 func (mr *MapRenderer) renderPass2(target d2interface.Surface, startX, startY, endX, endY int) {
 	for tileY := startY; tileY < endY; tileY++ {
 		for tileX := startX; tileX < endX; tileX++ {
+			// entities are only shown within sight
+			if mr.tileVisibility(tileX, tileY) != TileVisible {
+				continue
+			}
+
 			mr.viewport.PushTranslationWorld(float64(tileX), float64(tileY))
 
 			// TODO: Do not loop over every entity every frame
@@ -147,9 +209,7 @@ func (mr *MapRenderer) renderPass2(target d2interface.Surface, startX, startY, e
 					continue
 				}
 
-				target.PushTranslation(mr.viewport.GetTranslationScreen())
-				mapEntity.Render(target)
-				target.Pop()
+				mr.renderEntity(mapEntity, target, tileX, tileY)
 			}
 
 			mr.viewport.PopTranslation()
@@ -157,13 +217,34 @@ func (mr *MapRenderer) renderPass2(target d2interface.Surface, startX, startY, e
 	}
 }
 
+// renderEntity draws an entity standing on the given tile, shaded by the light level of the tile.
+func (mr *MapRenderer) renderEntity(mapEntity d2interface.MapEntity, target d2interface.Surface, tileX, tileY int) {
+	pushed, _ := mr.pushTileLight(target, tileX, tileY)
+
+	target.PushTranslation(mr.viewport.GetTranslationScreen())
+	mapEntity.Render(target)
+	target.PopN(pushed + 1)
+}
+
 // Upper wall tiles and entities above walls.
 func (mr *MapRenderer) renderPass3(target d2interface.Surface, startX, startY, endX, endY int) {
 	for tileY := startY; tileY < endY; tileY++ {
 		for tileX := startX; tileX < endX; tileX++ {
+			pushed, visible := mr.pushTileLight(target, tileX, tileY)
+			if !visible {
+				continue
+			}
+
 			tile := mr.mapEngine.TileAt(tileX, tileY)
 			mr.viewport.PushTranslationWorld(float64(tileX), float64(tileY))
 			mr.renderTilePass2(tile, target)
+			target.PopN(pushed)
+
+			// entities are only shown within sight
+			if mr.tileVisibility(tileX, tileY) != TileVisible {
+				mr.viewport.PopTranslation()
+				continue
+			}
 
 			// TODO: Do not loop over every entity every frame
 			for _, mapEntity := range *mr.mapEngine.Entities() {
@@ -177,9 +258,7 @@ func (mr *MapRenderer) renderPass3(target d2interface.Surface, startX, startY, e
 					continue
 				}
 
-				target.PushTranslation(mr.viewport.GetTranslationScreen())
-				mapEntity.Render(target)
-				target.Pop()
+				mr.renderEntity(mapEntity, target, tileX, tileY)
 			}
 
 			mr.viewport.PopTranslation()
@@ -191,10 +270,16 @@ func (mr *MapRenderer) renderPass3(target d2interface.Surface, startX, startY, e
 func (mr *MapRenderer) renderPass4(target d2interface.Surface, startX, startY, endX, endY int) {
 	for tileY := startY; tileY < endY; tileY++ {
 		for tileX := startX; tileX < endX; tileX++ {
+			pushed, visible := mr.pushTileLight(target, tileX, tileY)
+			if !visible {
+				continue
+			}
+
 			tile := mr.mapEngine.TileAt(tileX, tileY)
 			mr.viewport.PushTranslationWorld(float64(tileX), float64(tileY))
 			mr.renderTilePass3(tile, target)
 			mr.viewport.PopTranslation()
+			target.PopN(pushed)
 		}
 	}
 }
@@ -390,29 +475,45 @@ func (mr *MapRenderer) Advance(elapsed float64) {
 }
 
 func loadPaletteForAct(levelType d2enum.RegionIdType) (d2interface.Palette, error) {
-	var palettePath string
+	palettePath, _, err := actPalettePaths(levelType)
+	if err != nil {
+		return nil, err
+	}
+
+	return d2asset.LoadPalette(palettePath)
+}
 
+// loadPaletteTransformForAct loads the PL2 palette transform of the act palette, which holds its light levels
+func loadPaletteTransformForAct(levelType d2enum.RegionIdType) (*d2pl2.PL2, error) {
+	_, transformPath, err := actPalettePaths(levelType)
+	if err != nil {
+		return nil, err
+	}
+
+	return d2asset.LoadPaletteTransform(transformPath)
+}
+
+// actPalettePaths returns the paths of the palette and the palette transform of the act the region is in
+func actPalettePaths(levelType d2enum.RegionIdType) (palettePath, transformPath string, err error) {
 	switch levelType {
 	case d2enum.RegionAct1Town, d2enum.RegionAct1Wilderness, d2enum.RegionAct1Cave, d2enum.RegionAct1Crypt,
 		d2enum.RegionAct1Monestary, d2enum.RegionAct1Courtyard, d2enum.RegionAct1Barracks,
 		d2enum.RegionAct1Jail, d2enum.RegionAct1Cathedral, d2enum.RegionAct1Catacombs, d2enum.RegionAct1Tristram:
-		palettePath = d2resource.PaletteAct1
+		return d2resource.PaletteAct1, d2resource.PaletteTransformAct1, nil
 	case d2enum.RegionAct2Town, d2enum.RegionAct2Sewer, d2enum.RegionAct2Harem, d2enum.RegionAct2Basement,
 		d2enum.RegionAct2Desert, d2enum.RegionAct2Tomb, d2enum.RegionAct2Lair, d2enum.RegionAct2Arcane:
-		palettePath = d2resource.PaletteAct2
+		return d2resource.PaletteAct2, d2resource.PaletteTransformAct2, nil
 	case d2enum.RegionAct3Town, d2enum.RegionAct3Jungle, d2enum.RegionAct3Kurast, d2enum.RegionAct3Spider,
 		d2enum.RegionAct3Dungeon, d2enum.RegionAct3Sewer:
-		palettePath = d2resource.PaletteAct3
+		return d2resource.PaletteAct3, d2resource.PaletteTransformAct3, nil
 	case d2enum.RegionAct4Town, d2enum.RegionAct4Mesa, d2enum.RegionAct4Lava, d2enum.RegionAct5Lava:
-		palettePath = d2resource.PaletteAct4
+		return d2resource.PaletteAct4, d2resource.PaletteTransformAct4, nil
 	case d2enum.RegonAct5Town, d2enum.RegionAct5Siege, d2enum.RegionAct5Barricade, d2enum.RegionAct5Temple,
 		d2enum.RegionAct5IceCaves, d2enum.RegionAct5Baal:
-		palettePath = d2resource.PaletteAct5
+		return d2resource.PaletteAct5, d2resource.PaletteTransformAct5, nil
 	default:
-		return nil, errors.New("failed to find palette for region")
+		return "", "", errors.New("failed to find palette for region")
 	}
-
-	return d2asset.LoadPalette(palettePath)
 }
 
 // ViewportToLeft moves the viewport to the left.
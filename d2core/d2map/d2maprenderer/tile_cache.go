@@ -12,6 +12,12 @@ import (
 
 func (mr *MapRenderer) generateTileCache() {
 	mr.palette, _ = loadPaletteForAct(d2enum.RegionIdType(mr.mapEngine.LevelType().ID))
+	mr.lightLevels, _ = loadPaletteTransformForAct(d2enum.RegionIdType(mr.mapEngine.LevelType().ID))
+
+	if mr.lighting != nil {
+		mr.lighting.SetPaletteTransform(mr.lightLevels)
+	}
+
 	mapEngineSize := mr.mapEngine.Size()
 
 	for idx, tile := range *mr.mapEngine.Tiles() {
@@ -0,0 +1,78 @@
+package d2batch
+
+// MaxQuads is the most quads in a single batch. Each quad uses 4 vertices, which must be addressable by 16 bit
+// vertex indices.
+const MaxQuads = (1 << 16) / 4
+
+// Quad is a rectangle of the source texture drawn stretched over a rectangle of the target, in pixels.
+type Quad struct {
+	DstX, DstY float32
+	DstWidth   float32
+	DstHeight  float32
+	SrcX0      float32
+	SrcY0      float32
+	SrcX1      float32
+	SrcY1      float32
+}
+
+// SubmitFunc draws a batch of quads which all share the given key.
+type SubmitFunc func(key interface{}, quads []Quad) error
+
+// Batcher collects consecutive draws sharing the same key, and submits them together when the key changes, the batch
+// is full, or Flush is called. A key identifies everything which affects how quads are drawn, for example the source
+// texture and blend mode. Keys must be comparable.
+type Batcher struct {
+	submit      SubmitFunc
+	key         interface{}
+	quads       []Quad
+	submissions int
+}
+
+// NewBatcher creates a Batcher which draws its batches with submit.
+func NewBatcher(submit SubmitFunc) *Batcher {
+	return &Batcher{submit: submit}
+}
+
+// Draw queues a quad to be drawn with the given key. The pending batch is submitted first if its key is different.
+func (b *Batcher) Draw(key interface{}, quad Quad) error {
+	if len(b.quads) > 0 && (b.key != key || len(b.quads) >= MaxQuads) {
+		if err := b.Flush(); err != nil {
+			return err
+		}
+	}
+
+	b.key = key
+	b.quads = append(b.quads, quad)
+
+	return nil
+}
+
+// Flush submits the pending batch, if there is one.
+func (b *Batcher) Flush() error {
+	if len(b.quads) == 0 {
+		return nil
+	}
+
+	key, quads := b.key, b.quads
+
+	b.key = nil
+	b.quads = b.quads[:0]
+	b.submissions++
+
+	return b.submit(key, quads)
+}
+
+// Key returns the key of the pending batch, or nil if there are no pending quads.
+func (b *Batcher) Key() interface{} {
+	return b.key
+}
+
+// Pending returns the number of quads waiting to be submitted.
+func (b *Batcher) Pending() int {
+	return len(b.quads)
+}
+
+// Submissions returns the number of batches submitted so far.
+func (b *Batcher) Submissions() int {
+	return b.submissions
+}
@@ -0,0 +1,109 @@
+package d2batch
+
+import "testing"
+
+type testTexture struct {
+	name string
+}
+
+func TestBatcherSameTexture(t *testing.T) {
+	const sprites = 500
+
+	texture := &testTexture{"tile"}
+	drawn := 0
+
+	batcher := NewBatcher(func(key interface{}, quads []Quad) error {
+		drawn += len(quads)
+		return nil
+	})
+
+	for idx := 0; idx < sprites; idx++ {
+		if err := batcher.Draw(texture, Quad{DstX: float32(idx)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := batcher.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if batcher.Submissions() != 1 {
+		t.Errorf("submissions for %d sprites with the same texture: wanted 1: got %d", sprites, batcher.Submissions())
+	}
+
+	if drawn != sprites {
+		t.Errorf("quads drawn: wanted %d: got %d", sprites, drawn)
+	}
+}
+
+func TestBatcherStateChange(t *testing.T) {
+	textures := []*testTexture{{"floor"}, {"wall"}}
+
+	var order []string
+
+	batcher := NewBatcher(func(key interface{}, quads []Quad) error {
+		for range quads {
+			order = append(order, key.(*testTexture).name)
+		}
+
+		return nil
+	})
+
+	for _, texture := range []int{0, 0, 1, 0} {
+		if err := batcher.Draw(textures[texture], Quad{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if batcher.Submissions() != 2 || batcher.Pending() != 1 {
+		t.Errorf("after drawing floor, floor, wall, floor: wanted 2 submissions and 1 pending: got %d and %d",
+			batcher.Submissions(), batcher.Pending())
+	}
+
+	if err := batcher.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"floor", "floor", "wall", "floor"}
+	for idx := range want {
+		if idx >= len(order) || order[idx] != want[idx] {
+			t.Fatalf("draw order: wanted %v: got %v", want, order)
+		}
+	}
+}
+
+func TestBatcherFull(t *testing.T) {
+	batcher := NewBatcher(func(key interface{}, quads []Quad) error {
+		if len(quads) > MaxQuads {
+			t.Errorf("batch size: wanted at most %d: got %d", MaxQuads, len(quads))
+		}
+
+		return nil
+	})
+
+	for idx := 0; idx <= MaxQuads; idx++ {
+		if err := batcher.Draw(1, Quad{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if batcher.Submissions() != 1 || batcher.Pending() != 1 {
+		t.Errorf("after %d quads: wanted 1 submission and 1 pending: got %d and %d",
+			MaxQuads+1, batcher.Submissions(), batcher.Pending())
+	}
+}
+
+func BenchmarkBatcherSameTexture(b *testing.B) {
+	texture := &testTexture{"tile"}
+	batcher := NewBatcher(func(key interface{}, quads []Quad) error { return nil })
+
+	for n := 0; n < b.N; n++ {
+		for idx := 0; idx < 1000; idx++ {
+			_ = batcher.Draw(texture, Quad{})
+		}
+
+		_ = batcher.Flush()
+	}
+
+	b.ReportMetric(float64(batcher.Submissions())/float64(b.N), "submissions/frame")
+}
@@ -0,0 +1,3 @@
+// Package d2batch groups consecutive draw calls which share a texture and
+// draw state, so a renderer can submit them to the GPU together.
+package d2batch
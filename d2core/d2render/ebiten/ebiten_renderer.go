@@ -15,11 +15,15 @@ type Renderer struct {
 }
 
 func (r *Renderer) Update(screen *ebiten.Image) error {
-	err := r.renderCallback(createEbitenSurface(screen))
+	surface := createEbitenSurface(screen)
+
+	err := r.renderCallback(surface)
 	if err != nil {
 		return err
 	}
-	return nil
+
+	// Offscreen surfaces are flushed as well as the screen, even if nothing reads them
+	return flushQueuedBatches()
 }
 
 func (r *Renderer) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
@@ -63,11 +67,13 @@ func (r *Renderer) Run(f func(surface d2interface.Surface) error, width, height
 }
 
 func (r *Renderer) CreateSurface(surface d2interface.Surface) (d2interface.Surface, error) {
-	result := createEbitenSurface(
-		surface.(*ebitenSurface).image,
+	result := createBatchedSurface(
+		surface.(*ebitenSurface).batch,
 		surfaceState{
 			filter: ebiten.FilterNearest,
 			effect: d2enum.DrawEffectNone,
+			scaleX: 1,
+			scaleY: 1,
 		},
 	)
 
@@ -4,11 +4,13 @@ import (
 	"fmt"
 	"image"
 	"image/color"
+	"log"
 	"math"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2DebugUtil"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2render/d2batch"
 	"github.com/hajimehoshi/ebiten"
 	"github.com/hajimehoshi/ebiten/ebitenutil"
 )
@@ -23,26 +25,243 @@ type colorMCacheEntry struct {
 }
 
 type ebitenSurface struct {
-	stateStack     []surfaceState
-	stateCurrent   surfaceState
+	stateStack   []surfaceState
+	stateCurrent surfaceState
+	image        *ebiten.Image
+	batch        *imageBatch
+}
+
+// batchKey is the draw state shared by every quad in a batch
+type batchKey struct {
+	source     *ebiten.Image
+	filter     ebiten.Filter
+	color      color.Color
+	brightness float64
+	effect     d2enum.DrawEffect
+	clip       image.Rectangle
+	clipped    bool
+	blendMode  d2enum.BlendMode
+}
+
+// imageBatch holds the draws queued on an image, it is shared by every surface drawing on the image
+type imageBatch struct {
 	image          *ebiten.Image
+	batcher        *d2batch.Batcher
+	queued         bool
 	colorMCache    map[colorMCacheKey]*colorMCacheEntry
 	monotonicClock int64
 }
 
+// queuedBatches are the image batches with draws which have not been submitted yet
+var queuedBatches []*imageBatch //nolint:gochecknoglobals // images can be drawn on through any number of surfaces
+
 func createEbitenSurface(img *ebiten.Image, currentState ...surfaceState) *ebitenSurface {
-	state := surfaceState{effect: d2enum.DrawEffectNone}
+	return createBatchedSurface(newImageBatch(img), currentState...)
+}
+
+func createBatchedSurface(batch *imageBatch, currentState ...surfaceState) *ebitenSurface {
+	state := surfaceState{effect: d2enum.DrawEffectNone, scaleX: 1, scaleY: 1}
 	if len(currentState) > 0 {
 		state = currentState[0]
 	}
 
 	return &ebitenSurface{
-		image:        img,
+		image:        batch.image,
+		batch:        batch,
 		stateCurrent: state,
-		colorMCache:  make(map[colorMCacheKey]*colorMCacheEntry),
 	}
 }
 
+func newImageBatch(img *ebiten.Image) *imageBatch {
+	batch := &imageBatch{
+		image:       img,
+		colorMCache: make(map[colorMCacheKey]*colorMCacheEntry),
+	}
+
+	batch.batcher = d2batch.NewBatcher(batch.submit)
+
+	return batch
+}
+
+// draw queues a quad to be drawn onto the image
+func (b *imageBatch) draw(key batchKey, quad d2batch.Quad) error {
+	if !b.queued {
+		b.queued = true
+		queuedBatches = append(queuedBatches, b)
+	}
+
+	return b.batcher.Draw(key, quad)
+}
+
+// flush draws the queued quads onto the image
+func (b *imageBatch) flush() error {
+	if !b.queued {
+		return nil
+	}
+
+	b.queued = false
+
+	for idx := range queuedBatches {
+		if queuedBatches[idx] == b {
+			queuedBatches = append(queuedBatches[:idx], queuedBatches[idx+1:]...)
+			break
+		}
+	}
+
+	return b.batcher.Flush()
+}
+
+// flushQueuedBatches draws the queued quads of every image. It is called at the end of every frame, so the draws on
+// surfaces which are never read or cleared don't stay queued, keeping the surfaces alive.
+func flushQueuedBatches() error {
+	for len(queuedBatches) > 0 {
+		if err := queuedBatches[0].flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// invalidate flushes the batches using this image as their source, it must be called before the image is changed
+func (b *imageBatch) invalidate() error {
+	for idx := 0; idx < len(queuedBatches); idx++ {
+		reader := queuedBatches[idx]
+		if reader == b || reader.batcher.Key().(batchKey).source != b.image {
+			continue
+		}
+
+		if err := reader.flush(); err != nil {
+			return err
+		}
+
+		idx--
+	}
+
+	return nil
+}
+
+// beginDraw prepares the image to be changed by a draw which isn't batched
+func (b *imageBatch) beginDraw() error {
+	if err := b.invalidate(); err != nil {
+		return err
+	}
+
+	return b.flush()
+}
+
+// submit draws a batch of quads onto the image with a single draw call
+func (b *imageBatch) submit(key interface{}, quads []d2batch.Quad) error {
+	state := key.(batchKey)
+
+	target := b.image
+	if state.clipped {
+		target = b.image.SubImage(state.clip).(*ebiten.Image)
+	}
+
+	vertices := make([]ebiten.Vertex, 0, len(quads)*4)
+	indices := make([]uint16, 0, len(quads)*6)
+
+	for idx := range quads {
+		quad := &quads[idx]
+		dstX1 := quad.DstX + quad.DstWidth
+		dstY1 := quad.DstY + quad.DstHeight
+		first := uint16(len(vertices))
+
+		vertices = append(vertices,
+			quadVertex(quad.DstX, quad.DstY, quad.SrcX0, quad.SrcY0),
+			quadVertex(dstX1, quad.DstY, quad.SrcX1, quad.SrcY0),
+			quadVertex(quad.DstX, dstY1, quad.SrcX0, quad.SrcY1),
+			quadVertex(dstX1, dstY1, quad.SrcX1, quad.SrcY1),
+		)
+		indices = append(indices, first, first+1, first+2, first+1, first+3, first+2)
+	}
+
+	colorM, compositeMode := b.drawOptions(&state)
+
+	target.DrawTriangles(vertices, indices, state.source, &ebiten.DrawTrianglesOptions{
+		ColorM:        colorM,
+		CompositeMode: compositeMode,
+		Filter:        state.filter,
+	})
+
+	return nil
+}
+
+func quadVertex(dstX, dstY, srcX, srcY float32) ebiten.Vertex {
+	return ebiten.Vertex{
+		DstX:   dstX,
+		DstY:   dstY,
+		SrcX:   srcX,
+		SrcY:   srcY,
+		ColorR: 1,
+		ColorG: 1,
+		ColorB: 1,
+		ColorA: 1,
+	}
+}
+
+// drawOptions returns the color matrix and composite mode quads are drawn with
+func (b *imageBatch) drawOptions(state *batchKey) (ebiten.ColorM, ebiten.CompositeMode) {
+	colorM := ebiten.ColorM{}
+	compositeMode := ebiten.CompositeModeSourceOver
+
+	if state.color != nil {
+		colorM = b.colorToColorM(state.color)
+	}
+
+	if state.brightness != 0 {
+		colorM.ChangeHSV(0, 1, state.brightness)
+	}
+
+	// Are these correct? who even knows
+	switch state.effect {
+	case d2enum.DrawEffectPctTransparency25:
+		colorM.Translate(0, 0, 0, -0.25)
+	case d2enum.DrawEffectPctTransparency50:
+		colorM.Translate(0, 0, 0, -0.50)
+	case d2enum.DrawEffectPctTransparency75:
+		colorM.Translate(0, 0, 0, -0.75)
+	case d2enum.DrawEffectModulate:
+		compositeMode = ebiten.CompositeModeLighter
+	// TODO: idk what to do when ebiten doesn't exactly match, pick closest?
+	case d2enum.DrawEffectBurn:
+	case d2enum.DrawEffectNormal:
+	case d2enum.DrawEffectMod2XTrans:
+	case d2enum.DrawEffectMod2X:
+	case d2enum.DrawEffectNone:
+		compositeMode = ebiten.CompositeModeSourceOver
+	}
+
+	switch state.blendMode {
+	case d2enum.BlendModeAdditive:
+		compositeMode = ebiten.CompositeModeLighter
+	case d2enum.BlendModeMultiply:
+		colorM.Concat(multiplyColorM())
+		compositeMode = ebiten.CompositeModeDestinationIn
+	case d2enum.BlendModeNormal:
+	}
+
+	return colorM, compositeMode
+}
+
+// multiplyColorM moves the brightness of the source into its alpha, for drawing with CompositeModeDestinationIn.
+// That scales the destination by the source, which multiplies by the source's brightness as this version of ebiten
+// has no multiply composite mode, so the source's hue is lost. Transparent source pixels become opaque, so they
+// leave the destination unchanged.
+func multiplyColorM() ebiten.ColorM {
+	colorM := ebiten.ColorM{}
+
+	// alpha = 1 - a + luminance, which is the luminance of opaque pixels and 1 for transparent ones
+	colorM.SetElement(3, 0, 0.299)
+	colorM.SetElement(3, 1, 0.587)
+	colorM.SetElement(3, 2, 0.114)
+	colorM.SetElement(3, 3, -1)
+	colorM.SetElement(3, 4, 1)
+
+	return colorM
+}
+
 func (s *ebitenSurface) PushTranslation(x, y int) {
 	s.stateStack = append(s.stateStack, s.stateCurrent)
 	s.stateCurrent.x += x
@@ -69,6 +288,45 @@ func (s *ebitenSurface) PushBrightness(brightness float64) {
 	s.stateCurrent.brightness = brightness
 }
 
+func (s *ebitenSurface) PushScale(scaleX, scaleY float64) {
+	s.stateStack = append(s.stateStack, s.stateCurrent)
+	s.stateCurrent.scaleX *= scaleX
+	s.stateCurrent.scaleY *= scaleY
+}
+
+func (s *ebitenSurface) PushClip(bounds image.Rectangle) {
+	s.stateStack = append(s.stateStack, s.stateCurrent)
+
+	bounds = bounds.Add(image.Point{X: s.stateCurrent.x, Y: s.stateCurrent.y})
+	if s.stateCurrent.clipped {
+		bounds = bounds.Intersect(s.stateCurrent.clip)
+	}
+
+	s.stateCurrent.clip = bounds
+	s.stateCurrent.clipped = true
+}
+
+func (s *ebitenSurface) SetBlendMode(mode d2enum.BlendMode) {
+	s.stateCurrent.blendMode = mode
+}
+
+func (s *ebitenSurface) GetBlendMode() d2enum.BlendMode {
+	return s.stateCurrent.blendMode
+}
+
+// target returns the image to draw on, restricted to the clip bounds. It returns nil if nothing can be drawn.
+func (s *ebitenSurface) target() *ebiten.Image {
+	if !s.stateCurrent.clipped {
+		return s.image
+	}
+
+	if s.stateCurrent.clip.Empty() {
+		return nil
+	}
+
+	return s.image.SubImage(s.stateCurrent.clip).(*ebiten.Image)
+}
+
 func (s *ebitenSurface) Pop() {
 	count := len(s.stateStack)
 	if count == 0 {
@@ -86,87 +344,83 @@ func (s *ebitenSurface) PopN(n int) {
 }
 
 func (s *ebitenSurface) Render(sfc d2interface.Surface) error {
-	opts := &ebiten.DrawImageOptions{}
-	opts.GeoM.Translate(float64(s.stateCurrent.x), float64(s.stateCurrent.y))
-	opts.Filter = s.stateCurrent.filter
-
-	if s.stateCurrent.color != nil {
-		opts.ColorM = s.colorToColorM(s.stateCurrent.color)
-	}
-
-	if s.stateCurrent.brightness != 0 {
-		opts.ColorM.ChangeHSV(0, 1, s.stateCurrent.brightness)
-	}
-
-	// Are these correct? who even knows
-	switch s.stateCurrent.effect {
-	case d2enum.DrawEffectPctTransparency25:
-		opts.ColorM.Translate(0, 0, 0, -0.25)
-	case d2enum.DrawEffectPctTransparency50:
-		opts.ColorM.Translate(0, 0, 0, -0.50)
-	case d2enum.DrawEffectPctTransparency75:
-		opts.ColorM.Translate(0, 0, 0, -0.75)
-	case d2enum.DrawEffectModulate:
-		opts.CompositeMode = ebiten.CompositeModeLighter
-	// TODO: idk what to do when ebiten doesn't exactly match, pick closest?
-	case d2enum.DrawEffectBurn:
-	case d2enum.DrawEffectNormal:
-	case d2enum.DrawEffectMod2XTrans:
-	case d2enum.DrawEffectMod2X:
-	case d2enum.DrawEffectNone:
-		opts.CompositeMode = ebiten.CompositeModeSourceOver
-	}
-
-	var img = sfc.(*ebitenSurface).image
-
-	return s.image.DrawImage(img, opts)
+	return s.renderSection(sfc.(*ebitenSurface), sfc.(*ebitenSurface).image.Bounds())
 }
 
 // Renders the section of the animation frame enclosed by bounds
 func (s *ebitenSurface) RenderSection(sfc d2interface.Surface, bound image.Rectangle) error {
-	opts := &ebiten.DrawImageOptions{}
-	opts.GeoM.Translate(float64(s.stateCurrent.x), float64(s.stateCurrent.y))
-	opts.Filter = s.stateCurrent.filter
+	return s.renderSection(sfc.(*ebitenSurface), bound)
+}
 
-	if s.stateCurrent.color != nil {
-		opts.ColorM = s.colorToColorM(s.stateCurrent.color)
+// renderSection queues the section of the source enclosed by bounds to be drawn. Consecutive draws with the same
+// source and draw state are submitted together.
+func (s *ebitenSurface) renderSection(source *ebitenSurface, bound image.Rectangle) error {
+	if s.stateCurrent.clipped && s.stateCurrent.clip.Empty() {
+		return nil
 	}
 
-	if s.stateCurrent.brightness != 0 {
-		opts.ColorM.ChangeHSV(0, 1, s.stateCurrent.brightness)
+	// Draws queued on the source must land before it is read, and draws reading this surface before it changes
+	if err := source.batch.flush(); err != nil {
+		return err
 	}
 
-	// Are these correct? who even knows
-	switch s.stateCurrent.effect {
-	case d2enum.DrawEffectPctTransparency25:
-		opts.ColorM.Translate(0, 0, 0, -0.25)
-	case d2enum.DrawEffectPctTransparency50:
-		opts.ColorM.Translate(0, 0, 0, -0.50)
-	case d2enum.DrawEffectPctTransparency75:
-		opts.ColorM.Translate(0, 0, 0, -0.75)
-	case d2enum.DrawEffectModulate:
-		opts.CompositeMode = ebiten.CompositeModeLighter
-	// TODO: idk what to do when ebiten doesn't exactly match, pick closest?
-	case d2enum.DrawEffectBurn:
-	case d2enum.DrawEffectNormal:
-	case d2enum.DrawEffectMod2XTrans:
-	case d2enum.DrawEffectMod2X:
-	case d2enum.DrawEffectNone:
-		opts.CompositeMode = ebiten.CompositeModeSourceOver
+	if err := s.batch.invalidate(); err != nil {
+		return err
+	}
+
+	key := batchKey{
+		source:     source.image,
+		filter:     s.stateCurrent.filter,
+		color:      s.stateCurrent.color,
+		brightness: s.stateCurrent.brightness,
+		effect:     s.stateCurrent.effect,
+		clip:       s.stateCurrent.clip,
+		clipped:    s.stateCurrent.clipped,
+		blendMode:  s.stateCurrent.blendMode,
 	}
 
-	var img = sfc.(*ebitenSurface).image
+	return s.batch.draw(key, d2batch.Quad{
+		DstX:      float32(s.stateCurrent.x),
+		DstY:      float32(s.stateCurrent.y),
+		DstWidth:  float32(float64(bound.Dx()) * s.stateCurrent.scaleX),
+		DstHeight: float32(float64(bound.Dy()) * s.stateCurrent.scaleY),
+		SrcX0:     float32(bound.Min.X),
+		SrcY0:     float32(bound.Min.Y),
+		SrcX1:     float32(bound.Max.X),
+		SrcY1:     float32(bound.Max.Y),
+	})
+}
 
-	return s.image.DrawImage(img.SubImage(bound).(*ebiten.Image), opts)
+// Flush draws everything queued on the surface.
+func (s *ebitenSurface) Flush() error {
+	return s.batch.flush()
 }
 
 func (s *ebitenSurface) DrawText(format string, params ...interface{}) {
-	d2DebugUtil.D2DebugPrintAt(s.image, fmt.Sprintf(format, params...), s.stateCurrent.x, s.stateCurrent.y)
+	if err := s.batch.beginDraw(); err != nil {
+		log.Print(err)
+	}
+
+	target := s.target()
+	if target == nil {
+		return
+	}
+
+	d2DebugUtil.D2DebugPrintAt(target, fmt.Sprintf(format, params...), s.stateCurrent.x, s.stateCurrent.y)
 }
 
 func (s *ebitenSurface) DrawLine(x, y int, color color.Color) {
+	if err := s.batch.beginDraw(); err != nil {
+		log.Print(err)
+	}
+
+	target := s.target()
+	if target == nil {
+		return
+	}
+
 	ebitenutil.DrawLine(
-		s.image,
+		target,
 		float64(s.stateCurrent.x),
 		float64(s.stateCurrent.y),
 		float64(s.stateCurrent.x+x),
@@ -176,8 +430,17 @@ func (s *ebitenSurface) DrawLine(x, y int, color color.Color) {
 }
 
 func (s *ebitenSurface) DrawRect(width, height int, color color.Color) {
+	if err := s.batch.beginDraw(); err != nil {
+		log.Print(err)
+	}
+
+	target := s.target()
+	if target == nil {
+		return
+	}
+
 	ebitenutil.DrawRect(
-		s.image,
+		target,
 		float64(s.stateCurrent.x),
 		float64(s.stateCurrent.y),
 		float64(width),
@@ -187,6 +450,10 @@ func (s *ebitenSurface) DrawRect(width, height int, color color.Color) {
 }
 
 func (s *ebitenSurface) Clear(color color.Color) error {
+	if err := s.batch.beginDraw(); err != nil {
+		return err
+	}
+
 	return s.image.Fill(color)
 }
 
@@ -199,10 +466,18 @@ func (s *ebitenSurface) GetDepth() int {
 }
 
 func (s *ebitenSurface) ReplacePixels(pixels []byte) error {
+	if err := s.batch.beginDraw(); err != nil {
+		return err
+	}
+
 	return s.image.ReplacePixels(pixels)
 }
 
 func (s *ebitenSurface) Screenshot() *image.RGBA {
+	if err := s.batch.flush(); err != nil {
+		log.Print(err)
+	}
+
 	width, height := s.GetSize()
 	bounds := image.Rectangle{Min: image.Point{X: 0, Y: 0}, Max: image.Point{X: width, Y: height}}
 	rgba := image.NewRGBA(bounds)
@@ -216,13 +491,13 @@ func (s *ebitenSurface) Screenshot() *image.RGBA {
 	return rgba
 }
 
-func (s *ebitenSurface) now() int64 {
-	s.monotonicClock++
-	return s.monotonicClock
+func (b *imageBatch) now() int64 {
+	b.monotonicClock++
+	return b.monotonicClock
 }
 
 // colorToColorM converts a normal color to a color matrix
-func (s *ebitenSurface) colorToColorM(clr color.Color) ebiten.ColorM {
+func (b *imageBatch) colorToColorM(clr color.Color) ebiten.ColorM {
 	// RGBA() is in [0 - 0xffff]. Adjust them in [0 - 0xff].
 	cr, cg, cb, ca := clr.RGBA()
 	cr >>= 8
@@ -236,21 +511,21 @@ func (s *ebitenSurface) colorToColorM(clr color.Color) ebiten.ColorM {
 		return emptyColorM
 	}
 	key := colorMCacheKey(cr | (cg << 8) | (cb << 16) | (ca << 24))
-	e, ok := s.colorMCache[key]
+	e, ok := b.colorMCache[key]
 	if ok {
-		e.atime = s.now()
+		e.atime = b.now()
 		return e.colorMatrix
 	}
-	if len(s.colorMCache) > cacheLimit {
+	if len(b.colorMCache) > cacheLimit {
 		oldest := int64(math.MaxInt64)
 		oldestKey := colorMCacheKey(0)
-		for key, c := range s.colorMCache {
+		for key, c := range b.colorMCache {
 			if c.atime < oldest {
 				oldestKey = key
 				oldest = c.atime
 			}
 		}
-		delete(s.colorMCache, oldestKey)
+		delete(b.colorMCache, oldestKey)
 	}
 
 	cm := ebiten.ColorM{}
@@ -261,9 +536,9 @@ func (s *ebitenSurface) colorToColorM(clr color.Color) ebiten.ColorM {
 	cm.Scale(rf, gf, bf, af)
 	e = &colorMCacheEntry{
 		colorMatrix: cm,
-		atime:       s.now(),
+		atime:       b.now(),
 	}
-	s.colorMCache[key] = e
+	b.colorMCache[key] = e
 
 	return e.colorMatrix
 }
@@ -0,0 +1,45 @@
+package ebiten
+
+import (
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2render/d2batch"
+)
+
+// newTestBatch creates an image batch which counts the quads it submits instead of drawing them
+func newTestBatch(submitted *int) *imageBatch {
+	batch := &imageBatch{}
+	batch.batcher = d2batch.NewBatcher(func(key interface{}, quads []d2batch.Quad) error {
+		*submitted += len(quads)
+		return nil
+	})
+
+	return batch
+}
+
+func TestFlushQueuedBatches(t *testing.T) {
+	var screenQuads, offscreenQuads int
+
+	screen := newTestBatch(&screenQuads)
+	offscreen := newTestBatch(&offscreenQuads)
+
+	// the offscreen surface is drawn on but never read or cleared
+	for _, batch := range []*imageBatch{offscreen, screen} {
+		if err := batch.draw(batchKey{}, d2batch.Quad{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := flushQueuedBatches(); err != nil {
+		t.Fatal(err)
+	}
+
+	if screenQuads != 1 || offscreenQuads != 1 {
+		t.Errorf("quads submitted at the end of the frame: wanted %d on screen and %d offscreen: got %d and %d",
+			1, 1, screenQuads, offscreenQuads)
+	}
+
+	if len(queuedBatches) != 0 || screen.queued || offscreen.queued {
+		t.Errorf("batches queued after the end of the frame: wanted none: got %d", len(queuedBatches))
+	}
+}
@@ -1,6 +1,7 @@
 package ebiten
 
 import (
+	"image"
 	"image/color"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
@@ -13,5 +14,10 @@ type surfaceState struct {
 	filter     ebiten.Filter
 	color      color.Color
 	brightness float64
+	scaleX     float64
+	scaleY     float64
 	effect     d2enum.DrawEffect
+	clip       image.Rectangle
+	clipped    bool
+	blendMode  d2enum.BlendMode
 }
@@ -0,0 +1,48 @@
+package d2term
+
+import (
+	"testing"
+)
+
+func TestTerminalVariadicAction(t *testing.T) {
+	term, err := createTerminal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		gotName  string
+		gotCount []int
+	)
+
+	err = term.BindAction("spawn", "test action", func(name string, count ...int) {
+		gotName, gotCount = name, count
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := term.Execute("spawn zombie1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotName != "zombie1" || len(gotCount) != 0 {
+		t.Errorf("without the optional argument: wanted zombie1 []: got %s %v", gotName, gotCount)
+	}
+
+	if err := term.Execute("spawn zombie2 3"); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotName != "zombie2" || len(gotCount) != 1 || gotCount[0] != 3 {
+		t.Errorf("with the optional argument: wanted zombie2 [3]: got %s %v", gotName, gotCount)
+	}
+
+	if err := term.Execute("spawn"); err == nil {
+		t.Error("without the required argument: wanted an error")
+	}
+
+	if err := term.Execute("spawn zombie3 many"); err == nil {
+		t.Error("with a malformed optional argument: wanted an error")
+	}
+}
@@ -32,7 +32,7 @@ var singleton UI
 var clickSfx d2interface.SoundEffect
 
 func Initialize(audioProvider d2interface.AudioProvider) {
-	sfx, err := audioProvider.LoadSoundEffect(d2resource.SFXButtonClick)
+	sfx, err := audioProvider.LoadSoundEffect(d2resource.SFXButtonClick, d2enum.SoundCategoryUI)
 	if err != nil {
 		log.Fatalf("failed to initialize ui: %v", err)
 	}
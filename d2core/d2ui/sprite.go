@@ -16,6 +16,7 @@ type Sprite struct {
 	x         int
 	y         int
 	animation d2interface.Animation
+	blendMode d2enum.BlendMode
 }
 
 var (
@@ -30,6 +31,8 @@ func LoadSprite(animation d2interface.Animation) (*Sprite, error) {
 }
 
 func (s *Sprite) Render(target d2interface.Surface) error {
+	defer s.useBlendMode(target)()
+
 	_, frameHeight := s.animation.GetCurrentFrameSize()
 
 	target.PushTranslation(s.x, s.y-frameHeight)
@@ -40,6 +43,8 @@ func (s *Sprite) Render(target d2interface.Surface) error {
 
 // RenderSection renders the section of the sprite enclosed by bounds
 func (s *Sprite) RenderSection(sfc d2interface.Surface, bound image.Rectangle) error {
+	defer s.useBlendMode(sfc)()
+
 	sfc.PushTranslation(s.x, s.y-bound.Dy())
 	defer sfc.Pop()
 
@@ -47,6 +52,8 @@ func (s *Sprite) RenderSection(sfc d2interface.Surface, bound image.Rectangle) e
 }
 
 func (s *Sprite) RenderSegmented(target d2interface.Surface, segmentsX, segmentsY, frameOffset int) error {
+	defer s.useBlendMode(target)()
+
 	var currentY int
 
 	for y := 0; y < segmentsY; y++ {
@@ -77,6 +84,26 @@ func (s *Sprite) RenderSegmented(target d2interface.Surface, segmentsX, segments
 	return nil
 }
 
+// SetBlendMode sets how the sprite is combined with what is drawn beneath it, for example additive for glows
+func (s *Sprite) SetBlendMode(mode d2enum.BlendMode) {
+	s.blendMode = mode
+}
+
+// GetBlendMode returns how the sprite is combined with what is drawn beneath it
+func (s *Sprite) GetBlendMode() d2enum.BlendMode {
+	return s.blendMode
+}
+
+// useBlendMode sets the sprite's blend mode on the target and returns a function which restores the previous mode
+func (s *Sprite) useBlendMode(target d2interface.Surface) func() {
+	previous := target.GetBlendMode()
+	target.SetBlendMode(s.blendMode)
+
+	return func() {
+		target.SetBlendMode(previous)
+	}
+}
+
 // SetPosition places the sprite in 2D
 func (s *Sprite) SetPosition(x, y int) {
 	s.x = x
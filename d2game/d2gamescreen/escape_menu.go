@@ -323,7 +323,7 @@ func (m *EscapeMenu) addEnumLabel(l *layout, optID optionID, text string, values
 }
 
 func (m *EscapeMenu) onLoad() {
-	m.selectSound, _ = m.audioProvider.LoadSoundEffect(d2resource.SFXCursorSelect)
+	m.selectSound, _ = m.audioProvider.LoadSoundEffect(d2resource.SFXCursorSelect, d2enum.SoundCategoryUI)
 }
 
 func (m *EscapeMenu) onEscKey() {
@@ -18,12 +18,27 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2script"
 )
 
-const hideZoneTextAfterSeconds = 2.0
+const (
+	hideZoneTextAfterSeconds = 2.0
+	areaMusicFadeDuration    = 2.0
+
+	// playerLightRadius is the distance, in tiles, lit by the light the player carries
+	playerLightRadius = 8.0
+
+	// maxMoveSnapRadius is the furthest, in tiles, a move order on a tile which can't be walked on is moved to reach
+	// one which can
+	maxMoveSnapRadius = 8
+)
+
+// playerLightColor is the color of the light the player carries, a warm torch light
+var playerLightColor = color.RGBA{R: 255, G: 230, B: 190, A: 255} //nolint:gochecknoglobals // color.RGBA can't be a const
 
 // Game represents the Gameplay screen
 type Game struct {
 	gameClient           *d2client.GameClient
 	mapRenderer          *d2maprenderer.MapRenderer
+	fogOfWar             *d2maprenderer.FogOfWar
+	lighting             *d2maprenderer.Lighting
 	gameControls         *d2player.GameControls // TODO: Hack
 	localPlayer          *d2mapentity.Player
 	lastRegionType       d2enum.RegionIdType
@@ -33,11 +48,14 @@ type Game struct {
 	renderer      d2interface.Renderer
 	audioProvider d2interface.AudioProvider
 	terminal      d2interface.Terminal
+	scriptEngine  *d2script.ScriptEngine
 }
 
 // CreateGame creates the Gameplay screen and returns a pointer to it
 func CreateGame(renderer d2interface.Renderer, audioProvider d2interface.AudioProvider, gameClient *d2client.GameClient,
 	term d2interface.Terminal, scriptEngine *d2script.ScriptEngine) *Game {
+	mapSize := gameClient.MapEngine.Size()
+
 	result := &Game{
 		gameClient:           gameClient,
 		gameControls:         nil,
@@ -45,12 +63,26 @@ func CreateGame(renderer d2interface.Renderer, audioProvider d2interface.AudioPr
 		lastRegionType:       d2enum.RegionNone,
 		ticksSinceLevelCheck: 0,
 		mapRenderer:          d2maprenderer.CreateMapRenderer(renderer, gameClient.MapEngine, term),
+		fogOfWar:             d2maprenderer.NewFogOfWar(mapSize.Width, mapSize.Height),
+		lighting:             d2maprenderer.NewLighting(mapSize.Width, mapSize.Height),
 		escapeMenu:           NewEscapeMenu(renderer, audioProvider, term, scriptEngine),
 		audioProvider:        audioProvider,
 		renderer:             renderer,
 		terminal:             term,
+		scriptEngine:         scriptEngine,
+	}
+	result.mapRenderer.SetFogOfWar(result.fogOfWar)
+	result.mapRenderer.SetLighting(result.lighting)
+
+	if err := term.BindAction("ambient", "set the ambient light level, from 0 (dark) to 1 (fully lit)",
+		func(level float64) {
+			result.lighting.SetAmbient(level)
+		}); err != nil {
+		fmt.Println("failed to bind the ambient command")
 	}
+
 	result.escapeMenu.onLoad()
+	scriptEngine.BindEntityQueries(gameClient.MapEngine)
 
 	if err := d2input.BindHandler(result.escapeMenu); err != nil {
 		fmt.Println("failed to add gameplay screen as event handler")
@@ -66,6 +98,16 @@ func (v *Game) OnLoad(_ d2screen.LoadingState) {
 
 // OnUnload releases the resources of Gameplay screen
 func (v *Game) OnUnload() error {
+	if v.gameControls != nil {
+		v.gameControls.SaveAutomap()
+	}
+
+	if v.localPlayer != nil && v.gameClient.GameState != nil {
+		position := v.localPlayer.SavePosition()
+		v.gameClient.GameState.Position = &position
+		v.gameClient.GameState.Save()
+	}
+
 	if err := d2input.UnbindHandler(v.gameControls); err != nil { // TODO: hack
 		return err
 	}
@@ -74,6 +116,13 @@ func (v *Game) OnUnload() error {
 		return err
 	}
 
+	if err := v.terminal.UnbindAction("ambient"); err != nil {
+		return err
+	}
+
+	// callbacks bound to the game's entities must not outlive them
+	v.scriptEngine.ReleaseCallbacks()
+
 	if err := v.gameClient.Close(); err != nil {
 		return err
 	}
@@ -86,6 +135,14 @@ func (v *Game) Render(screen d2interface.Surface) error {
 	if v.gameClient.RegenMap {
 		v.gameClient.RegenMap = false
 		v.mapRenderer.RegenerateTileCache()
+
+		mapSize := v.gameClient.MapEngine.Size()
+		v.fogOfWar.Reset(mapSize.Width, mapSize.Height)
+		v.lighting.Reset(mapSize.Width, mapSize.Height)
+
+		if v.gameControls != nil {
+			v.gameControls.ResetAutomap()
+		}
 	}
 
 	if err := screen.Clear(color.Black); err != nil {
@@ -120,7 +177,7 @@ func (v *Game) Advance(tickTime float64) error {
 			tile := v.gameClient.MapEngine.TileAt(v.localPlayer.TileX, v.localPlayer.TileY)
 			if tile != nil {
 				musicInfo := d2common.GetMusicDef(tile.RegionType)
-				v.audioProvider.PlayBGM(musicInfo.MusicFile)
+				v.audioProvider.CrossfadeTo(musicInfo.MusicFile, areaMusicFadeDuration)
 
 				// skip showing zone change text the first time we enter the world
 				if v.lastRegionType != d2enum.RegionNone && v.lastRegionType != tile.RegionType {
@@ -146,6 +203,21 @@ func (v *Game) Advance(tickTime float64) error {
 		v.mapRenderer.MoveCameraTo(rx, ry)
 	}
 
+	// Positional sounds are heard from the player, who also lifts the fog of war and carries a light around them
+	if v.localPlayer != nil {
+		v.audioProvider.SetListenerPosition(v.localPlayer.LocationX/5, v.localPlayer.LocationY/5)
+		v.fogOfWar.Reveal(v.localPlayer.LocationX/5, v.localPlayer.LocationY/5)
+
+		v.lighting.ClearLights()
+		v.lighting.AddLight(d2maprenderer.LightSource{
+			X:      v.localPlayer.LocationX / 5,
+			Y:      v.localPlayer.LocationY / 5,
+			Radius: playerLightRadius,
+			Color:  playerLightColor,
+		})
+		v.lighting.Update()
+	}
+
 	return nil
 }
 
@@ -167,8 +239,20 @@ func (v *Game) bindGameControls() {
 	}
 }
 
-// OnPlayerMove sends the player move action to the server
+// OnPlayerMove sends the player move action to the server. Clicking a tile which can't be walked on, such as a wall,
+// moves the player to the closest tile which can.
 func (v *Game) OnPlayerMove(x, y float64) {
+	mapEngine := v.gameClient.MapEngine
+
+	if tileX, tileY := int(x), int(y); !mapEngine.IsTileWalkable(tileX, tileY) {
+		nearestX, nearestY, ok := d2common.NearestWalkable(tileX, tileY, maxMoveSnapRadius, mapEngine.IsTileWalkable)
+		if !ok {
+			return
+		}
+
+		x, y = float64(nearestX)+0.5, float64(nearestY)+0.5
+	}
+
 	heroPosX := v.localPlayer.LocationX / 5.0
 	heroPosY := v.localPlayer.LocationY / 5.0
 
@@ -0,0 +1,294 @@
+package d2player
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+const (
+	// maxSpawnCount is the most monsters the spawn command creates at once
+	maxSpawnCount = 50
+
+	// spawnRadius is the distance, in sub-tiles, from the hero at which monsters are spawned
+	spawnRadius = 10
+
+	// pathMaxCost is the maximum cost of paths found by the path command
+	pathMaxCost = 500
+
+	// pathMaxIterations is the number of sub-tiles the path command searches before settling for a truncated path
+	pathMaxIterations = 10000
+)
+
+var errNoMonStats2 = errors.New("monster has no monstats2 record")
+
+// monsterFactory creates a monster entity at the given location, in sub-tiles
+type monsterFactory func(x, y int, monstat *d2datadict.MonStatsRecord) (d2interface.MapEntity, error)
+
+// pathEntity is a map entity which can be told to walk a path
+type pathEntity interface {
+	d2interface.MapEntity
+	ID() uint64
+	SetPath(path []d2astar.Pather, done func())
+	ClearPath()
+}
+
+// pathRequest is a path search started by the path command which has not finished yet
+type pathRequest struct {
+	entity  pathEntity
+	targetX float64
+	targetY float64
+	results <-chan d2astar.PathResult
+	cancel  context.CancelFunc
+}
+
+// debugCommands holds the terminal commands used to debug the map and its entities
+type debugCommands struct {
+	term          d2interface.Terminal
+	hero          *d2mapentity.Player
+	mapEngine     *d2mapengine.MapEngine
+	createMonster monsterFactory
+	pendingPath   *pathRequest
+}
+
+// bindDebugCommands binds the map debugging commands to the terminal, replacing those bound for any previous hero
+func bindDebugCommands(term d2interface.Terminal, hero *d2mapentity.Player,
+	mapEngine *d2mapengine.MapEngine) (*debugCommands, error) {
+	commands := &debugCommands{
+		term:          term,
+		hero:          hero,
+		mapEngine:     mapEngine,
+		createMonster: createNPCMonster,
+	}
+
+	if err := term.BindAction("teleport", "teleport the player to the given tile: teleport <x> <y>",
+		commands.teleport); err != nil {
+		return nil, err
+	}
+
+	if err := term.BindAction("spawn", "spawn monsters around the player: spawn <monsterId> [count]",
+		commands.spawn); err != nil {
+		return nil, err
+	}
+
+	if err := term.BindAction("path",
+		"walk an entity to the given tile, fractions select a sub-tile: path <entityId> <x> <y> | path clear <entityId>",
+		commands.path); err != nil {
+		return nil, err
+	}
+
+	return commands, nil
+}
+
+// teleport instantly moves the hero to the given tile
+func (d *debugCommands) teleport(tileX, tileY int) {
+	mapSize := d.mapEngine.Size()
+
+	if tileX < 0 || tileY < 0 || tileX >= mapSize.Width || tileY >= mapSize.Height {
+		d.term.OutputErrorf("tile (%d, %d) is outside the map, which is %dx%d tiles",
+			tileX, tileY, mapSize.Width, mapSize.Height)
+
+		return
+	}
+
+	d.hero.Teleport(tileX*5, tileY*5)
+	d.term.OutputInfof("teleported to tile (%d, %d)", tileX, tileY)
+}
+
+// spawn creates count monsters with the given monstats.txt id, spread in a circle around the hero
+func (d *debugCommands) spawn(monsterID string, count ...int) {
+	monstat := d2datadict.GetMonStatsByID(monsterID)
+	if monstat == nil {
+		d.term.OutputErrorf("unknown monster id: %s", monsterID)
+		return
+	}
+
+	spawnCount := 1
+	if len(count) > 0 {
+		spawnCount = count[0]
+	}
+
+	if spawnCount < 1 {
+		d.term.OutputErrorf("invalid count %d, must be at least 1", spawnCount)
+		return
+	}
+
+	if spawnCount > maxSpawnCount {
+		d.term.OutputWarningf("count %d is too large, spawning %d", spawnCount, maxSpawnCount)
+		spawnCount = maxSpawnCount
+	}
+
+	for idx := 0; idx < spawnCount; idx++ {
+		angle := 2 * math.Pi * float64(idx) / float64(spawnCount)
+		x := int(d.hero.LocationX + spawnRadius*math.Cos(angle))
+		y := int(d.hero.LocationY + spawnRadius*math.Sin(angle))
+
+		monster, err := d.createMonster(x, y, monstat)
+		if err != nil {
+			d.term.OutputErrorf("failed to spawn %s: %v", monsterID, err)
+			return
+		}
+
+		d.mapEngine.AddEntity(monster)
+	}
+
+	d.term.OutputInfof("spawned %d %s", spawnCount, monsterID)
+}
+
+// path finds a path for an entity to the given tile and sets it once found, or clears the entity's path. The target
+// is in tiles like entity positions, with fractions of a tile selecting a sub-tile.
+func (d *debugCommands) path(args ...string) {
+	if len(args) == 2 && args[0] == "clear" {
+		d.clearPath(args[1])
+		return
+	}
+
+	if len(args) != 3 {
+		d.term.OutputErrorf("usage: path <entityId> <x> <y> | path clear <entityId>")
+		return
+	}
+
+	entity := d.findPathEntity(args[0])
+	if entity == nil {
+		return
+	}
+
+	targetX, errX := strconv.ParseFloat(args[1], 64)
+	targetY, errY := strconv.ParseFloat(args[2], 64)
+
+	if errX != nil || errY != nil {
+		d.term.OutputErrorf("invalid target (%s, %s)", args[1], args[2])
+		return
+	}
+
+	d.cancelPendingPath()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	startX, startY := entity.GetPositionF()
+
+	d.pendingPath = &pathRequest{
+		entity:  entity,
+		targetX: targetX,
+		targetY: targetY,
+		results: d.mapEngine.FindPathAsync(ctx, startX, startY, targetX, targetY,
+			d2astar.Options{MaxCost: pathMaxCost, MaxIterations: pathMaxIterations}),
+		cancel: cancel,
+	}
+}
+
+// clearPath stops the entity with the given id from walking its path
+func (d *debugCommands) clearPath(id string) {
+	entity := d.findPathEntity(id)
+	if entity == nil {
+		return
+	}
+
+	if d.pendingPath != nil && d.pendingPath.entity == entity {
+		d.cancelPendingPath()
+	}
+
+	entity.ClearPath()
+	d.term.OutputInfof("cleared the path of entity %d", entity.ID())
+}
+
+// findPathEntity returns the entity with the given id, printing an error if there is no such entity or it can't walk
+func (d *debugCommands) findPathEntity(id string) pathEntity {
+	entityID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		d.term.OutputErrorf("invalid entity id: %s", id)
+		return nil
+	}
+
+	entity := d.mapEngine.GetEntityByID(entityID)
+	if entity == nil {
+		d.term.OutputErrorf("no entity with id %d", entityID)
+		return nil
+	}
+
+	walker, ok := entity.(pathEntity)
+	if !ok {
+		d.term.OutputErrorf("entity %d can't walk a path", entityID)
+		return nil
+	}
+
+	return walker
+}
+
+// cancelPendingPath stops the path search started by the path command, if there is one
+func (d *debugCommands) cancelPendingPath() {
+	if d.pendingPath == nil {
+		return
+	}
+
+	d.pendingPath.cancel()
+	d.pendingPath = nil
+}
+
+// advance applies the result of the path search started by the path command once it has finished
+func (d *debugCommands) advance() {
+	if d.pendingPath == nil {
+		return
+	}
+
+	select {
+	case result := <-d.pendingPath.results:
+		request := d.pendingPath
+		d.cancelPendingPath()
+		d.applyPath(request, result)
+	default:
+	}
+}
+
+// applyPath sets the path found for a path command on its entity and prints the path nodes
+func (d *debugCommands) applyPath(request *pathRequest, result d2astar.PathResult) {
+	entityID := request.entity.ID()
+
+	if result.Err != nil {
+		d.term.OutputErrorf("no path for entity %d to (%g, %g): %v", entityID, request.targetX, request.targetY, result.Err)
+		return
+	}
+
+	if len(result.Path) == 0 || (!result.Found && !result.Truncated) {
+		d.term.OutputWarningf("path for entity %d to (%g, %g) is empty", entityID, request.targetX, request.targetY)
+		return
+	}
+
+	if result.Truncated {
+		d.term.OutputWarningf("path for entity %d to (%g, %g) was truncated", entityID, request.targetX, request.targetY)
+	}
+
+	request.entity.SetPath(result.Path, nil)
+	d.term.OutputInfof("path for entity %d has %d nodes: %s", entityID, len(result.Path), formatPath(result.Path))
+}
+
+// formatPath returns the positions of the path nodes, in tiles
+func formatPath(path []d2astar.Pather) string {
+	nodes := make([]string, 0, len(path))
+
+	for _, node := range path {
+		if tile, ok := node.(*d2common.PathTile); ok {
+			nodes = append(nodes, fmt.Sprintf("(%g, %g)", tile.X, tile.Y))
+		}
+	}
+
+	return strings.Join(nodes, " ")
+}
+
+// createNPCMonster creates a monster as an NPC entity
+func createNPCMonster(x, y int, monstat *d2datadict.MonStatsRecord) (d2interface.MapEntity, error) {
+	if d2datadict.MonStats2[monstat.ExtraDataKey] == nil {
+		return nil, errNoMonStats2
+	}
+
+	return d2mapentity.CreateNPC(x, y, monstat, 0), nil
+}
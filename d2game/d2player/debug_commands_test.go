@@ -0,0 +1,260 @@
+package d2player
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2astar"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2data/d2datadict"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2fileformats/d2ds1"
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// testTerminal records bound actions and output
+type testTerminal struct {
+	actions  map[string]interface{}
+	infos    []string
+	warnings []string
+	errors   []string
+}
+
+func newTestTerminal() *testTerminal {
+	return &testTerminal{actions: make(map[string]interface{})}
+}
+
+func (t *testTerminal) BindLogger()                                         {}
+func (t *testTerminal) Advance(elapsed float64) error                       { return nil }
+func (t *testTerminal) OnKeyDown(event d2interface.KeyEvent) bool           { return false }
+func (t *testTerminal) OnKeyChars(event d2interface.KeyCharsEvent) bool     { return false }
+func (t *testTerminal) Render(surface d2interface.Surface) error            { return nil }
+func (t *testTerminal) Execute(command string) error                        { return nil }
+func (t *testTerminal) OutputRaw(text string, category d2enum.TermCategory) {}
+func (t *testTerminal) Outputf(format string, params ...interface{})        {}
+func (t *testTerminal) OutputClear()                                        {}
+func (t *testTerminal) IsVisible() bool                                     { return false }
+func (t *testTerminal) Hide()                                               {}
+func (t *testTerminal) Show()                                               {}
+
+func (t *testTerminal) OutputInfof(format string, params ...interface{}) {
+	t.infos = append(t.infos, fmt.Sprintf(format, params...))
+}
+
+func (t *testTerminal) OutputWarningf(format string, params ...interface{}) {
+	t.warnings = append(t.warnings, fmt.Sprintf(format, params...))
+}
+
+func (t *testTerminal) OutputErrorf(format string, params ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, params...))
+}
+
+func (t *testTerminal) BindAction(name, description string, action interface{}) error {
+	t.actions[name] = action
+	return nil
+}
+
+func (t *testTerminal) UnbindAction(name string) error {
+	delete(t.actions, name)
+	return nil
+}
+
+// newTestMapEngine creates a map engine with an empty map of the given size
+func newTestMapEngine(width, height int) *d2mapengine.MapEngine {
+	d2datadict.LevelTypes = []d2datadict.LevelTypeRecord{{}}
+
+	mapEngine := d2mapengine.CreateMapEngine()
+	mapEngine.ResetMap(0, width, height)
+
+	return mapEngine
+}
+
+func TestDebugCommandTeleport(t *testing.T) {
+	term := newTestTerminal()
+	hero := &d2mapentity.Player{}
+
+	if _, err := bindDebugCommands(term, hero, newTestMapEngine(10, 8)); err != nil {
+		t.Fatal(err)
+	}
+
+	teleport, ok := term.actions["teleport"].(func(int, int))
+	if !ok {
+		t.Fatal("teleport command was not bound")
+	}
+
+	teleport(4, 6)
+
+	if hero.TileX != 4 || hero.TileY != 6 {
+		t.Errorf("valid teleport: wanted tile (4, 6): got (%d, %d)", hero.TileX, hero.TileY)
+	}
+
+	for _, tile := range [][2]int{{10, 0}, {0, 8}, {-1, 3}} {
+		teleport(tile[0], tile[1])
+
+		if hero.TileX != 4 || hero.TileY != 6 {
+			t.Errorf("teleport to %v outside the map: wanted tile (4, 6): got (%d, %d)", tile, hero.TileX, hero.TileY)
+		}
+	}
+
+	if len(term.errors) != 3 {
+		t.Errorf("errors printed: wanted 3: got %v", term.errors)
+	}
+}
+
+func TestDebugCommandSpawn(t *testing.T) {
+	d2datadict.MonStats = map[string]*d2datadict.MonStatsRecord{
+		"zombie1": {Key: "zombie1"},
+	}
+
+	term := newTestTerminal()
+	mapEngine := newTestMapEngine(20, 20)
+
+	hero := &d2mapentity.Player{}
+	hero.Teleport(50, 50)
+
+	commands, err := bindDebugCommands(term, hero, mapEngine)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commands.createMonster = func(x, y int, _ *d2datadict.MonStatsRecord) (d2interface.MapEntity, error) {
+		monster := &d2mapentity.NPC{}
+		monster.Teleport(x, y)
+
+		return monster, nil
+	}
+
+	spawn, ok := term.actions["spawn"].(func(string, ...int))
+	if !ok {
+		t.Fatal("spawn command was not bound")
+	}
+
+	spawn("zombie1", 3)
+
+	if got := len(*mapEngine.Entities()); got != 3 {
+		t.Errorf("entities after spawning 3: wanted 3: got %d", got)
+	}
+
+	spawn("nosuchmonster", 3)
+
+	if got := len(*mapEngine.Entities()); got != 3 {
+		t.Errorf("entities after spawning an unknown id: wanted 3: got %d", got)
+	}
+
+	if len(term.errors) != 1 {
+		t.Errorf("errors printed: wanted 1: got %v", term.errors)
+	}
+
+	spawn("zombie1", maxSpawnCount+10)
+
+	if got := len(*mapEngine.Entities()); got != 3+maxSpawnCount {
+		t.Errorf("entities after spawning too many: wanted %d: got %d", 3+maxSpawnCount, got)
+	}
+}
+
+// testPathEntity is a map entity which records the path it is told to walk
+type testPathEntity struct {
+	id   uint64
+	x, y float64
+	path []d2astar.Pather
+}
+
+func (e *testPathEntity) Render(target d2interface.Surface)       {}
+func (e *testPathEntity) Advance(tickTime float64)                {}
+func (e *testPathEntity) GetPosition() (float64, float64)         { return e.x, e.y }
+func (e *testPathEntity) GetLayer() int                           { return 0 }
+func (e *testPathEntity) GetPositionF() (float64, float64)        { return e.x, e.y }
+func (e *testPathEntity) Name() string                            { return "" }
+func (e *testPathEntity) Selectable() bool                        { return false }
+func (e *testPathEntity) Highlight()                              {}
+func (e *testPathEntity) ID() uint64                              { return e.id }
+func (e *testPathEntity) SetPath(path []d2astar.Pather, _ func()) { e.path = path }
+func (e *testPathEntity) ClearPath()                              { e.path = nil }
+
+// newTestFloorMapEngine creates a map engine where every tile has a floor, generating the walk mesh if walkable is
+// true. Otherwise every sub-tile is left blocked.
+func newTestFloorMapEngine(width, height int, walkable bool) *d2mapengine.MapEngine {
+	mapEngine := newTestMapEngine(width, height)
+
+	tiles := *mapEngine.Tiles()
+	for idx := range tiles {
+		tiles[idx].Floors = []d2ds1.FloorShadowRecord{{}}
+	}
+
+	if walkable {
+		mapEngine.RegenerateWalkPaths()
+	}
+
+	return mapEngine
+}
+
+// runPathCommand runs the path command and waits for the path search it started, if any, to be applied
+func runPathCommand(t *testing.T, commands *debugCommands, args ...string) {
+	path, ok := commands.term.(*testTerminal).actions["path"].(func(...string))
+	if !ok {
+		t.Fatal("path command was not bound")
+	}
+
+	path(args...)
+
+	request := commands.pendingPath
+	if request == nil {
+		return
+	}
+
+	select {
+	case result := <-request.results:
+		commands.cancelPendingPath()
+		commands.applyPath(request, result)
+	case <-time.After(time.Second):
+		t.Fatal("path search did not finish")
+	}
+}
+
+func TestDebugCommandPath(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		walkable bool
+		wantPath bool
+	}{
+		{"reachable target", true, true},
+		{"blocked target", false, false},
+	} {
+		term := newTestTerminal()
+		mapEngine := newTestFloorMapEngine(10, 10, test.walkable)
+
+		entity := &testPathEntity{id: 7, x: 1, y: 1}
+		mapEngine.AddEntity(entity)
+
+		commands, err := bindDebugCommands(term, &d2mapentity.Player{}, mapEngine)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runPathCommand(t, commands, "7", "6", "4")
+
+		if gotPath := len(entity.path) > 0; gotPath != test.wantPath {
+			t.Errorf("%s: wanted path set %v: got %v", test.name, test.wantPath, entity.path)
+		}
+
+		if test.wantPath {
+			end, ok := entity.path[len(entity.path)-1].(*d2common.PathTile)
+			if !ok || end.X != 6 || end.Y != 4 {
+				t.Errorf("%s: wanted path to end at tile (6, 4): got %v", test.name, entity.path[len(entity.path)-1])
+			}
+		}
+
+		if gotEmpty := len(term.warnings) == 1; gotEmpty == test.wantPath {
+			t.Errorf("%s: wanted empty path reported %v: got %v", test.name, !test.wantPath, term.warnings)
+		}
+
+		runPathCommand(t, commands, "clear", "7")
+
+		if len(entity.path) != 0 {
+			t.Errorf("%s: wanted path cleared: got %v", test.name, entity.path)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"image/color"
 	"log"
 	"math"
+	"os"
+	"path"
 	"time"
 
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
@@ -15,6 +17,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2resource"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2asset"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2automap"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2maprenderer"
@@ -35,6 +38,9 @@ var staminaBarWidth = 102.0
 var globeHeight = 80
 var globeWidth = 80
 
+// NPCs further than this many tiles from the hero are not shown on the automap.
+var automapBlipRange = 30.0
+
 var leftMenuRect = d2common.Rectangle{Left: 0, Top: 0, Width: 400, Height: 600}
 var rightMenuRect = d2common.Rectangle{Left: 400, Top: 0, Width: 400, Height: 600}
 var bottomMenuRect = d2common.Rectangle{Left: 0, Top: 550, Width: 800, Height: 50}
@@ -46,7 +52,10 @@ type GameControls struct {
 	mapRenderer    *d2maprenderer.MapRenderer
 	inventory      *Inventory
 	heroStatsPanel *HeroStatsPanel
+	automap        *d2automap.Automap
+	automapKey     string
 	inputListener  InputCallbackListener
+	debugCommands  *debugCommands
 	FreeCam        bool
 	lastMouseX     int
 	lastMouseY     int
@@ -120,6 +129,10 @@ func NewGameControls(renderer d2interface.Renderer, hero *d2mapentity.Player, ma
 	
 	inventoryRecord := d2datadict.Inventory[inventoryRecordKey]
 
+	mapSize := mapEngine.Size()
+	automap := d2automap.NewAutomap(mapSize.Width, mapSize.Height, mapEngine.IsTileWalkable)
+	hero.SetOnTileEnter(automap.Visit)
+
 	gc := &GameControls{
 		renderer:       renderer,
 		hero:           hero,
@@ -128,6 +141,7 @@ func NewGameControls(renderer d2interface.Renderer, hero *d2mapentity.Player, ma
 		mapRenderer:    mapRenderer,
 		inventory:      NewInventory(inventoryRecord),
 		heroStatsPanel: NewHeroStatsPanel(renderer, hero.Name(), hero.Class, hero.Stats),
+		automap:        automap,
 		nameLabel:      &nameLabel,
 		zoneChangeText: &zoneLabel,
 		actionableRegions: []ActionableRegion{
@@ -142,10 +156,19 @@ func NewGameControls(renderer d2interface.Renderer, hero *d2mapentity.Player, ma
 		},
 	}
 
+	gc.loadAutomap()
+
 	term.BindAction("freecam", "toggle free camera movement", func() {
 		gc.FreeCam = !gc.FreeCam
 	})
 
+	debugCommands, err := bindDebugCommands(term, hero, mapEngine)
+	if err != nil {
+		log.Printf("failed to bind debug commands: %v", err)
+	}
+
+	gc.debugCommands = debugCommands
+
 	return gc
 }
 
@@ -189,24 +212,73 @@ func (g *GameControls) OnKeyDown(event d2interface.KeyEvent) bool {
 			g.updateLayout()
 			break
 		}
-	case d2enum.KeyI:
+	default:
+		return false
+	}
+	return false
+}
+
+// OnInputAction handles the actions bound to keys in the key bindings
+func (g *GameControls) OnInputAction(event d2interface.InputActionEvent) bool {
+	switch event.Action() {
+	case d2enum.InputActionToggleInventory:
 		g.inventory.Toggle()
 		g.updateLayout()
-	case d2enum.KeyC:
+	case d2enum.InputActionToggleCharacter:
 		g.heroStatsPanel.Toggle()
 		g.updateLayout()
-	case d2enum.KeyR:
+	case d2enum.InputActionToggleRun:
 		g.onToggleRunButton()
+	case d2enum.InputActionToggleAutomap:
+		g.automap.Toggle()
 	default:
 		return false
 	}
-	return false
+
+	return true
 }
 
 var lastLeftBtnActionTime float64 = 0
 var lastRightBtnActionTime float64 = 0
 var mouseBtnActionsTreshhold = 0.25
 
+// Gamepad stick movement targets a point this many tiles ahead of the hero, at most every gamepadMoveThreshold seconds
+var lastGamepadMoveTime float64 = 0
+var gamepadMoveThreshold = 0.1
+var gamepadMoveDistance = 2.0
+
+// OnGamepadStick moves the hero in the direction the left stick is held
+func (g *GameControls) OnGamepadStick(event d2interface.GamepadStickEvent) bool {
+	if event.Stick() != d2enum.GamepadStickLeft {
+		return false
+	}
+
+	now := d2common.Now()
+	if now-lastGamepadMoveTime < gamepadMoveThreshold {
+		return true
+	}
+
+	lastGamepadMoveTime = now
+
+	// The stick is in screen space, the isometric world is rotated 45 degrees and squashed vertically
+	screenX, screenY := event.DirectionX(), event.DirectionY()
+	worldX := screenX + 2*screenY
+	worldY := 2*screenY - screenX
+
+	length := math.Hypot(worldX, worldY)
+	if length == 0 {
+		return true
+	}
+
+	distance := gamepadMoveDistance * math.Hypot(screenX, screenY) / length
+	targetX := g.hero.LocationX/5 + worldX*distance
+	targetY := g.hero.LocationY/5 + worldY*distance
+
+	g.inputListener.OnPlayerMove(targetX, targetY)
+
+	return true
+}
+
 func (g *GameControls) OnMouseButtonRepeat(event d2interface.MouseEvent) bool {
 	px, py := g.mapRenderer.ScreenToWorld(event.X(), event.Y())
 	px = float64(int(px*10)) / 10.0
@@ -323,6 +395,10 @@ func (g *GameControls) onToggleRunButton() {
 
 // ScreenAdvanceHandler
 func (g *GameControls) Advance(elapsed float64) error {
+	if g.debugCommands != nil {
+		g.debugCommands.advance()
+	}
+
 	return nil
 }
 
@@ -387,6 +463,7 @@ func (g *GameControls) Render(target d2interface.Surface) {
 		}
 	}
 
+	g.renderAutomap(target)
 	g.inventory.Render(target)
 	g.heroStatsPanel.Render(target)
 
@@ -500,6 +577,76 @@ func (g *GameControls) Render(target d2interface.Surface) {
 
 }
 
+// ResetAutomap saves the explored area and restores the exploration of the new area, for when the map has been
+// regenerated.
+func (g *GameControls) ResetAutomap() {
+	g.SaveAutomap()
+
+	mapSize := g.mapEngine.Size()
+	g.automap.Reset(mapSize.Width, mapSize.Height)
+	g.loadAutomap()
+}
+
+// SaveAutomap saves the explored area of the current map.
+func (g *GameControls) SaveAutomap() {
+	if g.automapKey == "" {
+		return
+	}
+
+	if err := g.automap.SaveExplored(g.automapDir(), g.automapKey); err != nil {
+		log.Printf("failed to save the automap for %s: %v", g.automapKey, err)
+	}
+}
+
+// loadAutomap restores the explored area of the current map, if it was explored before.
+func (g *GameControls) loadAutomap() {
+	levelType := g.mapEngine.LevelType()
+
+	g.automapKey = ""
+	if levelType.ID != 0 {
+		g.automapKey = d2automap.AreaKey(levelType.Act, levelType.ID)
+
+		err := g.automap.LoadExplored(g.automapDir(), g.automapKey)
+		if err != nil && !os.IsNotExist(err) {
+			log.Printf("failed to load the automap for %s: %v", g.automapKey, err)
+		}
+	}
+
+	g.automap.Visit(g.hero.TileX, g.hero.TileY)
+}
+
+// automapDir returns the directory the explored areas of the hero are saved in.
+func (g *GameControls) automapDir() string {
+	basePath, _ := getGameBaseSavePath()
+
+	return path.Join(basePath, "Automap", g.hero.Name())
+}
+
+func (g *GameControls) renderAutomap(target d2interface.Surface) {
+	if !g.automap.IsVisible() {
+		return
+	}
+
+	heroX, heroY := g.hero.GetPositionF()
+	blips := []d2automap.Blip{{X: heroX, Y: heroY, Color: d2automap.PlayerBlipColor}}
+
+	for _, entity := range *g.mapEngine.Entities() {
+		npc, ok := entity.(*d2mapentity.NPC)
+		if !ok {
+			continue
+		}
+
+		npcX, npcY := npc.GetPositionF()
+		if math.Hypot(npcX-heroX, npcY-heroY) > automapBlipRange {
+			continue
+		}
+
+		blips = append(blips, d2automap.Blip{X: npcX, Y: npcY, Color: d2automap.NPCBlipColor})
+	}
+
+	g.automap.Render(target, heroX, heroY, blips)
+}
+
 func (g *GameControls) SetZoneChangeText(text string) {
 	g.zoneChangeText.SetText(text)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2enum"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2hero"
 	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2inventory"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
 )
 
 type PlayerState struct {
@@ -25,6 +26,7 @@ type PlayerState struct {
 	Stats     *d2hero.HeroStatsState          `json:"stats"`
 	X         float64                        `json:"x"`
 	Y         float64                        `json:"y"`
+	Position  *d2mapentity.EntityPosition    `json:"position,omitempty"`
 }
 
 func HasGameStates() bool {
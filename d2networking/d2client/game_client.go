@@ -65,6 +65,9 @@ func (g *GameClient) Open(connectionString string, saveFilePath string) error {
 	case d2clientconnectiontype.LANServer, d2clientconnectiontype.Local:
 		g.scriptEngine.AllowEval()
 	}
+
+	g.GameState = d2player.LoadPlayerState(saveFilePath)
+
 	return g.clientConnection.Open(connectionString, saveFilePath)
 }
 
@@ -103,6 +106,12 @@ func (g *GameClient) OnPacketReceived(packet d2netpacket.NetPacket) error {
 	case d2netpackettype.AddPlayer:
 		player := packet.PacketData.(d2netpacket.AddPlayerPacket)
 		newPlayer := d2mapentity.CreatePlayer(player.Id, player.Name, player.X, player.Y, 0, player.HeroType, player.Stats, player.Equipment)
+		newPlayer.SetLocallyControlled(newPlayer.Id == g.PlayerId)
+
+		if newPlayer.Id == g.PlayerId && g.GameState != nil && g.GameState.Position != nil {
+			newPlayer.RestorePosition(*g.GameState.Position)
+		}
+
 		g.Players[newPlayer.Id] = newPlayer
 		g.MapEngine.AddEntity(newPlayer)
 	case d2netpackettype.MovePlayer:
@@ -132,7 +141,7 @@ func (g *GameClient) OnPacketReceived(packet d2netpacket.NetPacket) error {
 		playerCast := packet.PacketData.(d2netpacket.CastPacket)
 		player := g.Players[playerCast.SourceEntityID]
 		player.SetCasting()
-		player.ClearPath()
+		player.StopMovement()
 		// currently hardcoded to missile skill
 		missile, err := d2mapentity.CreateMissile(
 			int(player.LocationX),
@@ -150,9 +159,13 @@ func (g *GameClient) OnPacketReceived(packet d2netpacket.NetPacket) error {
 			playerCast.TargetY*5,
 		)
 
-		missile.SetRadians(rads, func() {
+		missile.SetOnImpact(func(_, _ int) {
+			g.MapEngine.RemoveEntity(missile)
+		})
+		missile.SetOnExpire(func() {
 			g.MapEngine.RemoveEntity(missile)
 		})
+		missile.SetRadians(rads)
 
 		g.MapEngine.AddEntity(missile)
 	case d2netpackettype.Ping:
@@ -0,0 +1,173 @@
+package d2netpacket
+
+import (
+	"errors"
+	"math"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common"
+)
+
+// EntityState is the state of an entity which is kept in sync between the server and clients.
+type EntityState struct {
+	X             float64
+	Y             float64
+	Direction     int
+	AnimationMode int
+	Speed         float64
+}
+
+// entityField is a bit in the mask which prefixes an encoded entity delta, set for every field included in the delta.
+type entityField byte
+
+const (
+	entityFieldX entityField = 1 << iota
+	entityFieldY
+	entityFieldDirection
+	entityFieldAnimationMode
+	entityFieldSpeed
+
+	entityFieldAll = entityFieldX | entityFieldY | entityFieldDirection | entityFieldAnimationMode | entityFieldSpeed
+)
+
+// ErrInvalidEntityDelta is returned when decoding a truncated or malformed entity delta.
+var ErrInvalidEntityDelta = errors.New("invalid entity delta")
+
+// ErrMissingEntityBaseline is returned when decoding a partial entity delta without a previous state to apply it to.
+var ErrMissingEntityBaseline = errors.New("entity delta has no previous state to apply to")
+
+// EncodeEntityDelta encodes the fields of current which differ from previous. If previous is nil every field is
+// encoded, so the first snapshot of an entity can be decoded on its own. An unchanged state encodes to a single byte.
+func EncodeEntityDelta(previous *EntityState, current EntityState) []byte {
+	fields := entityFieldAll
+
+	if previous != nil {
+		fields = changedEntityFields(previous, &current)
+	}
+
+	sw := d2common.CreateStreamWriter()
+	sw.PushByte(byte(fields))
+
+	if fields&entityFieldX != 0 {
+		sw.PushUint64(math.Float64bits(current.X))
+	}
+
+	if fields&entityFieldY != 0 {
+		sw.PushUint64(math.Float64bits(current.Y))
+	}
+
+	if fields&entityFieldDirection != 0 {
+		sw.PushInt16(int16(current.Direction))
+	}
+
+	if fields&entityFieldAnimationMode != 0 {
+		sw.PushInt16(int16(current.AnimationMode))
+	}
+
+	if fields&entityFieldSpeed != 0 {
+		sw.PushUint64(math.Float64bits(current.Speed))
+	}
+
+	return sw.GetBytes()
+}
+
+// DecodeEntityDelta applies an entity delta created by EncodeEntityDelta to the previous state and returns the
+// result. previous may only be nil if the delta contains every field.
+func DecodeEntityDelta(previous *EntityState, data []byte) (EntityState, error) {
+	if len(data) == 0 {
+		return EntityState{}, ErrInvalidEntityDelta
+	}
+
+	fields := entityField(data[0])
+
+	if fields&^entityFieldAll != 0 || len(data) != entityDeltaSize(fields) {
+		return EntityState{}, ErrInvalidEntityDelta
+	}
+
+	var state EntityState
+
+	if previous != nil {
+		state = *previous
+	} else if fields != entityFieldAll {
+		return EntityState{}, ErrMissingEntityBaseline
+	}
+
+	sr := d2common.CreateStreamReader(data)
+	sr.SkipBytes(1)
+
+	if fields&entityFieldX != 0 {
+		state.X = math.Float64frombits(sr.GetUint64())
+	}
+
+	if fields&entityFieldY != 0 {
+		state.Y = math.Float64frombits(sr.GetUint64())
+	}
+
+	if fields&entityFieldDirection != 0 {
+		state.Direction = int(sr.GetInt16())
+	}
+
+	if fields&entityFieldAnimationMode != 0 {
+		state.AnimationMode = int(sr.GetInt16())
+	}
+
+	if fields&entityFieldSpeed != 0 {
+		state.Speed = math.Float64frombits(sr.GetUint64())
+	}
+
+	return state, nil
+}
+
+// changedEntityFields returns the mask of fields which differ between the two states.
+func changedEntityFields(previous, current *EntityState) entityField {
+	var fields entityField
+
+	if previous.X != current.X {
+		fields |= entityFieldX
+	}
+
+	if previous.Y != current.Y {
+		fields |= entityFieldY
+	}
+
+	if previous.Direction != current.Direction {
+		fields |= entityFieldDirection
+	}
+
+	if previous.AnimationMode != current.AnimationMode {
+		fields |= entityFieldAnimationMode
+	}
+
+	if previous.Speed != current.Speed {
+		fields |= entityFieldSpeed
+	}
+
+	return fields
+}
+
+// entityDeltaSize returns the size, in bytes, of an encoded delta containing the given fields.
+func entityDeltaSize(fields entityField) int {
+	const (
+		maskSize  = 1
+		floatSize = 8
+		shortSize = 2
+	)
+
+	size := maskSize
+
+	for _, field := range []struct {
+		field entityField
+		size  int
+	}{
+		{entityFieldX, floatSize},
+		{entityFieldY, floatSize},
+		{entityFieldDirection, shortSize},
+		{entityFieldAnimationMode, shortSize},
+		{entityFieldSpeed, floatSize},
+	} {
+		if fields&field.field != 0 {
+			size += field.size
+		}
+	}
+
+	return size
+}
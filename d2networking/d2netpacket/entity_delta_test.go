@@ -0,0 +1,63 @@
+package d2netpacket
+
+import "testing"
+
+func TestEntityDeltaRoundTrip(t *testing.T) {
+	states := []EntityState{
+		{X: 12.5, Y: -3.25, Direction: 7, AnimationMode: 2, Speed: 6},
+		{X: 13, Y: -3.25, Direction: 7, AnimationMode: 2, Speed: 6},
+		{X: 13, Y: -3.25, Direction: 12, AnimationMode: 3, Speed: 9},
+	}
+
+	var sent, received *EntityState
+
+	for idx := range states {
+		data := EncodeEntityDelta(sent, states[idx])
+
+		decoded, err := DecodeEntityDelta(received, data)
+		if err != nil {
+			t.Fatalf("state %d: %v", idx, err)
+		}
+
+		if decoded != states[idx] {
+			t.Errorf("state %d: wanted %+v: got %+v", idx, states[idx], decoded)
+		}
+
+		sent, received = &states[idx], &decoded
+	}
+
+	if full, delta := EncodeEntityDelta(nil, states[1]), EncodeEntityDelta(&states[0], states[1]); len(delta) >= len(full) {
+		t.Errorf("delta of one field: wanted fewer than %d bytes: got %d", len(full), len(delta))
+	}
+}
+
+func TestEntityDeltaUnchanged(t *testing.T) {
+	state := EntityState{X: 1, Y: 2, Direction: 3, AnimationMode: 4, Speed: 5}
+
+	data := EncodeEntityDelta(&state, state)
+	if len(data) != 1 {
+		t.Errorf("unchanged delta size: wanted 1: got %d", len(data))
+	}
+
+	decoded, err := DecodeEntityDelta(&state, data)
+	if err != nil || decoded != state {
+		t.Errorf("unchanged delta: wanted %+v: got %+v, %v", state, decoded, err)
+	}
+}
+
+func TestEntityDeltaInvalid(t *testing.T) {
+	previous := EntityState{X: 1}
+	partial := EncodeEntityDelta(&previous, EntityState{X: 2})
+
+	if _, err := DecodeEntityDelta(nil, partial); err != ErrMissingEntityBaseline {
+		t.Errorf("partial delta without a previous state: wanted %v: got %v", ErrMissingEntityBaseline, err)
+	}
+
+	if _, err := DecodeEntityDelta(&previous, partial[:len(partial)-1]); err != ErrInvalidEntityDelta {
+		t.Errorf("truncated delta: wanted %v: got %v", ErrInvalidEntityDelta, err)
+	}
+
+	if _, err := DecodeEntityDelta(&previous, nil); err != ErrInvalidEntityDelta {
+		t.Errorf("empty delta: wanted %v: got %v", ErrInvalidEntityDelta, err)
+	}
+}
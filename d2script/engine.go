@@ -12,8 +12,10 @@ import (
 
 // ScriptEngine allows running JavaScript scripts
 type ScriptEngine struct {
-	vm            *otto.Otto
-	isEvalAllowed bool
+	vm             *otto.Otto
+	isEvalAllowed  bool
+	callbacks      map[uint64]*scriptCallback
+	lastCallbackID uint64
 }
 
 // CreateScriptEngine creates the script engine and returns a pointer to it.
@@ -29,6 +31,7 @@ func CreateScriptEngine() *ScriptEngine {
 	return &ScriptEngine{
 		vm:            vm,
 		isEvalAllowed: false,
+		callbacks:     make(map[uint64]*scriptCallback),
 	}
 }
 
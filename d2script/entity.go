@@ -0,0 +1,190 @@
+package d2script
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/robertkrimen/otto"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2common/d2interface"
+)
+
+// ScriptEntity is a map entity which can be handed to scripts
+type ScriptEntity interface {
+	ID() uint64
+	GetPositionF() (float64, float64)
+	Name() string
+	Selectable() bool
+}
+
+// EntityQuerier finds the entities on a map, it is implemented by the map engine
+type EntityQuerier interface {
+	GetEntityByID(id uint64) d2interface.MapEntity
+	GetEntitiesInRadius(x, y, radius float64) []d2interface.MapEntity
+}
+
+// ErrNotAFunction is returned when binding a script value which can't be called
+var ErrNotAFunction = errors.New("script value is not a function")
+
+// scriptCallback is a script function bound to an entity, waiting to be called from the game
+type scriptCallback struct {
+	function otto.Value
+	entity   otto.Value
+}
+
+// PathDone returns a function to pass as done to SetPath, which calls the named script function with the entity
+// when the entity reaches the end of its path. For example, a map script can start a dialog when the player arrives.
+func (s *ScriptEngine) PathDone(entity ScriptEntity, functionName string) (func(), error) {
+	function, err := s.vm.Get(functionName)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.BindPathDone(entity, function)
+}
+
+// BindPathDone returns a function to pass as done to SetPath, which calls the given script function with the entity
+// when the entity reaches the end of its path. The returned function only refers to the callback by a handle, so
+// once ReleaseCallbacks is called the script function and entity can be collected, and the function does nothing.
+func (s *ScriptEngine) BindPathDone(entity ScriptEntity, function otto.Value) (func(), error) {
+	if !function.IsFunction() {
+		return nil, ErrNotAFunction
+	}
+
+	entityValue, err := s.entityValue(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	s.lastCallbackID++
+	handle := s.lastCallbackID
+	s.callbacks[handle] = &scriptCallback{function: function, entity: entityValue}
+
+	return func() {
+		s.runCallback(handle)
+	}, nil
+}
+
+// BindEntityQueries adds the GetEntityByID(id) and GetEntitiesInRadius(x, y, radius) functions, which let scripts
+// find the entities on the given map. Positions and the radius are in sub tiles. Entities are returned as frozen
+// objects with the same accessors as the entities passed to path done callbacks, GetEntityByID returns null if there
+// is no such entity.
+func (s *ScriptEngine) BindEntityQueries(entities EntityQuerier) {
+	s.AddFunction("GetEntityByID", func(call otto.FunctionCall) otto.Value {
+		id, err := call.Argument(0).ToInteger()
+		if err != nil || id < 0 {
+			return otto.NullValue()
+		}
+
+		entity, ok := entities.GetEntityByID(uint64(id)).(ScriptEntity)
+		if !ok {
+			return otto.NullValue()
+		}
+
+		value, err := s.entityValue(entity)
+		if err != nil {
+			return otto.NullValue()
+		}
+
+		return value
+	})
+
+	s.AddFunction("GetEntitiesInRadius", func(call otto.FunctionCall) otto.Value {
+		array, err := s.vm.Object("[]")
+		if err != nil {
+			return otto.UndefinedValue()
+		}
+
+		x, errX := call.Argument(0).ToFloat()
+		y, errY := call.Argument(1).ToFloat()
+		radius, errRadius := call.Argument(2).ToFloat()
+
+		if errX != nil || errY != nil || errRadius != nil {
+			return array.Value()
+		}
+
+		for _, mapEntity := range entities.GetEntitiesInRadius(x, y, radius) {
+			// entities without an ID can't be looked up again, so they aren't handed to scripts
+			entity, ok := mapEntity.(ScriptEntity)
+			if !ok {
+				continue
+			}
+
+			value, err := s.entityValue(entity)
+			if err != nil {
+				continue
+			}
+
+			_, _ = array.Call("push", value)
+		}
+
+		return array.Value()
+	})
+}
+
+// ReleaseCallbacks drops every callback bound to a script function, for when the scripts are unloaded
+func (s *ScriptEngine) ReleaseCallbacks() {
+	s.callbacks = make(map[uint64]*scriptCallback)
+}
+
+// runCallback calls the script function bound to the handle. A path is only completed once, so the callback is
+// released when it runs.
+func (s *ScriptEngine) runCallback(handle uint64) {
+	callback, found := s.callbacks[handle]
+	if !found {
+		return
+	}
+
+	delete(s.callbacks, handle)
+
+	if _, err := callback.function.Call(otto.UndefinedValue(), callback.entity); err != nil {
+		fmt.Printf("Error running script callback: %s\n", err.Error())
+	}
+}
+
+// entityValue creates the script object representing an entity. The object is frozen and only holds accessors which
+// copy values out of the entity, so scripts can't change the entity through it.
+func (s *ScriptEngine) entityValue(entity ScriptEntity) (otto.Value, error) {
+	object, err := s.vm.Object("({})")
+	if err != nil {
+		return otto.UndefinedValue(), err
+	}
+
+	properties := map[string]interface{}{
+		"id": float64(entity.ID()),
+		"getPosition": func(call otto.FunctionCall) otto.Value {
+			x, y := entity.GetPositionF()
+			return s.objectValue(map[string]interface{}{"x": x, "y": y})
+		},
+		"getName": func(call otto.FunctionCall) otto.Value {
+			value, _ := s.vm.ToValue(entity.Name())
+			return value
+		},
+		"isSelectable": func(call otto.FunctionCall) otto.Value {
+			value, _ := s.vm.ToValue(entity.Selectable())
+			return value
+		},
+	}
+
+	for name, value := range properties {
+		if err := object.Set(name, value); err != nil {
+			return otto.UndefinedValue(), err
+		}
+	}
+
+	return s.vm.Call("Object.freeze", nil, object)
+}
+
+// objectValue creates a script object holding a copy of the given properties
+func (s *ScriptEngine) objectValue(properties map[string]interface{}) otto.Value {
+	object, err := s.vm.Object("({})")
+	if err != nil {
+		return otto.UndefinedValue()
+	}
+
+	for name, value := range properties {
+		_ = object.Set(name, value)
+	}
+
+	return object.Value()
+}
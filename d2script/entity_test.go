@@ -0,0 +1,109 @@
+package d2script
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapengine"
+	"github.com/OpenDiablo2/OpenDiablo2/d2core/d2map/d2mapentity"
+)
+
+// testScriptEntity is an entity handed to scripts, at a fixed position
+type testScriptEntity struct {
+	id   uint64
+	x, y float64
+}
+
+func (e *testScriptEntity) ID() uint64                       { return e.id }
+func (e *testScriptEntity) GetPositionF() (float64, float64) { return e.x, e.y }
+func (e *testScriptEntity) Name() string                     { return "" }
+func (e *testScriptEntity) Selectable() bool                 { return false }
+
+func TestPathDone(t *testing.T) {
+	engine := CreateScriptEngine()
+	engine.AllowEval()
+
+	if _, err := engine.Eval(`var arrivals = 0, arrivedID = -1;
+		function onArrive(entity) { arrivals++; arrivedID = entity.id; }`); err != nil {
+		t.Fatal(err)
+	}
+
+	entity := &testScriptEntity{id: 42, x: 2, y: 0}
+
+	done, err := engine.PathDone(entity, "onArrive")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the entity reaches the end of its path
+	done()
+
+	if got, _ := engine.Eval("arrivals"); got != "1" {
+		t.Errorf("script callback calls: wanted %d: got %s", 1, got)
+	}
+
+	if got, _ := engine.Eval("arrivedID"); got != strconv.FormatUint(entity.ID(), 10) {
+		t.Errorf("script callback entity: wanted %d: got %s", entity.ID(), got)
+	}
+
+	// callbacks bound before the script is unloaded never run
+	done, err = engine.PathDone(entity, "onArrive")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine.ReleaseCallbacks()
+	done()
+
+	if got, _ := engine.Eval("arrivals"); got != "1" {
+		t.Errorf("script callback calls after releasing: wanted %d: got %s", 1, got)
+	}
+
+	if _, err := engine.PathDone(entity, "arrivals"); err != ErrNotAFunction {
+		t.Errorf("binding a variable: wanted %v: got %v", ErrNotAFunction, err)
+	}
+}
+
+func TestBindEntityQueries(t *testing.T) {
+	mapEngine := d2mapengine.CreateMapEngine()
+
+	// sub tile positions (10.2, 10.2), (12.2, 10.2) and (30.2, 30.2)
+	near := d2mapentity.CreateAnimatedEntity(50, 50, nil)
+	nearby := d2mapentity.CreateAnimatedEntity(60, 50, nil)
+	far := d2mapentity.CreateAnimatedEntity(150, 150, nil)
+
+	mapEngine.AddEntity(near)
+	mapEngine.AddEntity(nearby)
+	mapEngine.AddEntity(far)
+
+	engine := CreateScriptEngine()
+	engine.AllowEval()
+	engine.BindEntityQueries(mapEngine)
+
+	got, err := engine.Eval(`GetEntitiesInRadius(10, 10, 3).map(function(entity) { return entity.id; }).join(",")`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := fmt.Sprintf("%d,%d", near.ID(), nearby.ID()); got != want {
+		t.Errorf("entities in radius: wanted %s: got %s", want, got)
+	}
+
+	got, err = engine.Eval(fmt.Sprintf(`var entity = GetEntityByID(%d);
+		entity.id = 0;
+		entity.getPosition = null;
+		entity.id + ":" + entity.getPosition().x + ":" + entity.isSelectable()`, far.ID()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, _ := far.GetPositionF()
+	if want := fmt.Sprintf("%d:%v:false", far.ID(), x); got != want {
+		t.Errorf("entity by ID after the script changed it: wanted %s: got %s", want, got)
+	}
+
+	if got, _ := engine.Eval(`GetEntityByID(-1) === null && GetEntityByID(999999) === null`); got != "true" {
+		t.Errorf("missing entity: wanted %s: got %s", "true", got)
+	}
+}